@@ -4,13 +4,12 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
 )
 
 // ChangeFailureRate retrieves the change failure rate of a Dataflow.
-func ChangeFailureRate(c *gin.Context) {
+func (h *Handler) ChangeFailureRate(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.MetricsRequest
@@ -20,12 +19,12 @@ func ChangeFailureRate(c *gin.Context) {
 	}
 
 	var dataflow models.Dataflow
-	err = daos.GetDataflow(ctx, request.DataflowID, &dataflow)
+	err = h.Store.GetDataflow(ctx, request.DataflowID, &dataflow)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 	}
 
-	changeFailureRate, err := metrics.ChangeFailureRate(ctx, dataflow.ID, request.StartDate, request.EndDate, request.Window)
+	changeFailureRate, err := metrics.ChangeFailureRate(ctx, h.Store, dataflow.ID, request.StartDate, request.EndDate, request.Window, request.Granularity, request.Timezone)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 	}
@@ -34,7 +33,7 @@ func ChangeFailureRate(c *gin.Context) {
 }
 
 // GeneralChangeFailureRate retrieves the change failure rate of a Dataflow.
-func GeneralChangeFailureRate(c *gin.Context) {
+func (h *Handler) GeneralChangeFailureRate(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.GeneralMetricsRequest
@@ -43,7 +42,7 @@ func GeneralChangeFailureRate(c *gin.Context) {
 		c.AbortWithError(http.StatusBadRequest, err)
 	}
 
-	changeFailureRate, err := metrics.GeneralChangeFailureRate(ctx, request.StartDate, request.EndDate, request.Window)
+	changeFailureRate, err := metrics.GeneralChangeFailureRate(ctx, h.Store, request.StartDate, request.EndDate, request.Window, request.Granularity)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 	}
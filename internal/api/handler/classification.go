@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/classification"
+)
+
+// Classification retrieves the DORA performance tiers of a Dataflow.
+func (h *Handler) Classification(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request models.ClassificationRequest
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	var dataflow models.Dataflow
+	err = h.Store.GetDataflow(ctx, request.DataflowID, &dataflow)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	thresholds := models.DefaultTierThresholds()
+	if request.Thresholds != nil {
+		thresholds = classification.WithDefaults(*request.Thresholds)
+	}
+
+	tiers, err := classification.Classify(ctx, h.Store, dataflow.ID, request.StartDate, request.EndDate, thresholds, request.Timezone)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tiers)
+}
+
+// GeneralClassification retrieves the general DORA performance tiers over all Dataflows.
+func (h *Handler) GeneralClassification(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request models.GeneralClassificationRequest
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	thresholds := models.DefaultTierThresholds()
+	if request.Thresholds != nil {
+		thresholds = classification.WithDefaults(*request.Thresholds)
+	}
+
+	tiers, err := classification.GeneralClassify(ctx, h.Store, request.StartDate, request.EndDate, thresholds)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tiers)
+}
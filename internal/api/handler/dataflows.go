@@ -1,17 +1,20 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/connectors/prometheus"
 	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/jobs"
 	"github.com/unnmdnwb3/dora/internal/services/trigger"
+	"github.com/unnmdnwb3/dora/internal/utils/times"
 	"github.com/unnmdnwb3/dora/internal/utils/types"
 )
 
 // CreateDataflow creates a new Dataflow.
-func CreateDataflow(c *gin.Context) {
+func (h *Handler) CreateDataflow(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var dataflow models.Dataflow
@@ -21,24 +24,84 @@ func CreateDataflow(c *gin.Context) {
 		return
 	}
 
-	err = daos.CreateDataflow(ctx, &dataflow)
+	err = validateDataflow(&dataflow)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
 
-	err = trigger.OnNewDataflow(ctx, &dataflow)
+	// store the operator, even if a legacy relation was given
+	dataflow.Deployment.Relation = models.DeploymentRelation(dataflow.Deployment.Relation)
+
+	err = h.Store.CreateDataflow(ctx, &dataflow)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dataflow)
+	job, err := jobs.Submit(ctx, h.Store, &dataflow)
+	if err != nil {
+		// a Dataflow without a Job would never be onboarded, hence it is created again by a retry
+		deleteErr := h.Store.DeleteDataflow(ctx, dataflow.ID)
+		if deleteErr != nil {
+			c.AbortWithError(http.StatusInternalServerError, deleteErr)
+			return
+		}
+	}
+	if err == jobs.ErrQueueFull {
+		c.AbortWithError(http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.DataflowJobResponse{Dataflow: dataflow, JobID: job.ID.Hex()})
 	return
 }
 
+// validateDataflow returns an error if a Dataflow to be created or updated has an invalid setting.
+func validateDataflow(dataflow *models.Dataflow) error {
+	if dataflow.BackfillDays < 0 {
+		return fmt.Errorf("backfill_days must not be negative")
+	}
+
+	if dataflow.SyncInterval < 0 {
+		return fmt.Errorf("sync_interval must not be negative")
+	}
+
+	switch dataflow.LeadTimeStrategy {
+	case "", models.LeadTimeCommits, models.LeadTimeMergeRequests:
+	default:
+		return fmt.Errorf("unsupported lead_time_strategy: %s", dataflow.LeadTimeStrategy)
+	}
+
+	err := prometheus.ValidateRelation(dataflow.Deployment.Relation)
+	if err != nil {
+		return err
+	}
+
+	if dataflow.Deployment.Step < 0 {
+		return fmt.Errorf("step must not be negative")
+	}
+
+	err = prometheus.ValidateQuery(dataflow.Deployment.Query)
+	if err != nil {
+		return err
+	}
+
+	deployment := dataflow.Deployment
+	if deployment.IncidentGap < 0 || deployment.TailPadding < 0 || deployment.MinIncidentDuration < 0 || deployment.MinAlerts < 0 {
+		return fmt.Errorf("incident_gap, tail_padding, min_incident_duration and min_alerts must not be negative")
+	}
+
+	_, err = times.Location(dataflow.Timezone)
+	return err
+}
+
 // GetDataflow retrieves a Dataflow.
-func GetDataflow(c *gin.Context) {
+func (h *Handler) GetDataflow(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var params models.Params
@@ -55,7 +118,7 @@ func GetDataflow(c *gin.Context) {
 		return
 	}
 
-	err = daos.GetDataflow(ctx, dataflowID, &dataflow)
+	err = h.Store.GetDataflow(ctx, dataflowID, &dataflow)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -66,11 +129,11 @@ func GetDataflow(c *gin.Context) {
 }
 
 // ListDataflows retrieves many Dataflows.
-func ListDataflows(c *gin.Context) {
+func (h *Handler) ListDataflows(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var dataflows []models.Dataflow
-	err := daos.ListDataflows(ctx, &dataflows)
+	err := h.Store.ListDataflows(ctx, &dataflows)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -81,7 +144,7 @@ func ListDataflows(c *gin.Context) {
 }
 
 // UpdateDataflow update a Dataflow.
-func UpdateDataflow(c *gin.Context) {
+func (h *Handler) UpdateDataflow(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var params models.Params
@@ -104,19 +167,97 @@ func UpdateDataflow(c *gin.Context) {
 		return
 	}
 
-	err = daos.UpdateDataflow(ctx, dataflowID, &dataflow)
+	err = validateDataflow(&dataflow)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// store the operator, even if a legacy relation was given
+	dataflow.Deployment.Relation = models.DeploymentRelation(dataflow.Deployment.Relation)
+
+	err = trigger.OnUpdate(ctx, h.Store, dataflowID, &dataflow)
+	if err == trigger.ErrSyncRunning {
+		c.AbortWithError(http.StatusConflict, err)
+		return
+	}
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
 
-	dataflow.ID = dataflowID
 	c.JSON(http.StatusOK, dataflow)
 	return
 }
 
+// SyncDataflow queues a Job syncing a Dataflow with all data newer than its high-water marks.
+func (h *Handler) SyncDataflow(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var params models.Params
+	err := c.BindUri(&params)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	dataflowID, err := types.StringToObjectID(params.ID)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	var dataflow models.Dataflow
+	err = h.Store.GetDataflow(ctx, dataflowID, &dataflow)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := jobs.Submit(ctx, h.Store, &dataflow)
+	if err == jobs.ErrQueueFull {
+		c.AbortWithError(http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.DataflowJobResponse{Dataflow: dataflow, JobID: job.ID.Hex()})
+	return
+}
+
+// GetSyncState retrieves the SyncState of a Dataflow, including the time and error of its last run.
+func (h *Handler) GetSyncState(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var params models.Params
+	err := c.BindUri(&params)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	dataflowID, err := types.StringToObjectID(params.ID)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	var syncState models.SyncState
+	err = h.Store.GetSyncState(ctx, dataflowID, &syncState)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, syncState)
+	return
+}
+
 // DeleteDataflow deletes a Dataflow.
-func DeleteDataflow(c *gin.Context) {
+func (h *Handler) DeleteDataflow(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var params models.Params
@@ -132,7 +273,7 @@ func DeleteDataflow(c *gin.Context) {
 		return
 	}
 
-	err = daos.DeleteDataflow(ctx, dataflowID)
+	err = h.Store.DeleteDataflow(ctx, dataflowID)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -4,13 +4,12 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
 )
 
 // DeploymentFrequency retrieves the deployment frequency of a Dataflow.
-func DeploymentFrequency(c *gin.Context) {
+func (h *Handler) DeploymentFrequency(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.MetricsRequest
@@ -21,13 +20,13 @@ func DeploymentFrequency(c *gin.Context) {
 	}
 
 	var dataflow models.Dataflow
-	err = daos.GetDataflow(ctx, request.DataflowID, &dataflow)
+	err = h.Store.GetDataflow(ctx, request.DataflowID, &dataflow)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
 
-	deploymentFrequency, err := metrics.DeploymentFrequency(ctx, dataflow.ID, request.StartDate, request.EndDate, request.Window)
+	deploymentFrequency, err := metrics.DeploymentFrequency(ctx, h.Store, dataflow.ID, request.StartDate, request.EndDate, request.Window, request.Granularity, request.Timezone)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -38,7 +37,7 @@ func DeploymentFrequency(c *gin.Context) {
 }
 
 // GeneralDeploymentFrequency retrieves the general deployment frequency.
-func GeneralDeploymentFrequency(c *gin.Context) {
+func (h *Handler) GeneralDeploymentFrequency(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.GeneralMetricsRequest
@@ -48,7 +47,7 @@ func GeneralDeploymentFrequency(c *gin.Context) {
 		return
 	}
 
-	deploymentFrequency, err := metrics.GeneralDeploymentFrequency(ctx, request.StartDate, request.EndDate, request.Window)
+	deploymentFrequency, err := metrics.GeneralDeploymentFrequency(ctx, h.Store, request.StartDate, request.EndDate, request.Window, request.Granularity)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
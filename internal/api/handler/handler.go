@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/unnmdnwb3/dora/internal/daos"
+)
+
+// Handler serves the API routes, reading and writing the documents of a Store.
+type Handler struct {
+	Store *daos.Store
+}
+
+// NewHandler creates a new Handler for a Store.
+func NewHandler(store *daos.Store) *Handler {
+	return &Handler{
+		Store: store,
+	}
+}
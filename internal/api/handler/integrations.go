@@ -4,13 +4,13 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/connectors"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/utils/types"
 )
 
 // CreateIntegration creates a new Integration.
-func CreateIntegration(c *gin.Context) {
+func (h *Handler) CreateIntegration(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var integration models.Integration
@@ -20,7 +20,16 @@ func CreateIntegration(c *gin.Context) {
 		return
 	}
 
-	err = daos.CreateIntegration(ctx, &integration)
+	err = connectors.Validate(&integration)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// store the current type, even if the legacy one was given
+	integration.Type = models.IntegrationType(integration.Type)
+
+	err = h.Store.CreateIntegration(ctx, &integration)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -31,7 +40,7 @@ func CreateIntegration(c *gin.Context) {
 }
 
 // GetIntegration retrieves a Integration.
-func GetIntegration(c *gin.Context) {
+func (h *Handler) GetIntegration(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var params models.Params
@@ -48,7 +57,7 @@ func GetIntegration(c *gin.Context) {
 		return
 	}
 
-	err = daos.GetIntegration(ctx, integrationID, &integration)
+	err = h.Store.GetIntegration(ctx, integrationID, &integration)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -59,11 +68,11 @@ func GetIntegration(c *gin.Context) {
 }
 
 // ListIntegrations retrieves many Integrations.
-func ListIntegrations(c *gin.Context) {
+func (h *Handler) ListIntegrations(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var integrations []models.Integration
-	err := daos.ListIntegrations(ctx, &integrations)
+	err := h.Store.ListIntegrations(ctx, &integrations)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -74,7 +83,7 @@ func ListIntegrations(c *gin.Context) {
 }
 
 // UpdateIntegration update a Integration.
-func UpdateIntegration(c *gin.Context) {
+func (h *Handler) UpdateIntegration(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var params models.Params
@@ -97,7 +106,16 @@ func UpdateIntegration(c *gin.Context) {
 		return
 	}
 
-	err = daos.UpdateIntegration(ctx, integrationID, &integration)
+	err = connectors.Validate(&integration)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// store the current type, even if the legacy one was given
+	integration.Type = models.IntegrationType(integration.Type)
+
+	err = h.Store.UpdateIntegration(ctx, integrationID, &integration)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -108,7 +126,7 @@ func UpdateIntegration(c *gin.Context) {
 }
 
 // DeleteIntegration deletes a Integration.
-func DeleteIntegration(c *gin.Context) {
+func (h *Handler) DeleteIntegration(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var params models.Params
@@ -124,7 +142,7 @@ func DeleteIntegration(c *gin.Context) {
 		return
 	}
 
-	err = daos.DeleteIntegration(ctx, integrationID)
+	err = h.Store.DeleteIntegration(ctx, integrationID)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
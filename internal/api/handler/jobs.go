@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/utils/types"
+)
+
+// GetJob retrieves a Job, including its phase, progress and error.
+func (h *Handler) GetJob(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var params models.Params
+	err := c.BindUri(&params)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	jobID, err := types.StringToObjectID(params.ID)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	var job models.Job
+	err = h.Store.GetJob(ctx, jobID, &job)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+	return
+}
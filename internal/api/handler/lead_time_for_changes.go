@@ -4,13 +4,12 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
 )
 
 // LeadTimeForChanges retrieves the lead time for changes of a Dataflow.
-func LeadTimeForChanges(c *gin.Context) {
+func (h *Handler) LeadTimeForChanges(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.MetricsRequest
@@ -21,13 +20,13 @@ func LeadTimeForChanges(c *gin.Context) {
 	}
 
 	var dataflow models.Dataflow
-	err = daos.GetDataflow(ctx, request.DataflowID, &dataflow)
+	err = h.Store.GetDataflow(ctx, request.DataflowID, &dataflow)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
 
-	leadTimeForChanges, err := metrics.LeadTimeForChanges(ctx, dataflow.ID, request.StartDate, request.EndDate, request.Window)
+	leadTimeForChanges, err := metrics.LeadTimeForChanges(ctx, h.Store, dataflow.ID, request.StartDate, request.EndDate, request.Window, request.Statistic, request.Granularity, request.Timezone)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -38,7 +37,7 @@ func LeadTimeForChanges(c *gin.Context) {
 }
 
 // GeneralLeadTimeForChanges retrieves the lead time for changes of a Dataflow.
-func GeneralLeadTimeForChanges(c *gin.Context) {
+func (h *Handler) GeneralLeadTimeForChanges(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.GeneralMetricsRequest
@@ -48,7 +47,7 @@ func GeneralLeadTimeForChanges(c *gin.Context) {
 		return
 	}
 
-	leadTimeForChanges, err := metrics.GeneralLeadTimeForChanges(ctx, request.StartDate, request.EndDate, request.Window)
+	leadTimeForChanges, err := metrics.GeneralLeadTimeForChanges(ctx, h.Store, request.StartDate, request.EndDate, request.Window, request.Statistic, request.Granularity)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
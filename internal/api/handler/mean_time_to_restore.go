@@ -4,13 +4,12 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
 )
 
 // MeanTimeToRestore retrieves the mean time to restore of a Dataflow.
-func MeanTimeToRestore(c *gin.Context) {
+func (h *Handler) MeanTimeToRestore(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.MetricsRequest
@@ -21,13 +20,13 @@ func MeanTimeToRestore(c *gin.Context) {
 	}
 
 	var dataflow models.Dataflow
-	err = daos.GetDataflow(ctx, request.DataflowID, &dataflow)
+	err = h.Store.GetDataflow(ctx, request.DataflowID, &dataflow)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
 
-	meanTimeToRestore, err := metrics.MeanTimeToRestore(ctx, dataflow.ID, request.StartDate, request.EndDate, request.Window)
+	meanTimeToRestore, err := metrics.MeanTimeToRestore(ctx, h.Store, dataflow.ID, request.StartDate, request.EndDate, request.Window, request.Statistic, request.Granularity, request.Timezone)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -37,7 +36,7 @@ func MeanTimeToRestore(c *gin.Context) {
 }
 
 // GeneralMeanTimeToRestore retrieves the mean time to restore of a Dataflow.
-func GeneralMeanTimeToRestore(c *gin.Context) {
+func (h *Handler) GeneralMeanTimeToRestore(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var request models.GeneralMetricsRequest
@@ -47,7 +46,7 @@ func GeneralMeanTimeToRestore(c *gin.Context) {
 		return
 	}
 
-	meanTimeToRestore, err := metrics.GeneralMeanTimeToRestore(ctx, request.StartDate, request.EndDate, request.Window)
+	meanTimeToRestore, err := metrics.GeneralMeanTimeToRestore(ctx, h.Store, request.StartDate, request.EndDate, request.Window, request.Statistic, request.Granularity)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
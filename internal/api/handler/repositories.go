@@ -4,17 +4,16 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/unnmdnwb3/dora/internal/connectors/gitlab"
-	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/connectors"
 	"github.com/unnmdnwb3/dora/internal/models"
 )
 
 // GetRepositories gets all repositories
-func GetRepositories(c *gin.Context) {
+func (h *Handler) GetRepositories(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var integrations []models.Integration
-	err := daos.ListIntegrations(ctx, &integrations)
+	err := h.Store.ListIntegrations(ctx, &integrations)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -22,10 +21,18 @@ func GetRepositories(c *gin.Context) {
 
 	allRepositories := []models.Repository{}
 	for _, integration := range integrations {
-		// TODO: currently only gitlab is supported
-		client := gitlab.NewClient(integration.URI, integration.BearerToken)
+		// integrations without version control, e.g. telemetry, have no repositories
+		if models.IntegrationType(integration.Type) != models.VersionControl {
+			continue
+		}
+
+		sourceControl, err := connectors.NewSourceControl(&integration)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
 
-		repositories, err := client.GetRepositories()
+		repositories, err := sourceControl.GetRepositories()
 		if err != nil {
 			c.AbortWithError(http.StatusBadRequest, err)
 			return
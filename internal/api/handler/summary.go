@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/metrics"
+)
+
+// Summary retrieves all four metrics of a Dataflow, or of all Dataflows if no Dataflow is given.
+func (h *Handler) Summary(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request models.SummaryRequest
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// the Dataflow is loaded by the Summary, which fails if the Dataflow can not be found
+	summary, err := metrics.Summary(ctx, h.Store, request.DataflowID, request.StartDate, request.EndDate, request.Window, request.Statistic, request.Granularity, request.Timezone)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Compare {
+		err = metrics.CompareSummary(ctx, h.Store, request.DataflowID, summary)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
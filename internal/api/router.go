@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/unnmdnwb3/dora/internal/api/handler"
+	"github.com/unnmdnwb3/dora/internal/daos"
 )
 
 // httpRequestsTotal is a prometheus counter for all HTTP requests
@@ -33,9 +34,10 @@ func prometheusMiddleware() gin.HandlerFunc {
 	}
 }
 
-// SetupRouter initializes the router and all routes to be served
-func SetupRouter() *gin.Engine {
+// SetupRouter initializes the router and all routes to be served from a Store
+func SetupRouter(store *daos.Store) *gin.Engine {
 	router := gin.Default()
+	h := handler.NewHandler(store)
 
 	// register prometheus metrics
 	registerMetrics()
@@ -45,33 +47,41 @@ func SetupRouter() *gin.Engine {
 	router.GET("/metrics", prometheusMiddleware(), gin.WrapH(promhttp.Handler()))
 
 	// routes for repositories
-	router.GET("/api/v1/repositories", prometheusMiddleware(), handler.GetRepositories)
+	router.GET("/api/v1/repositories", prometheusMiddleware(), h.GetRepositories)
 
 	// routes for integrations
-	router.POST("/api/v1/integrations", prometheusMiddleware(), handler.CreateIntegration)
-	router.GET("/api/v1/integrations", prometheusMiddleware(), handler.ListIntegrations)
-	router.GET("/api/v1/integrations/:id", prometheusMiddleware(), handler.GetIntegration)
-	router.PUT("/api/v1/integrations/:id", prometheusMiddleware(), handler.UpdateIntegration)
-	router.DELETE("/api/v1/integrations/:id", prometheusMiddleware(), handler.DeleteIntegration)
+	router.POST("/api/v1/integrations", prometheusMiddleware(), h.CreateIntegration)
+	router.GET("/api/v1/integrations", prometheusMiddleware(), h.ListIntegrations)
+	router.GET("/api/v1/integrations/:id", prometheusMiddleware(), h.GetIntegration)
+	router.PUT("/api/v1/integrations/:id", prometheusMiddleware(), h.UpdateIntegration)
+	router.DELETE("/api/v1/integrations/:id", prometheusMiddleware(), h.DeleteIntegration)
 
 	// routes for dataflows
-	router.POST("/api/v1/dataflows", prometheusMiddleware(), handler.CreateDataflow)
-	router.GET("/api/v1/dataflows", prometheusMiddleware(), handler.ListDataflows)
-	router.GET("/api/v1/dataflows/:id", prometheusMiddleware(), handler.GetDataflow)
-	router.PUT("/api/v1/dataflows/:id", prometheusMiddleware(), handler.UpdateDataflow)
-	router.DELETE("/api/v1/dataflows/:id", prometheusMiddleware(), handler.DeleteDataflow)
+	router.POST("/api/v1/dataflows", prometheusMiddleware(), h.CreateDataflow)
+	router.GET("/api/v1/dataflows", prometheusMiddleware(), h.ListDataflows)
+	router.GET("/api/v1/dataflows/:id", prometheusMiddleware(), h.GetDataflow)
+	router.PUT("/api/v1/dataflows/:id", prometheusMiddleware(), h.UpdateDataflow)
+	router.DELETE("/api/v1/dataflows/:id", prometheusMiddleware(), h.DeleteDataflow)
+	router.GET("/api/v1/dataflows/:id/sync", prometheusMiddleware(), h.GetSyncState)
+	router.POST("/api/v1/dataflows/:id/sync", prometheusMiddleware(), h.SyncDataflow)
+
+	// routes for jobs
+	router.GET("/api/v1/jobs/:id", prometheusMiddleware(), h.GetJob)
 
 	// routes for dataflow metrics
-	router.POST("/api/v1/metrics/deployment-frequency", prometheusMiddleware(), handler.DeploymentFrequency)
-	router.POST("/api/v1/metrics/lead-time-for-changes", prometheusMiddleware(), handler.LeadTimeForChanges)
-	router.POST("/api/v1/metrics/mean-time-to-restore", prometheusMiddleware(), handler.MeanTimeToRestore)
-	router.POST("/api/v1/metrics/change-failure-rate", prometheusMiddleware(), handler.ChangeFailureRate)
+	router.POST("/api/v1/metrics/deployment-frequency", prometheusMiddleware(), h.DeploymentFrequency)
+	router.POST("/api/v1/metrics/lead-time-for-changes", prometheusMiddleware(), h.LeadTimeForChanges)
+	router.POST("/api/v1/metrics/mean-time-to-restore", prometheusMiddleware(), h.MeanTimeToRestore)
+	router.POST("/api/v1/metrics/change-failure-rate", prometheusMiddleware(), h.ChangeFailureRate)
+	router.POST("/api/v1/metrics/classification", prometheusMiddleware(), h.Classification)
+	router.POST("/api/v1/metrics/summary", prometheusMiddleware(), h.Summary)
 
 	// routes for general dataflow metrics
-	router.POST("/api/v1/metrics/general/deployment-frequency", prometheusMiddleware(), handler.GeneralDeploymentFrequency)
-	router.POST("/api/v1/metrics/general/lead-time-for-changes", prometheusMiddleware(), handler.GeneralLeadTimeForChanges)
-	router.POST("/api/v1/metrics/general/mean-time-to-restore", prometheusMiddleware(), handler.GeneralMeanTimeToRestore)
-	router.POST("/api/v1/metrics/general/change-failure-rate", prometheusMiddleware(), handler.GeneralChangeFailureRate)
+	router.POST("/api/v1/metrics/general/deployment-frequency", prometheusMiddleware(), h.GeneralDeploymentFrequency)
+	router.POST("/api/v1/metrics/general/lead-time-for-changes", prometheusMiddleware(), h.GeneralLeadTimeForChanges)
+	router.POST("/api/v1/metrics/general/mean-time-to-restore", prometheusMiddleware(), h.GeneralMeanTimeToRestore)
+	router.POST("/api/v1/metrics/general/change-failure-rate", prometheusMiddleware(), h.GeneralChangeFailureRate)
+	router.POST("/api/v1/metrics/general/classification", prometheusMiddleware(), h.GeneralClassification)
 
 	return router
 }
@@ -0,0 +1,127 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/connectors/prometheus"
+	"github.com/unnmdnwb3/dora/internal/models"
+)
+
+// Client represents an Alertmanager API client.
+type Client struct {
+	URI  string
+	Auth string
+}
+
+// NewClient creates a new Alertmanager API client.
+func NewClient(URI string, auth string) *Client {
+	return &Client{
+		URI:  URI,
+		Auth: auth,
+	}
+}
+
+// Alert represents an alert of the Alertmanager v2 API.
+type Alert struct {
+	Fingerprint string            `json:"fingerprint"`
+	Labels      map[string]string `json:"labels"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"` // the time the alert expires unless it is sent again, if still firing
+	Status      struct {
+		State       string   `json:"state"` // one of unprocessed, active or suppressed
+		SilencedBy  []string `json:"silencedBy"`
+		InhibitedBy []string `json:"inhibitedBy"`
+	} `json:"status"`
+}
+
+// AlertSuppressed is the state of an alert silenced or inhibited, which is not notified.
+const AlertSuppressed = "suppressed"
+
+// Suppressed returns true if an alert is silenced or inhibited.
+func (a *Alert) Suppressed() bool {
+	return a.Status.State == AlertSuppressed || len(a.Status.SilencedBy) > 0 || len(a.Status.InhibitedBy) > 0
+}
+
+// GetAlerts gets all alerts matching every label matcher, e.g. alertname="TargetDown".
+// Alertmanager only holds the alerts still firing or resolved recently.
+// Failed requests are reported with the errors of the prometheus connector.
+func (c *Client) GetAlerts(matchers []string) (*[]Alert, error) {
+	client := &http.Client{}
+
+	uri := fmt.Sprintf("%s/api/v2/alerts", c.URI)
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, &prometheus.ConnectionError{URI: uri, Err: err}
+	}
+
+	bearer := fmt.Sprintf("Bearer %s", c.Auth)
+	req.Header.Add("Authorization", bearer)
+
+	q := req.URL.Query()
+	for _, matcher := range matchers {
+		q.Add("filter", matcher)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &prometheus.ConnectionError{URI: uri, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &prometheus.ConnectionError{URI: uri, Err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &prometheus.StatusError{URI: uri, StatusCode: resp.StatusCode}
+	}
+
+	var alerts []Alert
+	err = json.Unmarshal(body, &alerts)
+	if err != nil {
+		return nil, &prometheus.DecodeError{Err: err}
+	}
+
+	return &alerts, nil
+}
+
+// GetIncidents gets an incident for each alert matching every label matcher started since a given time.
+// An alert still firing is an incident lasting until now, and one whose endsAt has passed ends then.
+func (c *Client) GetIncidents(matchers []string, since time.Time) (*[]models.Incident, error) {
+	alerts, err := c.GetAlerts(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateIncidents(alerts, since, time.Now()), nil
+}
+
+// CreateIncidents creates Incidents from the alerts started since a given time, ending at the latest at a given time.
+// Silenced or inhibited alerts are no incidents.
+func CreateIncidents(alerts *[]Alert, since time.Time, until time.Time) *[]models.Incident {
+	incidents := []models.Incident{}
+	for _, alert := range *alerts {
+		if alert.StartsAt.Before(since) || alert.Suppressed() {
+			continue
+		}
+
+		endDate := alert.EndsAt
+		if endDate.IsZero() || endDate.After(until) {
+			endDate = until
+		}
+
+		incidents = append(incidents, models.Incident{
+			StartDate: alert.StartsAt,
+			EndDate:   endDate,
+			Series:    []map[string]string{alert.Labels},
+		})
+	}
+
+	return &incidents
+}
@@ -0,0 +1,124 @@
+package alertmanager_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/connectors/alertmanager"
+	"github.com/unnmdnwb3/dora/internal/connectors/prometheus"
+)
+
+func TestClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "alertmanager.Client Suite")
+}
+
+var _ = Describe("alertmanager.Client", func() {
+	var (
+		mock     *httptest.Server
+		filters  []string
+		matchers = []string{`alertname="TargetDown"`, `job="ak-core/log-processor-service"`}
+
+		client *alertmanager.Client
+	)
+
+	var _ = BeforeEach(func() {
+		alerts, _ := os.ReadFile("./../../../test/data/alertmanager/alerts.json")
+		mock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v2/alerts" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			filters = r.URL.Query()["filter"]
+			w.WriteHeader(http.StatusOK)
+			w.Write(alerts)
+		}))
+
+		client = alertmanager.NewClient(mock.URL, "")
+	})
+
+	var _ = AfterEach(func() {
+		defer mock.Close()
+	})
+
+	var _ = When("GetAlerts", func() {
+		It("gets all alerts matching the label matchers", func() {
+			alerts, err := client.GetAlerts(matchers)
+			Expect(err).To(BeNil())
+			Expect(filters).To(Equal(matchers))
+			Expect(len(*alerts)).To(Equal(2))
+			Expect((*alerts)[0].Labels["severity"]).To(Equal("warning"))
+			Expect((*alerts)[0].StartsAt).To(Equal(time.Date(2023, 1, 23, 15, 8, 46, 445000000, time.UTC)))
+		})
+
+		It("returns a StatusError for a non-2xx response", func() {
+			client = alertmanager.NewClient(mock.URL+"/unknown", "")
+
+			_, err := client.GetAlerts(matchers)
+			var statusError *prometheus.StatusError
+			Expect(errors.As(err, &statusError)).To(BeTrue())
+			Expect(statusError.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("returns a DecodeError for a malformed response", func() {
+			malformed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("{"))
+			}))
+			defer malformed.Close()
+
+			client = alertmanager.NewClient(malformed.URL, "")
+			_, err := client.GetAlerts(matchers)
+			var decodeError *prometheus.DecodeError
+			Expect(errors.As(err, &decodeError)).To(BeTrue())
+		})
+
+		It("returns a ConnectionError if alertmanager is down", func() {
+			mock.Close()
+
+			_, err := client.GetAlerts(matchers)
+			var connectionError *prometheus.ConnectionError
+			Expect(errors.As(err, &connectionError)).To(BeTrue())
+		})
+	})
+
+	var _ = When("CreateIncidents", func() {
+		It("creates an incident for each alert started since a given time, ending at the latest now", func() {
+			alerts, err := client.GetAlerts(matchers)
+			Expect(err).To(BeNil())
+
+			until := time.Date(2023, 1, 24, 10, 0, 0, 0, time.UTC)
+			incidents := alertmanager.CreateIncidents(alerts, time.Date(2023, 1, 23, 0, 0, 0, 0, time.UTC), until)
+			Expect(len(*incidents)).To(Equal(2))
+			Expect((*incidents)[0].EndDate).To(Equal(time.Date(2023, 1, 23, 15, 12, 46, 445000000, time.UTC)))
+			Expect((*incidents)[1].EndDate).To(Equal(until))
+			Expect((*incidents)[0].Series).To(Equal([]map[string]string{(*alerts)[0].Labels}))
+		})
+
+		It("drops alerts started before the given time", func() {
+			alerts, err := client.GetAlerts(matchers)
+			Expect(err).To(BeNil())
+
+			incidents := alertmanager.CreateIncidents(alerts, time.Date(2023, 1, 24, 0, 0, 0, 0, time.UTC), time.Now())
+			Expect(len(*incidents)).To(Equal(1))
+		})
+
+		It("drops silenced or inhibited alerts", func() {
+			alerts, err := client.GetAlerts(matchers)
+			Expect(err).To(BeNil())
+
+			(*alerts)[0].Status.State = alertmanager.AlertSuppressed
+			(*alerts)[1].Status.InhibitedBy = []string{"1d3e1b5b8c1b2f47"}
+
+			incidents := alertmanager.CreateIncidents(alerts, time.Date(2023, 1, 23, 0, 0, 0, 0, time.UTC), time.Now())
+			Expect(*incidents).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,159 @@
+package connectors
+
+import (
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/connectors/alertmanager"
+	"github.com/unnmdnwb3/dora/internal/connectors/github"
+	"github.com/unnmdnwb3/dora/internal/connectors/gitlab"
+	"github.com/unnmdnwb3/dora/internal/connectors/prometheus"
+	"github.com/unnmdnwb3/dora/internal/models"
+)
+
+// register the connectors shipped with dora
+func init() {
+	RegisterSourceControl("gitlab", func(integration *models.Integration) SourceControl {
+		return &gitlabConnector{client: gitlab.NewClient(integration.URI, integration.BearerToken)}
+	})
+	RegisterCIProvider("gitlab", func(integration *models.Integration) CIProvider {
+		return &gitlabConnector{client: gitlab.NewClient(integration.URI, integration.BearerToken)}
+	})
+
+	RegisterSourceControl("github", func(integration *models.Integration) SourceControl {
+		return &githubConnector{client: github.NewClient(integration.URI, integration.BearerToken)}
+	})
+	RegisterCIProvider("github", func(integration *models.Integration) CIProvider {
+		return &githubConnector{client: github.NewClient(integration.URI, integration.BearerToken)}
+	})
+
+	RegisterIncidentSource("prometheus", func(integration *models.Integration) IncidentSource {
+		return &prometheusConnector{uri: integration.URI, auth: integration.BearerToken}
+	})
+	RegisterIncidentSource("prometheus-alerts", func(integration *models.Integration) IncidentSource {
+		return &prometheusAlertsConnector{uri: integration.URI, auth: integration.BearerToken}
+	})
+	RegisterIncidentSource("alertmanager", func(integration *models.Integration) IncidentSource {
+		return &alertmanagerConnector{client: alertmanager.NewClient(integration.URI, integration.BearerToken)}
+	})
+}
+
+// gitlabConnector adapts a gitlab.Client, which identifies projects by their external ID.
+type gitlabConnector struct {
+	client *gitlab.Client
+}
+
+// GetRepositories gets all repositories readable with the bearer token provided.
+func (c *gitlabConnector) GetRepositories() (*[]models.Repository, error) {
+	return c.client.GetRepositories()
+}
+
+// GetCommits gets all commits on the default branch of a repository since a given time.
+func (c *gitlabConnector) GetCommits(repository *models.Repository, since time.Time) (*[]models.Commit, error) {
+	return c.client.GetCommits(repository.ExternalID, repository.DefaultBranch, since)
+}
+
+// GetPullRequests gets all merge requests into the default branch of a repository since a given time.
+func (c *gitlabConnector) GetPullRequests(repository *models.Repository, since time.Time) (*[]models.PullRequest, error) {
+	return c.client.GetPullRequests(repository.ExternalID, repository.DefaultBranch, since)
+}
+
+// GetPullRequestCommits gets all commits of a merge request.
+func (c *gitlabConnector) GetPullRequestCommits(repository *models.Repository, pullRequest *models.PullRequest) (*[]models.Commit, error) {
+	return c.client.GetPullRequestCommits(repository.ExternalID, pullRequest.IID)
+}
+
+// GetPipelineRuns gets all pipeline runs on the default branch of a pipeline since a given time.
+func (c *gitlabConnector) GetPipelineRuns(pipeline *models.Pipeline, since time.Time) (*[]models.PipelineRun, error) {
+	return c.client.GetPipelineRuns(pipeline.ExternalID, pipeline.DefaultBranch, since)
+}
+
+// githubConnector adapts a github.Client, which identifies repositories by their namespaced name.
+type githubConnector struct {
+	client *github.Client
+}
+
+// GetRepositories gets all repositories readable with the bearer token provided.
+func (c *githubConnector) GetRepositories() (*[]models.Repository, error) {
+	return c.client.GetRepositories()
+}
+
+// GetCommits gets all commits on the default branch of a repository since a given time.
+func (c *githubConnector) GetCommits(repository *models.Repository, since time.Time) (*[]models.Commit, error) {
+	return c.client.GetCommits(repository.NamespacedName, repository.DefaultBranch, since)
+}
+
+// GetPullRequests gets all pull requests into the default branch of a repository since a given time.
+func (c *githubConnector) GetPullRequests(repository *models.Repository, since time.Time) (*[]models.PullRequest, error) {
+	return c.client.GetPullRequests(repository.NamespacedName, repository.DefaultBranch, since)
+}
+
+// GetPullRequestCommits gets all commits of a pull request.
+func (c *githubConnector) GetPullRequestCommits(repository *models.Repository, pullRequest *models.PullRequest) (*[]models.Commit, error) {
+	return c.client.GetPullRequestCommits(repository.NamespacedName, pullRequest.IID)
+}
+
+// GetPipelineRuns gets all runs of the workflow of a pipeline on its default branch since a given time.
+func (c *githubConnector) GetPipelineRuns(pipeline *models.Pipeline, since time.Time) (*[]models.PipelineRun, error) {
+	return c.client.GetPipelineRuns(pipeline.NamespacedName, pipeline.ExternalID, pipeline.DefaultBranch, since)
+}
+
+// prometheusConnector creates a prometheus.Client for the query of each deployment.
+type prometheusConnector struct {
+	uri  string
+	auth string
+}
+
+// GetAlerts gets all alerts of a deployment since a given time.
+func (c *prometheusConnector) GetAlerts(deployment *models.Deployment, since time.Time) (*[]models.Alert, error) {
+	client := prometheus.NewClient(c.uri, c.auth, deployment)
+	return client.GetAlerts(since)
+}
+
+// prometheusAlertsConnector creates a prometheus.Client for the ALERTS series matching the label matchers of each deployment.
+type prometheusAlertsConnector struct {
+	uri  string
+	auth string
+}
+
+// client creates a prometheus.Client querying the ALERTS series firing for a deployment.
+func (c *prometheusAlertsConnector) client(deployment *models.Deployment) *prometheus.Client {
+	alertsDeployment := models.Deployment{
+		Query: prometheus.AlertsQuery(deployment.Matchers),
+		Step:  deployment.Step,
+	}
+	return prometheus.NewClient(c.uri, c.auth, &alertsDeployment)
+}
+
+// GetAlerts gets a sample of each alert firing for a deployment since a given time.
+func (c *prometheusAlertsConnector) GetAlerts(deployment *models.Deployment, since time.Time) (*[]models.Alert, error) {
+	return c.client(deployment).GetAlerts(since)
+}
+
+// GetIncidents gets an incident for each interval an alert was firing for a deployment since a given time.
+func (c *prometheusAlertsConnector) GetIncidents(deployment *models.Deployment, since time.Time) (*[]models.Incident, error) {
+	return c.client(deployment).GetIncidents(since)
+}
+
+// alertmanagerConnector adapts an alertmanager.Client, which filters the alerts of a deployment by its label matchers.
+type alertmanagerConnector struct {
+	client *alertmanager.Client
+}
+
+// GetAlerts gets the start of each alert of a deployment started since a given time.
+func (c *alertmanagerConnector) GetAlerts(deployment *models.Deployment, since time.Time) (*[]models.Alert, error) {
+	incidents, err := c.client.GetIncidents(deployment.Matchers, since)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []models.Alert{}
+	for _, incident := range *incidents {
+		alerts = append(alerts, models.Alert{CreatedAt: incident.StartDate})
+	}
+	return &alerts, nil
+}
+
+// GetIncidents gets an incident for each alert of a deployment started since a given time.
+func (c *alertmanagerConnector) GetIncidents(deployment *models.Deployment, since time.Time) (*[]models.Incident, error) {
+	return c.client.GetIncidents(deployment.Matchers, since)
+}
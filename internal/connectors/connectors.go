@@ -0,0 +1,31 @@
+package connectors
+
+import (
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+)
+
+// SourceControl provides the historical data of a version control system.
+type SourceControl interface {
+	GetRepositories() (*[]models.Repository, error)
+	GetCommits(repository *models.Repository, since time.Time) (*[]models.Commit, error)
+	GetPullRequests(repository *models.Repository, since time.Time) (*[]models.PullRequest, error)
+	GetPullRequestCommits(repository *models.Repository, pullRequest *models.PullRequest) (*[]models.Commit, error)
+}
+
+// CIProvider provides the historical data of a CI/CD system.
+type CIProvider interface {
+	GetPipelineRuns(pipeline *models.Pipeline, since time.Time) (*[]models.PipelineRun, error)
+}
+
+// IncidentSource provides the alerts of a deployment, from which incidents are derived.
+type IncidentSource interface {
+	GetAlerts(deployment *models.Deployment, since time.Time) (*[]models.Alert, error)
+}
+
+// IncidentProvider provides the incidents of a deployment with exact start and end times, e.g. from its alerting rules.
+// An IncidentSource implementing it is asked for incidents rather than deriving them from its alerts.
+type IncidentProvider interface {
+	GetIncidents(deployment *models.Deployment, since time.Time) (*[]models.Incident, error)
+}
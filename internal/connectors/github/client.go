@@ -0,0 +1,431 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/utils/times"
+)
+
+// DefaultMaxPages is the upper bound of pages fetched per resource, if not configured otherwise.
+// With 100 results per page, this allows 10'000 results per resource.
+const DefaultMaxPages = 100
+
+// Client represents a GitHub API client
+type Client struct {
+	URI      string
+	Auth     string
+	MaxPages int // upper bound of pages fetched per resource, so one huge repository can't starve the importer
+}
+
+// NewClient creates a new GitHub API client
+func NewClient(URI string, auth string) *Client {
+	return &Client{
+		URI:      URI,
+		Auth:     auth,
+		MaxPages: MaxPages(),
+	}
+}
+
+// MaxPages returns the upper bound of pages configured with GITHUB_MAX_PAGES, or DefaultMaxPages.
+func MaxPages() int {
+	maxPages, err := strconv.Atoi(os.Getenv("GITHUB_MAX_PAGES"))
+	if err != nil || maxPages < 1 {
+		return DefaultMaxPages
+	}
+	return maxPages
+}
+
+// organisation represents an organisation as returned by the GitHub API
+type organisation struct {
+	ID      int    `json:"id"`
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+}
+
+// repository represents a repository as returned by the GitHub API
+type repository struct {
+	ID            int    `json:"id"`
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// commit represents a commit as returned by the GitHub API
+type commit struct {
+	Sha    string `json:"sha"`
+	Commit struct {
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+	Parents []struct {
+		Sha string `json:"sha"`
+	} `json:"parents"`
+}
+
+// pullRequest represents a pull request as returned by the GitHub API
+type pullRequest struct {
+	ID        int        `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+	Base      struct {
+		Ref  string `json:"ref"`
+		Repo struct {
+			ID int `json:"id"`
+		} `json:"repo"`
+	} `json:"base"`
+	Head struct {
+		Ref string `json:"ref"`
+		Sha string `json:"sha"`
+	} `json:"head"`
+	MergeCommitSha string `json:"merge_commit_sha"`
+	HTMLURL        string `json:"html_url"`
+}
+
+// workflowRuns represents a list of workflow runs as returned by the GitHub API
+type workflowRuns struct {
+	TotalCount   int `json:"total_count"`
+	WorkflowRuns []struct {
+		ID         int       `json:"id"`
+		HeadSha    string    `json:"head_sha"`
+		HeadBranch string    `json:"head_branch"`
+		Conclusion string    `json:"conclusion"`
+		Event      string    `json:"event"`
+		CreatedAt  time.Time `json:"created_at"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		HTMLURL    string    `json:"html_url"`
+	} `json:"workflow_runs"`
+}
+
+// getAll gets all pages of a resource returning a list, following the Link header of the GitHub API until
+// either all results are fetched or the upper bound of pages is reached.
+func getAll[T any](c *Client, uri string, query map[string]string) (*[]T, error) {
+	results := []T{}
+	err := c.getPages(uri, query, func(body []byte) (bool, error) {
+		var pageResults []T
+		err := json.Unmarshal(body, &pageResults)
+		if err != nil {
+			return false, err
+		}
+
+		results = append(results, pageResults...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
+
+// getPages sends authenticated GET requests for each page of a resource and passes the body of each page to collect,
+// until either collect returns false, there is no next page or the upper bound of pages is reached.
+func (c *Client) getPages(uri string, query map[string]string, collect func(body []byte) (bool, error)) error {
+	client := &http.Client{}
+
+	maxPages := c.MaxPages
+	if maxPages < 1 {
+		maxPages = DefaultMaxPages
+	}
+
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	q := req.URL.Query()
+	for key, value := range query {
+		q.Add(key, value)
+	}
+	req.URL.RawQuery = q.Encode()
+	next := req.URL.String()
+
+	for page := 0; next != "" && page < maxPages; page++ {
+		req, err := http.NewRequest(http.MethodGet, next, nil)
+		if err != nil {
+			return err
+		}
+
+		bearer := fmt.Sprintf("Bearer %s", c.Auth)
+		req.Header.Add("Authorization", bearer)
+		req.Header.Add("Accept", "application/vnd.github+json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("github at %s responded with status %d: %s", uri, resp.StatusCode, body)
+		}
+
+		more, err := collect(body)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		next = NextPage(resp.Header)
+	}
+
+	if next != "" {
+		log.Printf("Stopped after %d pages of %s, there are more results", maxPages, uri)
+	}
+
+	return nil
+}
+
+// NextPage returns the URI of the next page provided in the Link header, or an empty string for the last page.
+func NextPage(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
+	}
+
+	return ""
+}
+
+// GetOrganisations gets all organisations readable with the bearer token provided
+func (c *Client) GetOrganisations() (*[]models.Organisation, error) {
+	uri := fmt.Sprintf("%s/user/orgs", c.URI)
+
+	response, err := getAll[organisation](c, uri, map[string]string{"per_page": "100"})
+	if err != nil {
+		return nil, err
+	}
+
+	organisations := []models.Organisation{}
+	for _, org := range *response {
+		organisations = append(organisations, models.Organisation{
+			ExternalID:     org.ID,
+			WebURL:         org.HTMLURL,
+			NamespacedName: org.Login,
+		})
+	}
+
+	return &organisations, nil
+}
+
+// GetRepositories gets all repositories readable with the bearer token provided
+func (c *Client) GetRepositories() (*[]models.Repository, error) {
+	uri := fmt.Sprintf("%s/user/repos", c.URI)
+	query := map[string]string{
+		"affiliation": "owner",
+		"per_page":    "100", // max
+	}
+
+	response, err := getAll[repository](c, uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	repositories := []models.Repository{}
+	for _, repo := range *response {
+		repositories = append(repositories, models.Repository{
+			ExternalID:     repo.ID,
+			NamespacedName: repo.FullName,
+			DefaultBranch:  repo.DefaultBranch,
+		})
+	}
+
+	return &repositories, nil
+}
+
+// GetPullRequests gets all pull requests of a repository merged since a given time
+func (c *Client) GetPullRequests(namespacedName string, targetBranch string, since time.Time) (*[]models.PullRequest, error) {
+	uri := fmt.Sprintf("%s/repos/%s/pulls", c.URI, namespacedName)
+	query := map[string]string{
+		"state":     "closed",
+		"base":      targetBranch,
+		"sort":      "updated",
+		"direction": "desc",
+		"per_page":  "100", // max
+	}
+
+	// the GitHub API does not support filtering pull requests by date, but a pull request merged since a given time
+	// was updated since then as well, so no later page can contain one once a page reaches older updates
+	response := []pullRequest{}
+	err := c.getPages(uri, query, func(body []byte) (bool, error) {
+		var pageResults []pullRequest
+		err := json.Unmarshal(body, &pageResults)
+		if err != nil {
+			return false, err
+		}
+
+		response = append(response, pageResults...)
+		return len(pageResults) > 0 && !pageResults[len(pageResults)-1].UpdatedAt.Before(since), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// closed pull requests also contain the ones which were never merged
+	pullRequests := []models.PullRequest{}
+	for _, pr := range response {
+		if pr.MergedAt == nil || pr.MergedAt.Before(since) {
+			continue
+		}
+
+		pullRequests = append(pullRequests, models.PullRequest{
+			ID:               pr.ID,
+			ProjectID:        pr.Base.Repo.ID,
+			Title:            pr.Title,
+			CreatedAt:        pr.CreatedAt,
+			UpdatedAt:        pr.UpdatedAt,
+			TargetBranch:     pr.Base.Ref,
+			SourceBranch:     pr.Head.Ref,
+			PreCommitTailSha: pr.Head.Sha,
+			MergeCommitSha:   pr.MergeCommitSha,
+			IID:              pr.Number,
+			Reference:        fmt.Sprintf("#%d", pr.Number),
+			WebURL:           pr.HTMLURL,
+		})
+	}
+
+	return &pullRequests, nil
+}
+
+// GetPullRequestCommits gets all commits of a merged pull request
+func (c *Client) GetPullRequestCommits(namespacedName string, pullRequestNumber int) (*[]models.Commit, error) {
+	uri := fmt.Sprintf("%s/repos/%s/pulls/%d/commits", c.URI, namespacedName, pullRequestNumber)
+	query := map[string]string{
+		"per_page": "100", // max
+	}
+
+	response, err := getAll[commit](c, uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// the GitHub API returns the oldest commit of a pull request first
+	commits := []models.Commit{}
+	for _, prCommit := range *response {
+		parentShas := []string{}
+		for _, parent := range prCommit.Parents {
+			parentShas = append(parentShas, parent.Sha)
+		}
+
+		commits = append(commits, models.Commit{
+			Sha:        prCommit.Sha,
+			CreatedAt:  prCommit.Commit.Committer.Date,
+			ParentShas: parentShas,
+		})
+	}
+
+	return &commits, nil
+}
+
+// GetCommits gets all commits of a repository since a given time
+func (c *Client) GetCommits(namespacedName string, referenceBranch string, since time.Time) (*[]models.Commit, error) {
+	uri := fmt.Sprintf("%s/repos/%s/commits", c.URI, namespacedName)
+	query := map[string]string{
+		"sha":      referenceBranch,
+		"since":    since.Format(time.RFC3339),
+		"per_page": "100", // max
+	}
+
+	response, err := getAll[commit](c, uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// the GitHub API returns the newest commit first, but we persist them in ascending order like Gitlab
+	commits := []models.Commit{}
+	for index := len(*response) - 1; index >= 0; index-- {
+		parentShas := []string{}
+		for _, parent := range (*response)[index].Parents {
+			parentShas = append(parentShas, parent.Sha)
+		}
+
+		commits = append(commits, models.Commit{
+			Sha:        (*response)[index].Sha,
+			CreatedAt:  (*response)[index].Commit.Committer.Date,
+			ParentShas: parentShas,
+		})
+	}
+
+	log.Printf("Found %d commits", len(commits))
+
+	return &commits, nil
+}
+
+// GetPipelineRuns gets all successful runs of a workflow of a repository created since a given time.
+// Only the runs of the deploying workflow count, not those of e.g. linting or docs workflows of the same repository.
+func (c *Client) GetPipelineRuns(namespacedName string, workflowID int, referenceBranch string, since time.Time) (*[]models.PipelineRun, error) {
+	if workflowID < 1 {
+		return nil, fmt.Errorf("no workflow of %s given, the external_id of its pipeline must be the ID of the workflow", namespacedName)
+	}
+
+	uri := fmt.Sprintf("%s/repos/%s/actions/workflows/%d/runs", c.URI, namespacedName, workflowID)
+	query := map[string]string{
+		"branch":   referenceBranch,
+		"event":    "push",
+		"status":   "success",
+		"created":  fmt.Sprintf(">=%s", times.Day(since)),
+		"per_page": "100", // max
+	}
+
+	// workflow runs are returned within an object, rather than as a list
+	var response workflowRuns
+	err := c.getPages(uri, query, func(body []byte) (bool, error) {
+		var page workflowRuns
+		err := json.Unmarshal(body, &page)
+		if err != nil {
+			return false, err
+		}
+
+		response.TotalCount = page.TotalCount
+		response.WorkflowRuns = append(response.WorkflowRuns, page.WorkflowRuns...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the GitHub API returns the newest workflow run first, but we persist them in ascending order
+	pipelineRuns := []models.PipelineRun{}
+	for index := len(response.WorkflowRuns) - 1; index >= 0; index-- {
+		run := response.WorkflowRuns[index]
+		pipelineRuns = append(pipelineRuns, models.PipelineRun{
+			ExternalID:  run.ID,
+			Sha:         run.HeadSha,
+			Ref:         run.HeadBranch,
+			Status:      run.Conclusion,
+			EventSource: run.Event,
+			CreatedAt:   run.CreatedAt,
+			UpdatedAt:   run.UpdatedAt,
+			URI:         run.HTMLURL,
+		})
+	}
+
+	log.Printf("Found %d pipeline runs", len(pipelineRuns))
+
+	return &pipelineRuns, nil
+}
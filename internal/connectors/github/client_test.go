@@ -0,0 +1,281 @@
+package github_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/test"
+
+	"github.com/unnmdnwb3/dora/internal/connectors/github"
+)
+
+func TestClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "github.Client Suite")
+}
+
+var _ = Describe("github.Client", func() {
+	var (
+		namespacedName  = "foobar/foobar"
+		referenceBranch = "main"
+		since           = time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	var _ = When("GetOrganisations", func() {
+		It("get all organisations", func() {
+			var fixture any
+			err := test.UnmarshalFixture("./../../../test/data/github/organisations.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+
+			}))
+			defer mock.Close()
+
+			client := github.NewClient(mock.URL, "bearertoken")
+
+			organisations, err := client.GetOrganisations()
+			Expect(err).To(BeNil())
+			Expect(len(*organisations)).To(Equal(2))
+			Expect((*organisations)[0].NamespacedName).To(Equal("fizzbuzz"))
+		})
+	})
+
+	var _ = When("GetRepositories", func() {
+		It("get all repositories", func() {
+			var fixture any
+			err := test.UnmarshalFixture("./../../../test/data/github/repositories.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+
+			}))
+			defer mock.Close()
+
+			client := github.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			repositories, err := client.GetRepositories()
+			Expect(err).To(BeNil())
+			Expect(len(*repositories)).To(Equal(1))
+			Expect((*repositories)[0].ExternalID).To(Equal(40649465))
+			Expect((*repositories)[0].NamespacedName).To(Equal(namespacedName))
+			Expect((*repositories)[0].DefaultBranch).To(Equal(referenceBranch))
+		})
+	})
+
+	var _ = When("GetCommits", func() {
+		It("get all commits of a repository", func() {
+			var fixture any
+			err := test.UnmarshalFixture("./../../../test/data/github/commits.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/repos/foobar/foobar/commits"))
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+
+			}))
+			defer mock.Close()
+
+			client := github.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			commits, err := client.GetCommits(namespacedName, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(len(*commits)).To(Equal(3))
+			Expect((*commits)[0].Sha).To(Equal("3d95fe3bf954501d3832e50fdd803c5f9eae3f94"))
+			Expect((*commits)[0].CreatedAt).To(Equal(time.Date(2022, 12, 28, 12, 21, 5, 0, time.UTC)))
+			Expect((*commits)[2].ParentShas).To(Equal([]string{
+				"3d95fe3bf954501d3832e50fdd803c5f9eae3f94",
+				"487d6aedb92ab76bdc03957aceece75db906796e",
+			}))
+		})
+	})
+
+	var _ = When("GetCommits spans several pages", func() {
+		It("follows the Link header until the last page", func() {
+			var fixture []any
+			err := test.UnmarshalFixture("./../../../test/data/github/commits.json", &fixture)
+			Expect(err).To(BeNil())
+
+			var mock *httptest.Server
+			mock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page := fixture[:2]
+				if r.URL.Query().Get("page") == "2" {
+					page = fixture[2:]
+				} else {
+					w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foobar/foobar/commits?page=2>; rel="next", <%s/repos/foobar/foobar/commits?page=2>; rel="last"`, mock.URL, mock.URL))
+				}
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(page)
+				w.Write(json)
+			}))
+			defer mock.Close()
+
+			client := github.NewClient(mock.URL, "token")
+
+			commits, err := client.GetCommits(namespacedName, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(len(*commits)).To(Equal(3))
+			Expect((*commits)[0].Sha).To(Equal("3d95fe3bf954501d3832e50fdd803c5f9eae3f94"))
+		})
+
+		It("stops at the upper bound of pages", func() {
+			requests := 0
+			var mock *httptest.Server
+			mock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foobar/foobar/commits?page=2>; rel="next"`, mock.URL))
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("[]"))
+			}))
+			defer mock.Close()
+
+			client := github.NewClient(mock.URL, "token")
+			client.MaxPages = 3
+
+			_, err := client.GetCommits(namespacedName, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(requests).To(Equal(3))
+		})
+	})
+
+	var _ = When("the GitHub API responds with an error", func() {
+		It("returns an error instead of an empty result", func() {
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"message": "Bad credentials"}`))
+			}))
+			defer mock.Close()
+
+			client := github.NewClient(mock.URL, "token")
+
+			_, err := client.GetCommits(namespacedName, referenceBranch, since)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("401"))
+		})
+	})
+
+	var _ = When("GetPullRequests", func() {
+		It("get all merged pull requests of a repository", func() {
+			var fixture any
+			err := test.UnmarshalFixture("./../../../test/data/github/pull_requests.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+
+			}))
+			defer mock.Close()
+
+			client := github.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			pullRequests, err := client.GetPullRequests(namespacedName, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(len(*pullRequests)).To(Equal(2))
+			Expect((*pullRequests)[0].MergeCommitSha).To(Equal("1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7"))
+			Expect((*pullRequests)[0].Reference).To(Equal("#2"))
+			Expect((*pullRequests)[0].IID).To(Equal(2))
+		})
+	})
+
+	var _ = When("GetPullRequestCommits", func() {
+		It("get all commits of a pull request", func() {
+			var fixture any
+			err := test.UnmarshalFixture("./../../../test/data/github/commits.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/repos/foobar/foobar/pulls/2/commits"))
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+
+			}))
+			defer mock.Close()
+
+			client := github.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			commits, err := client.GetPullRequestCommits(namespacedName, 2)
+			Expect(err).To(BeNil())
+			Expect(len(*commits)).To(Equal(3))
+		})
+	})
+
+	var _ = When("PipelineRuns", func() {
+		It("get all pipeline runs", func() {
+			var fixture any
+			err := test.UnmarshalFixture("./../../../test/data/github/pipeline_runs.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/repos/foobar/foobar/actions/workflows/42/runs"))
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+
+			}))
+			defer mock.Close()
+
+			client := github.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			pipelineRuns, err := client.GetPipelineRuns(namespacedName, 42, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(len(*pipelineRuns)).To(Equal(3))
+			Expect((*pipelineRuns)[0].ExternalID).To(Equal(3791240077))
+			Expect((*pipelineRuns)[0].Status).To(Equal("success"))
+			Expect((*pipelineRuns)[2].Sha).To(Equal("1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7"))
+		})
+
+		It("returns an error without a workflow", func() {
+			client := github.Client{
+				Auth: "token",
+				URI:  "http://localhost",
+			}
+
+			_, err := client.GetPipelineRuns(namespacedName, 0, referenceBranch, since)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	var _ = When("MaxPages", func() {
+		It("returns the upper bound of pages configured, or the default", func() {
+			os.Setenv("GITHUB_MAX_PAGES", "5")
+			defer os.Unsetenv("GITHUB_MAX_PAGES")
+			Expect(github.MaxPages()).To(Equal(5))
+
+			os.Setenv("GITHUB_MAX_PAGES", "none")
+			Expect(github.MaxPages()).To(Equal(github.DefaultMaxPages))
+		})
+	})
+})
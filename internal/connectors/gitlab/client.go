@@ -6,221 +6,234 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/unnmdnwb3/dora/internal/models"
-	"github.com/unnmdnwb3/dora/internal/utils/times"
 )
 
+// DefaultMaxPages is the upper bound of pages fetched per resource, if not configured otherwise.
+// With 100 results per page, this allows 10'000 results per resource.
+const DefaultMaxPages = 100
+
 // Client represents a Gitlab API client
 type Client struct {
-	URI  string
-	Auth string
+	URI      string
+	Auth     string
+	MaxPages int // upper bound of pages fetched per resource, so one huge repository can't starve the importer
 }
 
 // NewClient creates a new Gitlab API client
 func NewClient(URI string, auth string) *Client {
 	return &Client{
-		URI:  URI,
-		Auth: auth,
+		URI:      URI,
+		Auth:     auth,
+		MaxPages: MaxPages(),
+	}
+}
+
+// MaxPages returns the upper bound of pages configured with GITLAB_MAX_PAGES, or DefaultMaxPages.
+func MaxPages() int {
+	maxPages, err := strconv.Atoi(os.Getenv("GITLAB_MAX_PAGES"))
+	if err != nil || maxPages < 1 {
+		return DefaultMaxPages
 	}
+	return maxPages
 }
 
 // GetOrganisations gets all organisations readable with the bearer token provided
 func (c *Client) GetOrganisations() (*[]models.Organisation, error) {
-	client := &http.Client{}
-
 	uri := fmt.Sprintf("%s/groups", c.URI)
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	bearer := fmt.Sprintf("Bearer %s", c.Auth)
-	req.Header.Add("Authorization", bearer)
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 
-	organisations := []models.Organisation{}
-	err = json.Unmarshal(body, &organisations)
-	if err != nil {
-		return nil, err
-	}
-
-	return &organisations, nil
+	return getAll[models.Organisation](c, uri, url.Values{})
 }
 
 // GetRepositories gets all repositories readable with the bearer token provided
 func (c *Client) GetRepositories() (*[]models.Repository, error) {
-	client := &http.Client{}
-
 	uri := fmt.Sprintf("%s/projects", c.URI)
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
-	}
 
-	bearer := fmt.Sprintf("Bearer %s", c.Auth)
-	req.Header.Add("Authorization", bearer)
-
-	q := req.URL.Query()
+	q := url.Values{}
 	q.Add("owned", "true")
 	q.Add("simple", "true")
-	req.URL.RawQuery = q.Encode()
+	// keyset pagination is supported for projects and stays fast for large offsets
+	q.Add("pagination", "keyset")
+	q.Add("order_by", "id")
+	q.Add("sort", "asc")
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return getAll[models.Repository](c, uri, q)
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// GetPullRequests gets all pull requests of a repository updated since a given time
+func (c *Client) GetPullRequests(projectID int, targetBranch string, since time.Time) (*[]models.PullRequest, error) {
+	uri := fmt.Sprintf("%s/projects/%s/merge_requests", c.URI, strconv.Itoa(projectID))
 
-	repositories := []models.Repository{}
-	err = json.Unmarshal(body, &repositories)
-	if err != nil {
-		return nil, err
-	}
+	q := url.Values{}
+	q.Add("state", "merged")
+	q.Add("target_branch", targetBranch)
+	q.Add("updated_after", since.Format(time.RFC3339))
 
-	return &repositories, nil
+	return getAll[models.PullRequest](c, uri, q)
 }
 
-// GetPullRequests gets all pull requests of a repository
-func (c *Client) GetPullRequests(projectID int, targetBranch string) (*[]models.PullRequest, error) {
-	client := &http.Client{}
+// GetPullRequestCommits gets all commits of a merged pull request
+func (c *Client) GetPullRequestCommits(projectID int, pullRequestIID int) (*[]models.Commit, error) {
+	uri := fmt.Sprintf("%s/projects/%s/merge_requests/%s/commits", c.URI, strconv.Itoa(projectID), strconv.Itoa(pullRequestIID))
 
-	uri := fmt.Sprintf("%s/projects/%s/merge_requests", c.URI, strconv.Itoa(projectID))
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
+	return getAll[models.Commit](c, uri, url.Values{})
+}
+
+// GetCommits gets the commits of a repository since a given time, in ascending order.
+// The Gitlab API lists the newest commits first, hence if the upper bound of pages cuts off the oldest ones,
+// the commits up to the oldest one fetched are fetched again, until none since the given time are cut off.
+// The newer commits left out are fetched by the next sync, as they are newer than the commits returned.
+func (c *Client) GetCommits(projectID int, referenceBranch string, since time.Time) (*[]models.Commit, error) {
+	uri := fmt.Sprintf("%s/projects/%s/repository/commits", c.URI, strconv.Itoa(projectID))
+
+	var until time.Time
+	for {
+		q := url.Values{}
+		q.Add("order", "default") // newest first
+		q.Add("ref_name", referenceBranch)
+		q.Add("since", since.Format(time.RFC3339))
+		if !until.IsZero() {
+			q.Add("until", until.Format(time.RFC3339))
+		}
+
+		commits, truncated, err := getPages[models.Commit](c, uri, q)
+		if err != nil {
+			return nil, err
+		}
+
+		// more commits than fit into the pages at the same time can only be fetched in part
+		oldest := len(*commits) - 1
+		if truncated && oldest >= 0 && !(*commits)[oldest].CreatedAt.Equal(until) {
+			until = (*commits)[oldest].CreatedAt
+			continue
+		}
+
+		ascending := make([]models.Commit, len(*commits))
+		for index, commit := range *commits {
+			ascending[len(*commits)-1-index] = commit
+		}
+
+		log.Printf("Found %d commits", len(ascending))
+
+		return &ascending, nil
 	}
+}
 
-	bearer := fmt.Sprintf("Bearer %s", c.Auth)
-	req.Header.Add("Authorization", bearer)
+// GetPipelineRuns gets all workflow runs of a project updated since a given time
+func (c *Client) GetPipelineRuns(projectID int, referenceBranch string, since time.Time) (*[]models.PipelineRun, error) {
+	uri := fmt.Sprintf("%s/projects/%s/pipelines", c.URI, strconv.Itoa(projectID))
 
-	// TODO add "since" parameter
-	q := req.URL.Query()
-	q.Add("state", "merged")
-	q.Add("target_branch", targetBranch)
-	req.URL.RawQuery = q.Encode()
+	q := url.Values{}
+	q.Add("ref", referenceBranch)
+	q.Add("sort", "asc") // asc
+	q.Add("source", "push")
+	q.Add("status", "success")
+	q.Add("updated_after", since.Format(time.RFC3339))
 
-	resp, err := client.Do(req)
+	pipelineRuns, err := getAll[models.PipelineRun](c, uri, q)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	log.Printf("Found %d pipeline runs", len(*pipelineRuns))
 
-	pullRequests := []models.PullRequest{}
-	err = json.Unmarshal(body, &pullRequests)
-	if err != nil {
-		return nil, err
-	}
+	return pipelineRuns, nil
+}
 
-	return &pullRequests, nil
+// getAll gets all pages of a resource, following the pagination headers of the Gitlab API until
+// either all results are fetched or the upper bound of pages is reached.
+func getAll[T any](c *Client, uri string, query url.Values) (*[]T, error) {
+	results, _, err := getPages[T](c, uri, query)
+	return results, err
 }
 
-// GetCommits gets all commits of a repository
-func (c *Client) GetCommits(projectID int, referenceBranch string) (*[]models.Commit, error) {
+// getPages gets the pages of a resource like getAll, and reports if the upper bound of pages cut off any results.
+func getPages[T any](c *Client, uri string, query url.Values) (*[]T, bool, error) {
 	client := &http.Client{}
 
-	uri := fmt.Sprintf("%s/projects/%s/repository/commits", c.URI, strconv.Itoa(projectID))
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
+	maxPages := c.MaxPages
+	if maxPages < 1 {
+		maxPages = DefaultMaxPages
 	}
 
-	bearer := fmt.Sprintf("Bearer %s", c.Auth)
-	req.Header.Add("Authorization", bearer)
+	query.Set("per_page", "100") // max
+	next := fmt.Sprintf("%s?%s", uri, query.Encode())
 
-	// TODO add "since" parameter
-	q := req.URL.Query()
-	q.Add("order", "default") // asc
-	q.Add("ref_name", referenceBranch)
-	q.Add("since", times.Date(time.Now().AddDate(0, -3, 0)).String())
-	q.Add("per_page", "100") // max
-	req.URL.RawQuery = q.Encode()
+	results := []T{}
+	for page := 0; next != "" && page < maxPages; page++ {
+		req, err := http.NewRequest(http.MethodGet, next, nil)
+		if err != nil {
+			return nil, false, err
+		}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+		bearer := fmt.Sprintf("Bearer %s", c.Auth)
+		req.Header.Add("Authorization", bearer)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	commits := []models.Commit{}
-	err = json.Unmarshal(body, &commits)
-	if err != nil {
-		return nil, err
-	}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, false, err
+		}
 
-	log.Printf("Found %d commits", len(commits))
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, false, err
+		}
 
-	return &commits, nil
-}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, false, fmt.Errorf("gitlab at %s responded with status %d: %s", uri, resp.StatusCode, body)
+		}
 
-// GetPipelineRuns gets all workflow runs of a project
-func (c *Client) GetPipelineRuns(projectID int, referenceBranch string) (*[]models.PipelineRun, error) {
-	client := &http.Client{}
+		var pageResults []T
+		err = json.Unmarshal(body, &pageResults)
+		if err != nil {
+			return nil, false, err
+		}
 
-	uri := fmt.Sprintf("%s/projects/%s/pipelines", c.URI, strconv.Itoa(projectID))
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
-	if err != nil {
-		return nil, err
+		results = append(results, pageResults...)
+		next = NextPage(resp.Header, req.URL)
 	}
 
-	bearer := fmt.Sprintf("Bearer %s", c.Auth)
-	req.Header.Add("Authorization", bearer)
+	truncated := next != ""
+	if truncated {
+		log.Printf("Stopped after %d pages of %s, there are more results", maxPages, uri)
+	}
 
-	q := req.URL.Query()
-	q.Add("ref", referenceBranch)
-	q.Add("sort", "asc") // asc
-	q.Add("source", "push")
-	q.Add("status", "success")
-	// shorter than commits, because a pipeline run could depend on commits older than a month or so
-	q.Add("updated_after", times.Date(time.Now().AddDate(0, -1, 0)).String())
-	q.Add("per_page", "100") // max
-	req.URL.RawQuery = q.Encode()
+	return &results, truncated, nil
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// NextPage returns the URI of the next page, or an empty string for the last page.
+// Keyset pagination provides the next page in the Link header,
+// whereas offset pagination provides the next page number in the X-Next-Page header.
+func NextPage(header http.Header, current *url.URL) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+		for _, param := range parts[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
 	}
 
-	var pipelineRuns []models.PipelineRun
-	err = json.Unmarshal(body, &pipelineRuns)
-	if err != nil {
-		return nil, err
+	nextPage := header.Get("X-Next-Page")
+	if nextPage == "" {
+		return ""
 	}
 
-	log.Printf("Found %d pipeline runs", len(pipelineRuns))
+	next := *current
+	q := next.Query()
+	q.Set("page", nextPage)
+	next.RawQuery = q.Encode()
 
-	return &pipelineRuns, nil
+	return next.String()
 }
@@ -2,9 +2,11 @@ package gitlab_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -23,6 +25,7 @@ var _ = Describe("gitlab.Client", func() {
 	var (
 		projectID       = 15392086
 		referenceBranch = "main"
+		since           = time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
 	)
 
 	var _ = When("GetOrganisations", func() {
@@ -79,6 +82,7 @@ var _ = Describe("gitlab.Client", func() {
 			Expect(err).To(BeNil())
 
 			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Get("since")).To(Equal("2022-12-01T00:00:00Z"))
 				w.WriteHeader(http.StatusOK)
 				json, _ := json.Marshal(fixture)
 				w.Write(json)
@@ -91,10 +95,52 @@ var _ = Describe("gitlab.Client", func() {
 				URI:  mock.URL,
 			}
 
-			commits, err := client.GetCommits(projectID, referenceBranch)
+			commits, err := client.GetCommits(projectID, referenceBranch, since)
 			Expect(err).To(BeNil())
 			Expect(len(*commits)).To(Equal(10))
 		})
+
+		It("gets the oldest commits first if the upper bound of pages is reached.", func() {
+			var fixture []models.Commit
+			for day := 4; day > 0; day-- {
+				fixture = append(fixture, models.Commit{
+					Sha:       fmt.Sprint(day),
+					CreatedAt: since.AddDate(0, 0, day),
+				})
+			}
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				commits := []models.Commit{}
+				for _, commit := range fixture {
+					until, err := time.Parse(time.RFC3339, r.URL.Query().Get("until"))
+					if err != nil || !commit.CreatedAt.After(until) {
+						commits = append(commits, commit)
+					}
+				}
+
+				page := 1
+				fmt.Sscan(r.URL.Query().Get("page"), &page)
+				if page < len(commits) {
+					w.Header().Set("X-Next-Page", fmt.Sprint(page+1))
+				}
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(commits[page-1 : page])
+				w.Write(json)
+			}))
+			defer mock.Close()
+
+			client := gitlab.Client{
+				Auth:     "token",
+				URI:      mock.URL,
+				MaxPages: 2,
+			}
+
+			commits, err := client.GetCommits(projectID, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(len(*commits)).To(Equal(2))
+			Expect((*commits)[0].Sha).To(Equal("1"))
+			Expect((*commits)[1].Sha).To(Equal("2"))
+		})
 	})
 
 	var _ = When("GetPullRequests", func() {
@@ -116,12 +162,38 @@ var _ = Describe("gitlab.Client", func() {
 				URI:  mock.URL,
 			}
 
-			pullRequests, err := client.GetPullRequests(projectID, referenceBranch)
+			pullRequests, err := client.GetPullRequests(projectID, referenceBranch, since)
 			Expect(err).To(BeNil())
 			Expect(len(*pullRequests)).To(Equal(3))
 		})
 	})
 
+	var _ = When("GetPullRequestCommits", func() {
+		It("get all commits of a pull request", func() {
+			var fixture []models.Commit
+			err := test.UnmarshalFixture("./../../../test/data/gitlab/commits.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/projects/15392086/merge_requests/2/commits"))
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+
+			}))
+			defer mock.Close()
+
+			client := gitlab.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			commits, err := client.GetPullRequestCommits(projectID, 2)
+			Expect(err).To(BeNil())
+			Expect(len(*commits)).To(Equal(10))
+		})
+	})
+
 	var _ = When("PipelineRuns", func() {
 		It("get all pipeline runs", func() {
 			var fixture []models.PipelineRun
@@ -141,9 +213,109 @@ var _ = Describe("gitlab.Client", func() {
 				URI:  mock.URL,
 			}
 
-			pipelineRuns, err := client.GetPipelineRuns(projectID, referenceBranch)
+			pipelineRuns, err := client.GetPipelineRuns(projectID, referenceBranch, since)
 			Expect(err).To(BeNil())
 			Expect(len(*pipelineRuns)).To(Equal(4))
 		})
 	})
+
+	var _ = When("Pagination", func() {
+		It("follows the X-Next-Page header until the last page.", func() {
+			var fixture []models.Commit
+			err := test.UnmarshalFixture("./../../../test/data/gitlab/commits.json", &fixture)
+			Expect(err).To(BeNil())
+
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") != "2" {
+					w.Header().Set("X-Next-Page", "2")
+				}
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+			}))
+			defer mock.Close()
+
+			client := gitlab.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			commits, err := client.GetCommits(projectID, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(len(*commits)).To(Equal(20))
+		})
+
+		It("follows the Link header of keyset pagination.", func() {
+			var fixture []models.Repository
+			err := test.UnmarshalFixture("./../../../test/data/gitlab/repositories.json", &fixture)
+			Expect(err).To(BeNil())
+
+			var mock *httptest.Server
+			mock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Get("pagination")).To(Equal("keyset"))
+				if r.URL.Query().Get("id_after") == "" {
+					next := fmt.Sprintf("%s/projects?pagination=keyset&per_page=100&order_by=id&sort=asc&id_after=1", mock.URL)
+					w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next))
+				}
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+			}))
+			defer mock.Close()
+
+			client := gitlab.Client{
+				Auth: "token",
+				URI:  mock.URL,
+			}
+
+			repositories, err := client.GetRepositories()
+			Expect(err).To(BeNil())
+			Expect(len(*repositories)).To(Equal(2))
+		})
+
+		It("stops at the upper bound of pages.", func() {
+			var fixture []models.PipelineRun
+			err := test.UnmarshalFixture("./../../../test/data/gitlab/pipeline_runs.json", &fixture)
+			Expect(err).To(BeNil())
+
+			requests := 0
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("X-Next-Page", fmt.Sprint(requests+1))
+				w.WriteHeader(http.StatusOK)
+				json, _ := json.Marshal(fixture)
+				w.Write(json)
+			}))
+			defer mock.Close()
+
+			client := gitlab.Client{
+				Auth:     "token",
+				URI:      mock.URL,
+				MaxPages: 3,
+			}
+
+			pipelineRuns, err := client.GetPipelineRuns(projectID, referenceBranch, since)
+			Expect(err).To(BeNil())
+			Expect(requests).To(Equal(3))
+			Expect(len(*pipelineRuns)).To(Equal(12))
+		})
+	})
+
+	var _ = When("the Gitlab API responds with an error", func() {
+		It("returns an error instead of an empty page.", func() {
+			for _, statusCode := range []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusTooManyRequests} {
+				mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(statusCode)
+					w.Write([]byte(`{"message": "error"}`))
+				}))
+
+				client := gitlab.NewClient(mock.URL, "token")
+
+				_, err := client.GetCommits(projectID, referenceBranch, since)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprint(statusCode)))
+				mock.Close()
+			}
+		})
+	})
 })
@@ -0,0 +1,78 @@
+package prometheus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/utils/labels"
+)
+
+// AlertsQuery returns the query of the ALERTS series firing and matching every label matcher, e.g. alertname="TargetDown".
+func AlertsQuery(matchers []string) string {
+	return fmt.Sprintf("ALERTS{%s}", strings.Join(append([]string{`alertstate="firing"`}, matchers...), ","))
+}
+
+// GetIncidents gets an incident for each interval a series of the query has samples since a given time,
+// e.g. while an alert of the ALERTS series is firing. Start and end are exact up to the step of the client.
+func (c *Client) GetIncidents(since time.Time) (*[]models.Incident, error) {
+	queryResponses, err := c.queryRangeSince(since)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CreateIncidents(*queryResponses)
+}
+
+// CreateIncidents creates Incidents from the samples of QueryResponses, one for each interval a series has samples.
+// Samples of a series at most one step apart belong to the same interval, even across QueryResponses.
+func (c *Client) CreateIncidents(queryResponses []QueryResponse) (*[]models.Incident, error) {
+	timestampsPerSeries := map[string][]float64{}
+	labelsPerSeries := map[string]map[string]string{}
+	for _, queryResponse := range queryResponses {
+		for _, result := range queryResponse.Data.Result {
+			key := labels.Key(result.Metric)
+			labelsPerSeries[key] = result.Metric
+			for _, dataPoint := range result.Values {
+				if len(dataPoint) != 2 {
+					return nil, &DecodeError{Err: fmt.Errorf("malformed sample: %v", dataPoint)}
+				}
+
+				timestamp, ok := dataPoint[0].(float64)
+				if !ok {
+					return nil, &DecodeError{Err: fmt.Errorf("malformed timestamp of sample: %v", dataPoint)}
+				}
+				timestampsPerSeries[key] = append(timestampsPerSeries[key], timestamp)
+			}
+		}
+	}
+
+	incidents := []models.Incident{}
+	for key, timestamps := range timestampsPerSeries {
+		sort.Float64s(timestamps)
+
+		start := timestamps[0]
+		for index := 1; index <= len(timestamps); index++ {
+			if index < len(timestamps) && timestamps[index]-timestamps[index-1] <= float64(c.Step) {
+				continue
+			}
+
+			incidents = append(incidents, models.Incident{
+				StartDate: time.Unix(int64(start), 0),
+				EndDate:   time.Unix(int64(timestamps[index-1]), 0),
+				Series:    []map[string]string{labelsPerSeries[key]},
+			})
+			if index < len(timestamps) {
+				start = timestamps[index]
+			}
+		}
+	}
+
+	sort.Slice(incidents, func(i, j int) bool {
+		return incidents[i].StartDate.Before(incidents[j].StartDate)
+	})
+
+	return &incidents, nil
+}
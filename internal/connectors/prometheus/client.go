@@ -4,49 +4,110 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/unnmdnwb3/dora/internal/models"
 )
 
-// Client represents a Gitlab API client.
+// MaxSamples is the maximum number of samples per series Prometheus returns for a single range query.
+const MaxSamples = 11000
+
+// Client represents a Prometheus API client.
 type Client struct {
-	URI   string
-	Auth  string
-	Query string
+	URI       string
+	Auth      string
+	Query     string
+	Step      int
+	Relation  string
+	Threshold float64
 }
 
-// NewClient creates a new Gitlab API client.
-func NewClient(URI string, auth string, query string) *Client {
+// NewClient creates a new Prometheus API client for the query of a deployment.
+func NewClient(URI string, auth string, deployment *models.Deployment) *Client {
+	step := deployment.Step
+	if step < 1 {
+		step = models.DefaultStep
+	}
+
 	return &Client{
-		URI:   URI,
-		Auth:  auth,
-		Query: query,
+		URI:       URI,
+		Auth:      auth,
+		Query:     deployment.Query,
+		Step:      step,
+		Relation:  deployment.Relation,
+		Threshold: deployment.Threshold,
 	}
 }
 
+// QueryStatusError is the status of a QueryResponse of a rejected query.
+const QueryStatusError = "error"
+
 // QueryResponse represents a Prometheus query response.
 type QueryResponse struct {
-	Data struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType,omitempty"` // only set if the status is QueryStatusError
+	Error     string `json:"error,omitempty"`     // only set if the status is QueryStatusError
+	Data      struct {
 		Result []struct {
-			Metric struct {
-				Name string `json:"__name__"`
-			} `json:"metric"`
-			Values [][]interface{} `json:"values"`
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
 		} `json:"result"`
 	} `json:"data"`
 }
 
-// GetAlerts gets all alerts.
-func (c *Client) GetAlerts() (*[]models.Alert, error) {
+// GetAlerts gets all alerts since a given time.
+func (c *Client) GetAlerts(since time.Time) (*[]models.Alert, error) {
+	queryResponses, err := c.queryRangeSince(since)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []models.Alert{}
+	for _, queryResponse := range *queryResponses {
+		rangeAlerts, err := c.CreateAlerts(queryResponse)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, *rangeAlerts...)
+	}
+
+	return &alerts, nil
+}
+
+// queryRangeSince runs the query of the client from a given time until now.
+// The range is split into several queries if it exceeds MaxSamples steps.
+func (c *Client) queryRangeSince(since time.Time) (*[]QueryResponse, error) {
+	until := time.Now()
+	step := time.Duration(c.Step) * time.Second
+
+	queryResponses := []QueryResponse{}
+	for start := since; !start.After(until); start = start.Add(MaxSamples * step) {
+		end := start.Add((MaxSamples - 1) * step)
+		if end.After(until) {
+			end = until
+		}
+
+		queryResponse, err := c.queryRange(start, end)
+		if err != nil {
+			return nil, err
+		}
+		queryResponses = append(queryResponses, *queryResponse)
+	}
+
+	return &queryResponses, nil
+}
+
+// queryRange runs the query of the client over a range of time.
+func (c *Client) queryRange(start time.Time, end time.Time) (*QueryResponse, error) {
 	client := &http.Client{}
 
-	uri := fmt.Sprintf("%s/api/v1/query", c.URI)
+	uri := fmt.Sprintf("%s/api/v1/query_range", c.URI)
 	req, err := http.NewRequest(http.MethodGet, uri, nil)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, &ConnectionError{URI: uri, Err: err}
 	}
 
 	bearer := fmt.Sprintf("Bearer %s", c.Auth)
@@ -54,41 +115,131 @@ func (c *Client) GetAlerts() (*[]models.Alert, error) {
 
 	q := req.URL.Query()
 	q.Add("query", c.Query)
+	q.Add("start", strconv.FormatInt(start.Unix(), 10))
+	q.Add("end", strconv.FormatInt(end.Unix(), 10))
+	q.Add("step", strconv.Itoa(c.Step))
 	req.URL.RawQuery = q.Encode()
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, &ConnectionError{URI: uri, Err: err}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, &ConnectionError{URI: uri, Err: err}
 	}
 
+	// rejected queries are answered with a non-2xx status code and the error within the response
 	var queryResponse QueryResponse
 	err = json.Unmarshal(body, &queryResponse)
-	if err != nil {
-		log.Fatalln(err)
+	if err == nil && queryResponse.Status == QueryStatusError {
+		return nil, &QueryError{StatusCode: resp.StatusCode, ErrorType: queryResponse.ErrorType, Err: queryResponse.Error}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{URI: uri, StatusCode: resp.StatusCode}
 	}
 
-	alerts, err := c.CreateAlerts(queryResponse)
 	if err != nil {
-		return nil, err
+		return nil, &DecodeError{Err: err}
 	}
 
-	return alerts, nil
+	return &queryResponse, nil
 }
 
-// CreateAlerts creates Alerts from a QueryResponse.
+// CreateAlerts creates Alerts from the samples of a QueryResponse breaching the threshold.
+// Each Alert is labeled with the labels of its series, as a query can return several series.
 func (c *Client) CreateAlerts(queryResponse QueryResponse) (*[]models.Alert, error) {
+	// a relation not supported is not an error of the response, hence is reported even without samples
+	err := ValidateRelation(c.Relation)
+	if err != nil {
+		return nil, err
+	}
+
 	alerts := []models.Alert{}
 	for _, result := range queryResponse.Data.Result {
 		for _, dataPoint := range result.Values {
-			alerts = append(alerts, models.Alert{CreatedAt: time.Unix(int64(dataPoint[0].(float64)), 0)})
+			if len(dataPoint) != 2 {
+				return nil, &DecodeError{Err: fmt.Errorf("malformed sample: %v", dataPoint)}
+			}
+
+			timestamp, ok := dataPoint[0].(float64)
+			if !ok {
+				return nil, &DecodeError{Err: fmt.Errorf("malformed timestamp of sample: %v", dataPoint)}
+			}
+
+			value, ok := dataPoint[1].(string)
+			if !ok {
+				return nil, &DecodeError{Err: fmt.Errorf("malformed value of sample: %v", dataPoint)}
+			}
+
+			breaches, err := Breaches(value, c.Relation, c.Threshold)
+			if err != nil {
+				return nil, &DecodeError{Err: err}
+			}
+
+			if breaches {
+				alerts = append(alerts, models.Alert{
+					CreatedAt: time.Unix(int64(timestamp), 0),
+					Labels:    result.Metric,
+				})
+			}
 		}
 	}
 
 	return &alerts, nil
 }
+
+// rangeSelector matches a query evaluating to a range vector, e.g. up[6w] or up[1h:5m] offset 1d.
+var rangeSelector = regexp.MustCompile(`\[[^\[\]]+\](\s*(offset\s+-?\w+|@\s*[\w().]+))*\s*$`)
+
+// ValidateQuery returns an error if a query cannot be evaluated over a range,
+// as Prometheus rejects range queries evaluating to a range vector.
+func ValidateQuery(query string) error {
+	if rangeSelector.MatchString(query) {
+		return fmt.Errorf("query must not evaluate to a range vector: %s", query)
+	}
+	return nil
+}
+
+// Breaches returns true if the value of a sample is in relation to the threshold.
+// Without a relation, every sample breaches.
+func Breaches(value string, relation string, threshold float64) (bool, error) {
+	if relation == "" {
+		return true, nil
+	}
+
+	sample, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, fmt.Errorf("malformed value of sample: %s", value)
+	}
+
+	switch models.DeploymentRelation(relation) {
+	case models.RelationGreater:
+		return sample > threshold, nil
+	case models.RelationGreaterOrEqual:
+		return sample >= threshold, nil
+	case models.RelationLess:
+		return sample < threshold, nil
+	case models.RelationLessOrEqual:
+		return sample <= threshold, nil
+	case models.RelationEqual:
+		return sample == threshold, nil
+	case models.RelationNotEqual:
+		return sample != threshold, nil
+	default:
+		return false, &RelationError{Relation: relation}
+	}
+}
+
+// ValidateRelation returns a RelationError if a relation is neither one of the relations, a legacy one nor empty.
+func ValidateRelation(relation string) error {
+	switch models.DeploymentRelation(relation) {
+	case "", models.RelationGreater, models.RelationGreaterOrEqual, models.RelationLess, models.RelationLessOrEqual, models.RelationEqual, models.RelationNotEqual:
+		return nil
+	default:
+		return &RelationError{Relation: relation}
+	}
+}
@@ -2,14 +2,17 @@ package prometheus_test
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/unnmdnwb3/dora/internal/connectors/prometheus"
+	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/test"
 )
 
@@ -20,8 +23,9 @@ func TestClient(t *testing.T) {
 
 var _ = Describe("prometheus.Client", func() {
 	var (
-		mock               *httptest.Server
+		mock          *httptest.Server
 		queryResponse prometheus.QueryResponse
+		deployment    models.Deployment
 
 		client *prometheus.Client
 	)
@@ -29,26 +33,254 @@ var _ = Describe("prometheus.Client", func() {
 	var _ = BeforeEach(func() {
 		_ = test.UnmarshalFixture("./../../../test/data/prometheus/query.json", &queryResponse)
 		mock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/query_range" || r.URL.Query().Get("step") != "300" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// only return the samples within the range queried
+			start, _ := strconv.ParseFloat(r.URL.Query().Get("start"), 64)
+			end, _ := strconv.ParseFloat(r.URL.Query().Get("end"), 64)
+			rangeResponse := queryResponse
+			rangeResponse.Data.Result = nil
+			for _, result := range queryResponse.Data.Result {
+				values := [][]interface{}{}
+				for _, value := range result.Values {
+					if value[0].(float64) >= start && value[0].(float64) <= end {
+						values = append(values, value)
+					}
+				}
+				result.Values = values
+				rangeResponse.Data.Result = append(rangeResponse.Data.Result, result)
+			}
+
 			w.WriteHeader(http.StatusOK)
-			json, _ := json.Marshal(queryResponse)
+			json, _ := json.Marshal(rangeResponse)
 			w.Write(json)
 		}))
 
-		query := "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}[6w]"
+		deployment = models.Deployment{
+			Query:     "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}",
+			Step:      300,
+			Relation:  models.RelationGreaterOrEqual,
+			Threshold: 1,
+		}
 
-		client = prometheus.NewClient(mock.URL, "", query)
+		client = prometheus.NewClient(mock.URL, "", &deployment)
 	})
 
 	var _ = AfterEach(func() {
 		defer mock.Close()
 	})
 
+	var _ = When("GetAlerts", func() {
+		It("gets all alerts since a given time", func() {
+			alerts, err := client.GetAlerts(time.Unix(1674551806, 0))
+			Expect(err).To(BeNil())
+			Expect(len(*alerts)).To(Equal(60))
+			Expect((*alerts)[0].CreatedAt).To(Equal(time.Unix(1674551806, 0)))
+		})
+	})
+
+	var _ = When("GetAlerts fails", func() {
+		It("returns a QueryError for a rejected query", func() {
+			rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"status": "error", "errorType": "bad_data", "error": "parse error"}`))
+			}))
+			defer rejecting.Close()
+
+			client = prometheus.NewClient(rejecting.URL, "", &deployment)
+			_, err := client.GetAlerts(time.Now().Add(-time.Hour))
+
+			var queryError *prometheus.QueryError
+			Expect(errors.As(err, &queryError)).To(BeTrue())
+			Expect(queryError.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(queryError.ErrorType).To(Equal("bad_data"))
+			Expect(queryError.Err).To(Equal("parse error"))
+		})
+
+		It("returns a StatusError for a non-2xx response without an error of the query", func() {
+			unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+				w.Write([]byte("bad gateway"))
+			}))
+			defer unavailable.Close()
+
+			client = prometheus.NewClient(unavailable.URL, "", &deployment)
+			_, err := client.GetAlerts(time.Now().Add(-time.Hour))
+
+			var statusError *prometheus.StatusError
+			Expect(errors.As(err, &statusError)).To(BeTrue())
+			Expect(statusError.StatusCode).To(Equal(http.StatusBadGateway))
+		})
+
+		It("returns a DecodeError for a malformed response", func() {
+			malformed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("{"))
+			}))
+			defer malformed.Close()
+
+			client = prometheus.NewClient(malformed.URL, "", &deployment)
+			_, err := client.GetAlerts(time.Now().Add(-time.Hour))
+
+			var decodeError *prometheus.DecodeError
+			Expect(errors.As(err, &decodeError)).To(BeTrue())
+		})
+
+		It("returns a ConnectionError if prometheus is down", func() {
+			mock.Close()
+
+			_, err := client.GetAlerts(time.Now().Add(-time.Hour))
+
+			var connectionError *prometheus.ConnectionError
+			Expect(errors.As(err, &connectionError)).To(BeTrue())
+		})
+	})
+
 	var _ = When("CreateAlerts", func() {
 		It("creates alerts from a query response", func() {
 			alerts, err := client.CreateAlerts(queryResponse)
 			Expect(err).To(BeNil())
 			Expect(len(*alerts)).To(Equal(62))
 			Expect((*alerts)[0].CreatedAt).To(Equal(time.Unix(1674486526, 0)))
+			Expect((*alerts)[0].Labels).To(Equal(queryResponse.Data.Result[0].Metric))
+		})
+
+		It("labels each alert with the labels of its series", func() {
+			var multiSeries prometheus.QueryResponse
+			_ = json.Unmarshal([]byte(`{"status": "success", "data": {"result": [
+				{"metric": {"pod": "api-1"}, "values": [[1000, "1"], [1300, "0"]]},
+				{"metric": {"pod": "api-2"}, "values": [[1000, "2"]]}
+			]}}`), &multiSeries)
+
+			alerts, err := client.CreateAlerts(multiSeries)
+			Expect(err).To(BeNil())
+			Expect(*alerts).To(Equal([]models.Alert{
+				{CreatedAt: time.Unix(1000, 0), Labels: map[string]string{"pod": "api-1"}},
+				{CreatedAt: time.Unix(1000, 0), Labels: map[string]string{"pod": "api-2"}},
+			}))
+		})
+
+		It("creates no alerts from samples not breaching the threshold", func() {
+			deployment.Relation = models.RelationGreater
+			client = prometheus.NewClient(mock.URL, "", &deployment)
+
+			alerts, err := client.CreateAlerts(queryResponse)
+			Expect(err).To(BeNil())
+			Expect(len(*alerts)).To(Equal(0))
+		})
+
+		It("returns a RelationError for an unsupported relation, even without samples", func() {
+			deployment.Relation = "greater"
+			client = prometheus.NewClient(mock.URL, "", &deployment)
+
+			_, err := client.CreateAlerts(prometheus.QueryResponse{})
+			var relationError *prometheus.RelationError
+			Expect(errors.As(err, &relationError)).To(BeTrue())
+		})
+	})
+
+	var _ = When("AlertsQuery", func() {
+		It("queries the ALERTS series firing and matching the label matchers", func() {
+			query := prometheus.AlertsQuery([]string{`alertname="TargetDown"`})
+			Expect(query).To(Equal(`ALERTS{alertstate="firing",alertname="TargetDown"}`))
+		})
+	})
+
+	var _ = When("CreateIncidents", func() {
+		It("creates an incident for each interval a series has samples, even across responses", func() {
+			var first, second prometheus.QueryResponse
+			_ = json.Unmarshal([]byte(`{"status": "success", "data": {"result": [
+				{"metric": {"alertname": "TargetDown"}, "values": [[1000, "1"], [1300, "1"], [1600, "1"]]},
+				{"metric": {"alertname": "HighLatency"}, "values": [[1300, "1"]]}
+			]}}`), &first)
+			_ = json.Unmarshal([]byte(`{"status": "success", "data": {"result": [
+				{"metric": {"alertname": "TargetDown"}, "values": [[1900, "1"], [4000, "1"]]}
+			]}}`), &second)
+
+			incidents, err := client.CreateIncidents([]prometheus.QueryResponse{first, second})
+			Expect(err).To(BeNil())
+			Expect(len(*incidents)).To(Equal(3))
+			Expect((*incidents)[0].StartDate).To(Equal(time.Unix(1000, 0)))
+			Expect((*incidents)[0].EndDate).To(Equal(time.Unix(1900, 0)))
+			Expect((*incidents)[0].Series).To(Equal([]map[string]string{{"alertname": "TargetDown"}}))
+			Expect((*incidents)[1].StartDate).To(Equal(time.Unix(1300, 0)))
+			Expect((*incidents)[1].EndDate).To(Equal(time.Unix(1300, 0)))
+			Expect((*incidents)[2].StartDate).To(Equal(time.Unix(4000, 0)))
+		})
+
+		It("returns a DecodeError for a malformed sample", func() {
+			var queryResponse prometheus.QueryResponse
+			_ = json.Unmarshal([]byte(`{"status": "success", "data": {"result": [
+				{"metric": {"alertname": "TargetDown"}, "values": [[]]}
+			]}}`), &queryResponse)
+
+			_, err := client.CreateIncidents([]prometheus.QueryResponse{queryResponse})
+			var decodeError *prometheus.DecodeError
+			Expect(errors.As(err, &decodeError)).To(BeTrue())
+		})
+	})
+
+	var _ = When("Breaches", func() {
+		It("evaluates a sample against the threshold", func() {
+			for relation, breaches := range map[string]bool{
+				models.RelationGreater:        false,
+				models.RelationGreaterOrEqual: true,
+				models.RelationLess:           false,
+				models.RelationLessOrEqual:    true,
+				models.RelationEqual:          true,
+				models.RelationNotEqual:       false,
+			} {
+				breach, err := prometheus.Breaches("0.2", relation, 0.2)
+				Expect(err).To(BeNil())
+				Expect(breach).To(Equal(breaches), relation)
+			}
+		})
+
+		It("breaches without a relation", func() {
+			breach, err := prometheus.Breaches("NaN", "", 0)
+			Expect(err).To(BeNil())
+			Expect(breach).To(BeTrue())
+		})
+
+		It("evaluates the legacy relations", func() {
+			breach, err := prometheus.Breaches("1", "gt", 0)
+			Expect(err).To(BeNil())
+			Expect(breach).To(BeTrue())
+
+			breach, err = prometheus.Breaches("1", "ne", 1)
+			Expect(err).To(BeNil())
+			Expect(breach).To(BeFalse())
+		})
+
+		It("returns a RelationError for an unsupported relation", func() {
+			_, err := prometheus.Breaches("1", "greater", 0)
+			var relationError *prometheus.RelationError
+			Expect(errors.As(err, &relationError)).To(BeTrue())
+		})
+	})
+
+	var _ = When("ValidateQuery", func() {
+		It("accepts queries evaluating to an instant vector", func() {
+			for _, query := range []string{
+				"job:http_total_requests:internal_server_error_percentage",
+				`rate(http_requests_total{code="500"}[5m])`,
+				`max_over_time(up[1h:5m]) offset 1d`,
+			} {
+				Expect(prometheus.ValidateQuery(query)).To(BeNil())
+			}
+		})
+
+		It("returns an error for queries evaluating to a range vector", func() {
+			for _, query := range []string{
+				"up[6w]",
+				`http_requests_total{code="500"}[5m]`,
+				"up[1h:5m] offset 1d",
+			} {
+				Expect(prometheus.ValidateQuery(query)).ToNot(BeNil())
+			}
 		})
 	})
 })
@@ -0,0 +1,67 @@
+package prometheus
+
+import "fmt"
+
+// ConnectionError reports a request to Prometheus or Alertmanager that could not be sent or whose response could not be read.
+type ConnectionError struct {
+	URI string
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("could not query %s: %s", e.URI, e.Err.Error())
+}
+
+// Unwrap returns the error of the failed request.
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError reports a response of Prometheus or Alertmanager with a non-2xx status code and without an error of the query.
+type StatusError struct {
+	URI        string
+	StatusCode int
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s responded with status %d", e.URI, e.StatusCode)
+}
+
+// QueryError reports a query rejected by Prometheus, e.g. a query that could not be parsed or timed out.
+type QueryError struct {
+	StatusCode int
+	ErrorType  string // e.g. bad_data, timeout or execution
+	Err        string
+}
+
+// Error implements the error interface.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("prometheus rejected the query with %s: %s", e.ErrorType, e.Err)
+}
+
+// RelationError reports a relation of a deployment which is not supported, hence no sample can be evaluated.
+type RelationError struct {
+	Relation string
+}
+
+// Error implements the error interface.
+func (e *RelationError) Error() string {
+	return fmt.Sprintf("unsupported relation: %s", e.Relation)
+}
+
+// DecodeError reports a response of Prometheus or Alertmanager that could not be decoded.
+type DecodeError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("could not decode response: %s", e.Err.Error())
+}
+
+// Unwrap returns the error of the failed decoding.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
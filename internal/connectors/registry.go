@@ -0,0 +1,106 @@
+package connectors
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+)
+
+// SourceControlFactory creates a SourceControl for an Integration.
+type SourceControlFactory func(integration *models.Integration) SourceControl
+
+// CIProviderFactory creates a CIProvider for an Integration.
+type CIProviderFactory func(integration *models.Integration) CIProvider
+
+// IncidentSourceFactory creates an IncidentSource for an Integration.
+type IncidentSourceFactory func(integration *models.Integration) IncidentSource
+
+// registry holds all factories, keyed on the provider of an Integration.
+var registry = struct {
+	sync.RWMutex
+	sourceControls  map[string]SourceControlFactory
+	ciProviders     map[string]CIProviderFactory
+	incidentSources map[string]IncidentSourceFactory
+}{
+	sourceControls:  map[string]SourceControlFactory{},
+	ciProviders:     map[string]CIProviderFactory{},
+	incidentSources: map[string]IncidentSourceFactory{},
+}
+
+// RegisterSourceControl registers a SourceControl for a provider.
+func RegisterSourceControl(provider string, factory SourceControlFactory) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.sourceControls[provider] = factory
+}
+
+// RegisterCIProvider registers a CIProvider for a provider.
+func RegisterCIProvider(provider string, factory CIProviderFactory) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.ciProviders[provider] = factory
+}
+
+// RegisterIncidentSource registers an IncidentSource for a provider.
+func RegisterIncidentSource(provider string, factory IncidentSourceFactory) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.incidentSources[provider] = factory
+}
+
+// NewSourceControl creates the SourceControl registered for the provider of an Integration.
+func NewSourceControl(integration *models.Integration) (SourceControl, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	factory, ok := registry.sourceControls[integration.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider for version control: %s", integration.Provider)
+	}
+	return factory(integration), nil
+}
+
+// NewCIProvider creates the CIProvider registered for the provider of an Integration.
+func NewCIProvider(integration *models.Integration) (CIProvider, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	factory, ok := registry.ciProviders[integration.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider for ci/cd: %s", integration.Provider)
+	}
+	return factory(integration), nil
+}
+
+// NewIncidentSource creates the IncidentSource registered for the provider of an Integration.
+func NewIncidentSource(integration *models.Integration) (IncidentSource, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	factory, ok := registry.incidentSources[integration.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider for incident management: %s", integration.Provider)
+	}
+	return factory(integration), nil
+}
+
+// Validate checks if a connector is registered for the type and provider of an Integration.
+// The legacy type of version control is accepted as well.
+func Validate(integration *models.Integration) error {
+	var err error
+	switch models.IntegrationType(integration.Type) {
+	case models.VersionControl:
+		_, err = NewSourceControl(integration)
+	case models.ContinuousIntegration:
+		_, err = NewCIProvider(integration)
+	case models.IncidentManagement:
+		_, err = NewIncidentSource(integration)
+	default:
+		err = fmt.Errorf("unsupported integration type: %s", integration.Type)
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package connectors_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/connectors"
+	"github.com/unnmdnwb3/dora/internal/models"
+)
+
+func TestConnectors(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "connectors Suite")
+}
+
+// fakeCIProvider is a CIProvider returning a fixed set of pipeline runs.
+type fakeCIProvider struct {
+	pipelineRuns []models.PipelineRun
+}
+
+func (f *fakeCIProvider) GetPipelineRuns(pipeline *models.Pipeline, since time.Time) (*[]models.PipelineRun, error) {
+	return &f.pipelineRuns, nil
+}
+
+var _ = Describe("connectors.Registry", func() {
+	var _ = When("NewSourceControl", func() {
+		It("creates the built-in connectors.", func() {
+			for _, provider := range []string{"gitlab", "github"} {
+				integration := models.Integration{
+					Type:     models.VersionControl,
+					Provider: provider,
+					URI:      "https://example.com",
+				}
+				sourceControl, err := connectors.NewSourceControl(&integration)
+				Expect(err).To(BeNil())
+				Expect(sourceControl).To(Not(BeNil()))
+			}
+		})
+
+		It("rejects unknown providers.", func() {
+			integration := models.Integration{
+				Type:     models.VersionControl,
+				Provider: "bitbucket",
+			}
+			_, err := connectors.NewSourceControl(&integration)
+			Expect(err).To(Not(BeNil()))
+		})
+	})
+
+	var _ = When("RegisterCIProvider", func() {
+		It("plugs in a new backend.", func() {
+			connectors.RegisterCIProvider("fake", func(integration *models.Integration) connectors.CIProvider {
+				return &fakeCIProvider{pipelineRuns: []models.PipelineRun{{ExternalID: 1}, {ExternalID: 2}}}
+			})
+
+			integration := models.Integration{
+				Type:     models.ContinuousIntegration,
+				Provider: "fake",
+			}
+			ciProvider, err := connectors.NewCIProvider(&integration)
+			Expect(err).To(BeNil())
+
+			pipelineRuns, err := ciProvider.GetPipelineRuns(&models.Pipeline{}, time.Now())
+			Expect(err).To(BeNil())
+			Expect(*pipelineRuns).To(HaveLen(2))
+		})
+	})
+
+	var _ = When("NewIncidentSource", func() {
+		It("creates the built-in alerting sources providing incidents themselves.", func() {
+			for _, provider := range []string{"alertmanager", "prometheus-alerts"} {
+				integration := models.Integration{
+					Type:     models.IncidentManagement,
+					Provider: provider,
+					URI:      "https://example.com",
+				}
+				incidentSource, err := connectors.NewIncidentSource(&integration)
+				Expect(err).To(BeNil())
+
+				_, ok := incidentSource.(connectors.IncidentProvider)
+				Expect(ok).To(BeTrue())
+			}
+		})
+	})
+
+	var _ = When("Validate", func() {
+		It("accepts registered combinations of type and provider.", func() {
+			integrations := []models.Integration{
+				{Type: models.VersionControl, Provider: "gitlab"},
+				{Type: models.ContinuousIntegration, Provider: "github"},
+				{Type: models.IncidentManagement, Provider: "prometheus"},
+			}
+			for _, integration := range integrations {
+				Expect(connectors.Validate(&integration)).To(BeNil())
+			}
+		})
+
+		It("accepts the legacy type of version control.", func() {
+			integration := models.Integration{Type: models.LegacySourceControl, Provider: "gitlab"}
+			Expect(connectors.Validate(&integration)).To(BeNil())
+		})
+
+		It("rejects unknown types and providers.", func() {
+			integrations := []models.Integration{
+				{Type: models.IncidentManagement, Provider: "gitlab"},
+				{Type: models.VersionControl, Provider: "prometheus"},
+				{Type: "unknown", Provider: "gitlab"},
+			}
+			for _, integration := range integrations {
+				Expect(connectors.Validate(&integration)).To(Not(BeNil()))
+			}
+		})
+	})
+})
@@ -2,9 +2,7 @@ package daos
 
 import (
 	"context"
-	"os"
 
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,89 +13,55 @@ import (
 const changeCollection = "changes"
 
 // CreateChange creates a new Change.
-func CreateChange(ctx context.Context, repositoryID primitive.ObjectID, change *models.Change) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) CreateChange(ctx context.Context, repositoryID primitive.ObjectID, change *models.Change) error {
 	change.RepositoryID = repositoryID
-	err = service.InsertOne(ctx, changeCollection, change)
+	err := s.Service.InsertOne(ctx, changeCollection, change)
 	return err
 }
 
-// CreateChanges creates many new Changes.
-func CreateChanges(ctx context.Context, repositoryID primitive.ObjectID, changes *[]models.Change) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	for index, change := range *changes {
+// CreateChanges creates many new Changes, or replaces the Changes deployed at the same time by the pipeline of the repository.
+// They are written in ordered batches, and each of the Changes written gets its ID set.
+func (s *Store) CreateChanges(ctx context.Context, repositoryID primitive.ObjectID, changes *[]models.Change) error {
+	filters := make([]bson.M, len(*changes))
+	documents := make([]any, len(*changes))
+	for index := range *changes {
+		change := &(*changes)[index]
 		change.RepositoryID = repositoryID
 
-		err = service.InsertOne(ctx, changeCollection, &change)
-		if err != nil {
-			return err
-		}
-		(*changes)[index] = change
+		filters[index] = bson.M{"repository_id": repositoryID, "pipeline_id": change.PipelineID, "deployment_date": change.DeploymentDate}
+		documents[index] = change
 	}
-	return nil
-}
 
-// GetChange retrieves an Change.
-func GetChange(ctx context.Context, changeID primitive.ObjectID, change *models.Change) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
+	ids, err := s.Service.UpsertMany(ctx, changeCollection, filters, documents)
+	for index, id := range ids {
+		(*changes)[index].ID = id
 	}
-	defer service.Disconnect(ctx)
+	return err
+}
 
-	err = service.FindOneByID(ctx, changeCollection, changeID, change)
+// GetChange retrieves an Change.
+func (s *Store) GetChange(ctx context.Context, changeID primitive.ObjectID, change *models.Change) error {
+	err := s.Service.FindOneByID(ctx, changeCollection, changeID, change)
 	return err
 }
 
 // ListChanges retrieves many Changes.
-func ListChanges(ctx context.Context, repositoryID primitive.ObjectID, changes *[]models.Change) error {
+func (s *Store) ListChanges(ctx context.Context, repositoryID primitive.ObjectID, changes *[]models.Change) error {
 	filter := bson.M{"repository_id": repositoryID}
-	err := ListChangesByFilter(ctx, filter, changes)
+	err := s.ListChangesByFilter(ctx, filter, changes)
 	return err
 }
 
 // ListChangesByFilter retrieves many Changes conforming to a filter.
-func ListChangesByFilter(ctx context.Context, filter bson.M, changes *[]models.Change) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) ListChangesByFilter(ctx context.Context, filter bson.M, changes *[]models.Change) error {
 	ops := options.Find().SetSort(bson.M{"first_commit_date": 1})
-	err = service.Find(ctx, changeCollection, filter, changes, ops)
+	err := s.Service.Find(ctx, changeCollection, filter, changes, ops)
 	return err
 }
 
 // UpdateChange updates an Change.
-func UpdateChange(ctx context.Context, changeID primitive.ObjectID, change *models.Change) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.UpdateOne(ctx, changeCollection, changeID, &change)
+func (s *Store) UpdateChange(ctx context.Context, changeID primitive.ObjectID, change *models.Change) error {
+	err := s.Service.UpdateOne(ctx, changeCollection, changeID, &change)
 	if err != nil {
 		return err
 	}
@@ -107,15 +71,13 @@ func UpdateChange(ctx context.Context, changeID primitive.ObjectID, change *mode
 }
 
 // DeleteChange deletes an Change.
-func DeleteChange(ctx context.Context, changeID primitive.ObjectID) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
+func (s *Store) DeleteChange(ctx context.Context, changeID primitive.ObjectID) error {
+	err := s.Service.DeleteOne(ctx, changeCollection, changeID)
+	return err
+}
 
-	err = service.DeleteOne(ctx, changeCollection, changeID)
+// DeleteChangesByFilter deletes many Changes conforming to a filter.
+func (s *Store) DeleteChangesByFilter(ctx context.Context, filter bson.M) error {
+	err := s.Service.DeleteMany(ctx, changeCollection, filter)
 	return err
 }
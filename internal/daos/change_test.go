@@ -6,7 +6,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -23,7 +22,7 @@ var _ = Describe("daos.Change", func() {
 				FirstCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				DeploymentDate:  time.Date(2022, 12, 27, 13, 16, 21, 0, time.UTC),
 			}
-			err := daos.CreateChange(ctx, repositoryID, &change)
+			err := store.CreateChange(ctx, repositoryID, &change)
 			Expect(err).To(BeNil())
 			Expect(change.ID).To(Not(BeEmpty()))
 		})
@@ -44,13 +43,50 @@ var _ = Describe("daos.Change", func() {
 					DeploymentDate:  time.Date(2022, 12, 27, 14, 59, 34, 0, time.UTC),
 				},
 			}
-			err := daos.CreateChanges(ctx, repositoryID, &changes)
+			err := store.CreateChanges(ctx, repositoryID, &changes)
 			Expect(err).To(BeNil())
 			Expect(changes[0].ID).To(Not(BeEmpty()))
 			Expect(changes[1].ID).To(Not(BeEmpty()))
 		})
 	})
 
+	var _ = When("CreateChanges of a pipeline twice", func() {
+		It("replaces the Changes deployed at the same time.", func() {
+			repositoryID := primitive.NewObjectID()
+			pipelineID := primitive.NewObjectID()
+			changes := []models.Change{
+				{
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
+				},
+			}
+			err := store.CreateChanges(ctx, repositoryID, &changes)
+			Expect(err).To(BeNil())
+
+			changes = []models.Change{
+				{
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 27, 12, 16, 42, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
+				},
+				{
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 27, 14, 51, 21, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 27, 14, 59, 34, 0, time.UTC),
+				},
+			}
+			err = store.CreateChanges(ctx, repositoryID, &changes)
+			Expect(err).To(BeNil())
+
+			var findChanges []models.Change
+			err = store.ListChanges(ctx, repositoryID, &findChanges)
+			Expect(err).To(BeNil())
+			Expect(findChanges).To(HaveLen(2))
+			Expect(findChanges[0].FirstCommitDate).To(Equal(time.Date(2022, 12, 27, 12, 16, 42, 0, time.UTC)))
+		})
+	})
+
 	var _ = When("GetChange", func() {
 		It("retrieves an Change.", func() {
 			repositoryID := primitive.NewObjectID()
@@ -59,12 +95,12 @@ var _ = Describe("daos.Change", func() {
 				FirstCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				DeploymentDate:  time.Date(2022, 12, 27, 13, 16, 21, 0, time.UTC),
 			}
-			err := daos.CreateChange(ctx, repositoryID, &change)
+			err := store.CreateChange(ctx, repositoryID, &change)
 			Expect(err).To(BeNil())
 			Expect(change.ID).To(Not(BeEmpty()))
 
 			var findChange models.Change
-			err = daos.GetChange(ctx, change.ID, &findChange)
+			err = store.GetChange(ctx, change.ID, &findChange)
 			Expect(err).To(BeNil())
 			Expect(findChange.ID).To(Equal(change.ID))
 		})
@@ -90,13 +126,13 @@ var _ = Describe("daos.Change", func() {
 					DeploymentDate:  time.Date(2022, 12, 28, 21, 45, 46, 0, time.UTC),
 				},
 			}
-			err := daos.CreateChanges(ctx, repositoryID, &changes)
+			err := store.CreateChanges(ctx, repositoryID, &changes)
 			Expect(changes[0].ID).To(Not(BeNil()))
 			Expect(changes[1].ID).To(Not(BeNil()))
 			Expect(changes[2].ID).To(Not(BeNil()))
 
 			var findChanges []models.Change
-			err = daos.ListChanges(ctx, repositoryID, &findChanges)
+			err = store.ListChanges(ctx, repositoryID, &findChanges)
 			Expect(err).To(BeNil())
 			Expect(findChanges).To(HaveLen(3))
 		})
@@ -123,7 +159,7 @@ var _ = Describe("daos.Change", func() {
 				},
 			}
 
-			err := daos.CreateChanges(ctx, repositoryID, &changes)
+			err := store.CreateChanges(ctx, repositoryID, &changes)
 			Expect(err).To(BeNil())
 			Expect(changes[0].ID).To(Not(BeNil()))
 			Expect(changes[1].ID).To(Not(BeNil()))
@@ -131,7 +167,7 @@ var _ = Describe("daos.Change", func() {
 
 			var findChanges []models.Change
 			filter := bson.M{"repository_id": repositoryID}
-			err = daos.ListChangesByFilter(ctx, filter, &findChanges)
+			err = store.ListChangesByFilter(ctx, filter, &findChanges)
 			Expect(err).To(BeNil())
 			Expect(findChanges).To(HaveLen(3))
 		})
@@ -145,7 +181,7 @@ var _ = Describe("daos.Change", func() {
 				FirstCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				DeploymentDate:  time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
 			}
-			err := daos.CreateChange(ctx, repositoryID, &change)
+			err := store.CreateChange(ctx, repositoryID, &change)
 			Expect(err).To(BeNil())
 			Expect(change.ID).To(Not(BeEmpty()))
 
@@ -154,7 +190,7 @@ var _ = Describe("daos.Change", func() {
 				FirstCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				DeploymentDate:  time.Date(2022, 12, 27, 13, 26, 42, 0, time.UTC),
 			}
-			err = daos.UpdateChange(ctx, change.ID, &updateChange)
+			err = store.UpdateChange(ctx, change.ID, &updateChange)
 			Expect(err).To(BeNil())
 			Expect(updateChange.DeploymentDate).To(Equal(time.Date(2022, 12, 27, 13, 26, 42, 0, time.UTC)))
 		})
@@ -168,15 +204,15 @@ var _ = Describe("daos.Change", func() {
 				FirstCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				DeploymentDate:  time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
 			}
-			err := daos.CreateChange(ctx, repositoryID, &change)
+			err := store.CreateChange(ctx, repositoryID, &change)
 			Expect(err).To(BeNil())
 			Expect(change.ID).To(Not(BeEmpty()))
 
-			err = daos.DeleteChange(ctx, change.ID)
+			err = store.DeleteChange(ctx, change.ID)
 			Expect(err).To(BeNil())
 
 			var findChange models.Change
-			err = daos.GetChange(ctx, change.ID, &findChange)
+			err = store.GetChange(ctx, change.ID, &findChange)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -2,9 +2,7 @@ package daos
 
 import (
 	"context"
-	"os"
 
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,93 +12,60 @@ import (
 // default changesPerDayCollection
 const changesPerDayCollection = "changes_per_days"
 
-// CreateChangesPerDay creates a new ChangesPerDay.
-func CreateChangesPerDay(ctx context.Context, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID, changesPerDay *models.ChangesPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+// CreateChangesPerDay creates a new ChangesPerDay, or replaces the ChangesPerDay of the same date.
+func (s *Store) CreateChangesPerDay(ctx context.Context, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID, changesPerDay *models.ChangesPerDay) error {
 	changesPerDay.RepositoryID = repositoryID
 	changesPerDay.PipelineID = pipelineID
 
-	err = service.InsertOne(ctx, changesPerDayCollection, changesPerDay)
+	filter := bson.M{"repository_id": repositoryID, "pipeline_id": pipelineID, "date": changesPerDay.Date}
+	err := s.Service.UpsertOne(ctx, changesPerDayCollection, filter, changesPerDay)
 	return err
 }
 
-// CreateChangesPerDays creates many new ChangesPerDay.
-func CreateChangesPerDays(ctx context.Context, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID, changesPerDays *[]models.ChangesPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	for index, changesPerDay := range *changesPerDays {
+// CreateChangesPerDays creates many new ChangesPerDay, or replaces the ChangesPerDay of the same dates.
+// They are written in ordered batches, and each of the ChangesPerDay written gets its ID set.
+func (s *Store) CreateChangesPerDays(ctx context.Context, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID, changesPerDays *[]models.ChangesPerDay) error {
+	filters := make([]bson.M, len(*changesPerDays))
+	documents := make([]any, len(*changesPerDays))
+	for index := range *changesPerDays {
+		changesPerDay := &(*changesPerDays)[index]
 		changesPerDay.RepositoryID = repositoryID
 		changesPerDay.PipelineID = pipelineID
 
-		err = service.InsertOne(ctx, changesPerDayCollection, &changesPerDay)
-		if err != nil {
-			return err
-		}
-		(*changesPerDays)[index] = changesPerDay
+		filters[index] = bson.M{"repository_id": repositoryID, "pipeline_id": pipelineID, "date": changesPerDay.Date}
+		documents[index] = changesPerDay
 	}
-	return nil
-}
 
-// GetChangesPerDay retrieves a ChangesPerDay.
-func GetChangesPerDay(ctx context.Context, changesPerDayID primitive.ObjectID, changesPerDay *models.ChangesPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
+	ids, err := s.Service.UpsertMany(ctx, changesPerDayCollection, filters, documents)
+	for index, id := range ids {
+		(*changesPerDays)[index].ID = id
 	}
-	defer service.Disconnect(ctx)
+	return err
+}
 
-	err = service.FindOneByID(ctx, changesPerDayCollection, changesPerDayID, changesPerDay)
+// GetChangesPerDay retrieves a ChangesPerDay.
+func (s *Store) GetChangesPerDay(ctx context.Context, changesPerDayID primitive.ObjectID, changesPerDay *models.ChangesPerDay) error {
+	err := s.Service.FindOneByID(ctx, changesPerDayCollection, changesPerDayID, changesPerDay)
 	return err
 }
 
 // ListChangesPerDays retrieves many ChangesPerDay.
-func ListChangesPerDays(ctx context.Context, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID, changesPerDay *[]models.ChangesPerDay) error {
+func (s *Store) ListChangesPerDays(ctx context.Context, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID, changesPerDay *[]models.ChangesPerDay) error {
 	filter := bson.M{"repository_id": repositoryID, "pipeline_id": pipelineID}
-	err := ListChangesPerDaysByFilter(ctx, filter, changesPerDay)
+	err := s.ListChangesPerDaysByFilter(ctx, filter, changesPerDay)
 	return err
 }
 
 // ListChangesPerDaysByFilter retrieves many ChangesPerDay conforming to a filter.
-func ListChangesPerDaysByFilter(ctx context.Context, filter bson.M, changesPerDay *[]models.ChangesPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) ListChangesPerDaysByFilter(ctx context.Context, filter bson.M, changesPerDay *[]models.ChangesPerDay) error {
 	ops := options.Find().SetSort(bson.M{"date": 1})
-	err = service.Find(ctx, changesPerDayCollection, filter, changesPerDay, ops)
+	err := s.Service.Find(ctx, changesPerDayCollection, filter, changesPerDay, ops)
 	return err
 }
 
 // UpdateChangesPerDay updates a ChangesPerDay.
-func UpdateChangesPerDay(ctx context.Context, changesPerDayID primitive.ObjectID, changesPerDay *models.ChangesPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.UpdateOne(ctx, changesPerDayCollection, changesPerDayID, &changesPerDay)
+func (s *Store) UpdateChangesPerDay(ctx context.Context, changesPerDayID primitive.ObjectID, changesPerDay *models.ChangesPerDay) error {
+	err := s.Service.UpdateOne(ctx, changesPerDayCollection, changesPerDayID, &changesPerDay)
 	if err != nil {
 		return err
 	}
@@ -110,15 +75,13 @@ func UpdateChangesPerDay(ctx context.Context, changesPerDayID primitive.ObjectID
 }
 
 // DeleteChangesPerDay deletes a ChangesPerDay.
-func DeleteChangesPerDay(ctx context.Context, changesPerDayID primitive.ObjectID) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
+func (s *Store) DeleteChangesPerDay(ctx context.Context, changesPerDayID primitive.ObjectID) error {
+	err := s.Service.DeleteOne(ctx, changesPerDayCollection, changesPerDayID)
+	return err
+}
 
-	err = service.DeleteOne(ctx, changesPerDayCollection, changesPerDayID)
+// DeleteChangesPerDaysByFilter deletes many ChangesPerDay conforming to a filter.
+func (s *Store) DeleteChangesPerDaysByFilter(ctx context.Context, filter bson.M) error {
+	err := s.Service.DeleteMany(ctx, changesPerDayCollection, filter)
 	return err
 }
@@ -6,7 +6,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -26,7 +25,7 @@ var _ = Describe("daos.ChangesPerDays", func() {
 				TotalChanges:  1,
 				TotalLeadTime: 300,
 			}
-			err := daos.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
+			err := store.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
 			Expect(err).To(BeNil())
 			Expect(changesPerDay.ID).To(Not(BeEmpty()))
 		})
@@ -53,7 +52,7 @@ var _ = Describe("daos.ChangesPerDays", func() {
 					TotalLeadTime: 450,
 				},
 			}
-			err := daos.CreateChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
+			err := store.CreateChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
 			Expect(err).To(BeNil())
 			Expect(changesPerDays[0].ID).To(Not(BeEmpty()))
 			Expect(changesPerDays[1].ID).To(Not(BeEmpty()))
@@ -71,12 +70,12 @@ var _ = Describe("daos.ChangesPerDays", func() {
 				TotalChanges:  1,
 				TotalLeadTime: 300,
 			}
-			err := daos.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
+			err := store.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
 			Expect(err).To(BeNil())
 			Expect(changesPerDay.ID).To(Not(BeEmpty()))
 
 			var findChangesPerDay models.ChangesPerDay
-			err = daos.GetChangesPerDay(ctx, changesPerDay.ID, &findChangesPerDay)
+			err = store.GetChangesPerDay(ctx, changesPerDay.ID, &findChangesPerDay)
 			Expect(err).To(BeNil())
 			Expect(findChangesPerDay.ID).To(Equal(changesPerDay.ID))
 		})
@@ -103,11 +102,11 @@ var _ = Describe("daos.ChangesPerDays", func() {
 					TotalLeadTime: 450,
 				},
 			}
-			err := daos.CreateChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
+			err := store.CreateChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
 			Expect(err).To(BeNil())
 
 			var findChangesPerDays []models.ChangesPerDay
-			err = daos.ListChangesPerDays(ctx, repositoryID, pipelineID, &findChangesPerDays)
+			err = store.ListChangesPerDays(ctx, repositoryID, pipelineID, &findChangesPerDays)
 			Expect(err).To(BeNil())
 			Expect(len(findChangesPerDays)).To(Equal(2))
 		})
@@ -134,13 +133,13 @@ var _ = Describe("daos.ChangesPerDays", func() {
 					TotalLeadTime: 450,
 				},
 			}
-			err := daos.CreateChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
+			err := store.CreateChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
 			Expect(err).To(BeNil())
 
 			var findChangesPerDays []models.ChangesPerDay
 			date := time.Date(2022, 12, 27, 0, 0, 1, 0, time.UTC)
 			filter := bson.M{"date": bson.M{"$gte": date}}
-			err = daos.ListChangesPerDaysByFilter(ctx, filter, &findChangesPerDays)
+			err = store.ListChangesPerDaysByFilter(ctx, filter, &findChangesPerDays)
 			Expect(err).To(BeNil())
 			Expect(findChangesPerDays).To(HaveLen(1))
 		})
@@ -157,7 +156,7 @@ var _ = Describe("daos.ChangesPerDays", func() {
 				TotalChanges:  1,
 				TotalLeadTime: 300,
 			}
-			err := daos.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
+			err := store.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
 			Expect(err).To(BeNil())
 			Expect(changesPerDay.ID).To(Not(BeEmpty()))
 
@@ -167,7 +166,7 @@ var _ = Describe("daos.ChangesPerDays", func() {
 				TotalChanges:  2,
 				TotalLeadTime: 600,
 			}
-			err = daos.UpdateChangesPerDay(ctx, changesPerDay.ID, &updateChangesPerDay)
+			err = store.UpdateChangesPerDay(ctx, changesPerDay.ID, &updateChangesPerDay)
 			Expect(err).To(BeNil())
 			Expect(updateChangesPerDay.TotalChanges).To(Equal(2))
 		})
@@ -184,15 +183,15 @@ var _ = Describe("daos.ChangesPerDays", func() {
 				TotalChanges:  1,
 				TotalLeadTime: 300,
 			}
-			err := daos.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
+			err := store.CreateChangesPerDay(ctx, repositoryID, pipelineID, &changesPerDay)
 			Expect(err).To(BeNil())
 			Expect(changesPerDay.ID).To(Not(BeEmpty()))
 
-			err = daos.DeleteChangesPerDay(ctx, changesPerDay.ID)
+			err = store.DeleteChangesPerDay(ctx, changesPerDay.ID)
 			Expect(err).To(BeNil())
 
 			var findChangesPerDay models.ChangesPerDay
-			err = daos.GetChangesPerDay(ctx, changesPerDay.ID, &findChangesPerDay)
+			err = store.GetChangesPerDay(ctx, changesPerDay.ID, &findChangesPerDay)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
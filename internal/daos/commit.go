@@ -2,9 +2,7 @@ package daos
 
 import (
 	"context"
-	"os"
 
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,90 +12,57 @@ import (
 // default commitCollection
 const commitCollection = "commits"
 
-// CreateCommit creates a new Commit.
-func CreateCommit(ctx context.Context, repositoryID primitive.ObjectID, commit *models.Commit) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+// CreateCommit creates a new Commit, or replaces the Commit with the same sha in the repository.
+func (s *Store) CreateCommit(ctx context.Context, repositoryID primitive.ObjectID, commit *models.Commit) error {
 	commit.RepositoryID = repositoryID
-	err = service.InsertOne(ctx, commitCollection, commit)
+	filter := bson.M{"repository_id": repositoryID, "sha": commit.Sha}
+	err := s.Service.UpsertOne(ctx, commitCollection, filter, commit)
 	return err
 }
 
-// CreateCommits creates many new Commits.
-func CreateCommits(ctx context.Context, repositoryID primitive.ObjectID, commits *[]models.Commit) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	for index, commit := range *commits {
+// CreateCommits creates many new Commits, or replaces the Commits with the same sha in the repository.
+// They are written in ordered batches, and each of the Commits written gets its ID set.
+func (s *Store) CreateCommits(ctx context.Context, repositoryID primitive.ObjectID, commits *[]models.Commit) error {
+	filters := make([]bson.M, len(*commits))
+	documents := make([]any, len(*commits))
+	for index := range *commits {
+		commit := &(*commits)[index]
 		commit.RepositoryID = repositoryID
 
-		err = service.InsertOne(ctx, commitCollection, &commit)
-		if err != nil {
-			return err
-		}
-		(*commits)[index] = commit
+		filters[index] = bson.M{"repository_id": repositoryID, "sha": commit.Sha}
+		documents[index] = commit
 	}
-	return nil
-}
 
-// GetCommit retrieves an Commit.
-func GetCommit(ctx context.Context, commitID primitive.ObjectID, commit *models.Commit) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
+	ids, err := s.Service.UpsertMany(ctx, commitCollection, filters, documents)
+	for index, id := range ids {
+		(*commits)[index].ID = id
 	}
-	defer service.Disconnect(ctx)
+	return err
+}
 
-	err = service.FindOneByID(ctx, commitCollection, commitID, commit)
+// GetCommit retrieves an Commit.
+func (s *Store) GetCommit(ctx context.Context, commitID primitive.ObjectID, commit *models.Commit) error {
+	err := s.Service.FindOneByID(ctx, commitCollection, commitID, commit)
 	return err
 }
 
 // ListCommits retrieves many Commits.
-func ListCommits(ctx context.Context, repositoryID primitive.ObjectID, commits *[]models.Commit) error {
+func (s *Store) ListCommits(ctx context.Context, repositoryID primitive.ObjectID, commits *[]models.Commit) error {
 	filter := bson.M{"repository_id": repositoryID}
-	err := ListCommitsByFilter(ctx, filter, commits)
+	err := s.ListCommitsByFilter(ctx, filter, commits)
 	return err
 }
 
 // ListCommitsByFilter retrieves many Commits conforming to a filter.
-func ListCommitsByFilter(ctx context.Context, filter bson.M, commits *[]models.Commit) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) ListCommitsByFilter(ctx context.Context, filter bson.M, commits *[]models.Commit) error {
 	ops := options.Find().SetSort(bson.M{"created_at": 1})
-	err = service.Find(ctx, commitCollection, filter, commits, ops)
+	err := s.Service.Find(ctx, commitCollection, filter, commits, ops)
 	return err
 }
 
 // UpdateCommit updates an Commit.
-func UpdateCommit(ctx context.Context, commitID primitive.ObjectID, commit *models.Commit) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.UpdateOne(ctx, commitCollection, commitID, &commit)
+func (s *Store) UpdateCommit(ctx context.Context, commitID primitive.ObjectID, commit *models.Commit) error {
+	err := s.Service.UpdateOne(ctx, commitCollection, commitID, &commit)
 	if err != nil {
 		return err
 	}
@@ -107,15 +72,7 @@ func UpdateCommit(ctx context.Context, commitID primitive.ObjectID, commit *mode
 }
 
 // DeleteCommit deletes an Commit.
-func DeleteCommit(ctx context.Context, commitID primitive.ObjectID) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.DeleteOne(ctx, commitCollection, commitID)
+func (s *Store) DeleteCommit(ctx context.Context, commitID primitive.ObjectID) error {
+	err := s.Service.DeleteOne(ctx, commitCollection, commitID)
 	return err
 }
@@ -6,7 +6,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -27,7 +26,7 @@ var _ = Describe("daos.Commit", func() {
 					"3d95fe3bf954501d3832e50fdd803c5f9eae3f94",
 				},
 			}
-			err := daos.CreateCommit(ctx, repositoryID, &commit)
+			err := store.CreateCommit(ctx, repositoryID, &commit)
 			Expect(err).To(BeNil())
 			Expect(commit.ID).To(Not(BeEmpty()))
 		})
@@ -55,7 +54,7 @@ var _ = Describe("daos.Commit", func() {
 					},
 				},
 			}
-			err := daos.CreateCommits(ctx, repositoryID, &commits)
+			err := store.CreateCommits(ctx, repositoryID, &commits)
 			Expect(err).To(BeNil())
 			Expect(commits[0].ID).To(Not(BeEmpty()))
 			Expect(commits[1].ID).To(Not(BeEmpty()))
@@ -74,17 +73,50 @@ var _ = Describe("daos.Commit", func() {
 					"3d95fe3bf954501d3832e50fdd803c5f9eae3f94",
 				},
 			}
-			err := daos.CreateCommit(ctx, repositoryID, &commit)
+			err := store.CreateCommit(ctx, repositoryID, &commit)
 			Expect(err).To(BeNil())
 			Expect(commit.ID).To(Not(BeEmpty()))
 
 			var findCommit models.Commit
-			err = daos.GetCommit(ctx, commit.ID, &findCommit)
+			err = store.GetCommit(ctx, commit.ID, &findCommit)
 			Expect(err).To(BeNil())
 			Expect(findCommit.ID).To(Equal(commit.ID))
 		})
 	})
 
+	var _ = When("CreateCommits", func() {
+		It("replaces Commits with the same sha instead of creating them again.", func() {
+			repositoryID := primitive.NewObjectID()
+			commits := []models.Commit{
+				{
+					CreatedAt:  time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+					Sha:        "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+					ParentShas: []string{"487d6aedb92ab76bdc03957aceece75db906796e"},
+				},
+			}
+			err := store.CreateCommits(ctx, repositoryID, &commits)
+			Expect(err).To(BeNil())
+			Expect(commits[0].ID).To(Not(BeEmpty()))
+
+			moreCommits := []models.Commit{
+				commits[0],
+				{
+					CreatedAt:  time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
+					Sha:        "487d6aedb92ab76bdc03957aceece75db906796e",
+					ParentShas: []string{"398dc0ca313035ea4eb7ab3f29a5500631660fb7"},
+				},
+			}
+			err = store.CreateCommits(ctx, repositoryID, &moreCommits)
+			Expect(err).To(BeNil())
+			Expect(moreCommits[0].ID).To(Equal(commits[0].ID))
+
+			var findCommits []models.Commit
+			err = store.ListCommits(ctx, repositoryID, &findCommits)
+			Expect(err).To(BeNil())
+			Expect(findCommits).To(HaveLen(2))
+		})
+	})
+
 	var _ = When("ListCommits", func() {
 		It("retrieves many Commits.", func() {
 			repositoryID := primitive.NewObjectID()
@@ -107,13 +139,13 @@ var _ = Describe("daos.Commit", func() {
 					},
 				},
 			}
-			err := daos.CreateCommits(ctx, repositoryID, &commits)
+			err := store.CreateCommits(ctx, repositoryID, &commits)
 			Expect(err).To(BeNil())
 			Expect(commits[0].ID).To(Not(BeNil()))
 			Expect(commits[1].ID).To(Not(BeNil()))
 
 			var findCommits []models.Commit
-			err = daos.ListCommits(ctx, repositoryID, &findCommits)
+			err = store.ListCommits(ctx, repositoryID, &findCommits)
 			Expect(err).To(BeNil())
 			Expect(len(findCommits)).To(Equal(2))
 		})
@@ -143,14 +175,14 @@ var _ = Describe("daos.Commit", func() {
 				},
 			}
 
-			err := daos.CreateCommits(ctx, repositoryID, &commits)
+			err := store.CreateCommits(ctx, repositoryID, &commits)
 			Expect(err).To(BeNil())
 			Expect(commits[0].ID).To(Not(BeNil()))
 			Expect(commits[1].ID).To(Not(BeNil()))
 
 			var findCommits []models.Commit
 			filter := bson.M{"repository_id": repositoryID}
-			err = daos.ListCommitsByFilter(ctx, filter, &findCommits)
+			err = store.ListCommitsByFilter(ctx, filter, &findCommits)
 			Expect(err).To(BeNil())
 			Expect(findCommits).To(HaveLen(2))
 		})
@@ -168,7 +200,7 @@ var _ = Describe("daos.Commit", func() {
 					"3d95fe3bf954501d3832e50fdd803c5f9eae3f94",
 				},
 			}
-			err := daos.CreateCommit(ctx, repositoryID, &commit)
+			err := store.CreateCommit(ctx, repositoryID, &commit)
 			Expect(err).To(BeNil())
 			Expect(commit.ID).To(Not(BeEmpty()))
 
@@ -181,7 +213,7 @@ var _ = Describe("daos.Commit", func() {
 					"3d95fe3bf954501d3832e50fdd803c5f9eae3f94",
 				},
 			}
-			err = daos.UpdateCommit(ctx, commit.ID, &updateCommit)
+			err = store.UpdateCommit(ctx, commit.ID, &updateCommit)
 			Expect(err).To(BeNil())
 			Expect(updateCommit.CreatedAt).To(Equal(time.Date(2022, 12, 27, 13, 26, 42, 0, time.UTC)))
 		})
@@ -199,15 +231,15 @@ var _ = Describe("daos.Commit", func() {
 					"3d95fe3bf954501d3832e50fdd803c5f9eae3f94",
 				},
 			}
-			err := daos.CreateCommit(ctx, repositoryID, &commit)
+			err := store.CreateCommit(ctx, repositoryID, &commit)
 			Expect(err).To(BeNil())
 			Expect(commit.ID).To(Not(BeEmpty()))
 
-			err = daos.DeleteCommit(ctx, commit.ID)
+			err = store.DeleteCommit(ctx, commit.ID)
 			Expect(err).To(BeNil())
 
 			var findCommit models.Commit
-			err = daos.GetCommit(ctx, commit.ID, &findCommit)
+			err = store.GetCommit(ctx, commit.ID, &findCommit)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -5,7 +5,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -36,7 +35,7 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline,
 				Deployment: deployment,
 			}
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 			Expect(dataflow.ID).To(Not(BeEmpty()))
 			Expect(dataflow.Repository.ID).To(Not(BeEmpty()))
@@ -67,12 +66,12 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline,
 				Deployment: deployment,
 			}
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 			Expect(dataflow.ID).To(Not(BeEmpty()))
 
 			var findDataflow models.Dataflow
-			err = daos.GetDataflow(ctx, dataflow.ID, &findDataflow)
+			err = store.GetDataflow(ctx, dataflow.ID, &findDataflow)
 			Expect(err).To(BeNil())
 			Expect(findDataflow.ID).To(Equal(dataflow.ID))
 		})
@@ -100,7 +99,7 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline1,
 				Deployment: deployment1,
 			}
-			_ = daos.CreateDataflow(ctx, &dataflow1)
+			_ = store.CreateDataflow(ctx, &dataflow1)
 			Expect(dataflow1.ID).To(Not(BeNil()))
 
 			repository2 := models.Repository{
@@ -123,11 +122,11 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline2,
 				Deployment: deployment2,
 			}
-			_ = daos.CreateDataflow(ctx, &dataflow2)
+			_ = store.CreateDataflow(ctx, &dataflow2)
 			Expect(dataflow2.ID).To(Not(BeNil()))
 
 			var findDataflows []models.Dataflow
-			err := daos.ListDataflows(ctx, &findDataflows)
+			err := store.ListDataflows(ctx, &findDataflows)
 			Expect(err).To(BeNil())
 			Expect(findDataflows).To(HaveLen(2))
 		})
@@ -155,7 +154,7 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline1,
 				Deployment: deployment1,
 			}
-			_ = daos.CreateDataflow(ctx, &dataflow1)
+			_ = store.CreateDataflow(ctx, &dataflow1)
 			Expect(dataflow1.ID).To(Not(BeNil()))
 
 			repository2 := models.Repository{
@@ -178,11 +177,11 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline2,
 				Deployment: deployment2,
 			}
-			_ = daos.CreateDataflow(ctx, &dataflow2)
+			_ = store.CreateDataflow(ctx, &dataflow2)
 			Expect(dataflow2.ID).To(Not(BeNil()))
 
 			var findDataflows []models.Dataflow
-			err := daos.ListDataflows(ctx, &findDataflows)
+			err := store.ListDataflows(ctx, &findDataflows)
 			Expect(err).To(BeNil())
 			Expect(findDataflows).To(HaveLen(2))
 		})
@@ -210,7 +209,7 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline,
 				Deployment: deployment,
 			}
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 			Expect(dataflow.ID).To(Not(BeEmpty()))
 
@@ -223,7 +222,7 @@ var _ = Describe("daos.Dataflow", func() {
 				Deployment: newDeployment,
 			}
 
-			err = daos.UpdateDataflow(ctx, dataflow.ID, &updateDataflow)
+			err = store.UpdateDataflow(ctx, dataflow.ID, &updateDataflow)
 			Expect(err).To(BeNil())
 			Expect(updateDataflow.Deployment.IntegrationID).To(Equal(newDeployment.IntegrationID))
 		})
@@ -251,15 +250,15 @@ var _ = Describe("daos.Dataflow", func() {
 				Pipeline:   pipeline,
 				Deployment: deployment,
 			}
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 			Expect(dataflow.ID).To(Not(BeEmpty()))
 
-			err = daos.DeleteDataflow(ctx, dataflow.ID)
+			err = store.DeleteDataflow(ctx, dataflow.ID)
 			Expect(err).To(BeNil())
 
 			var findDataflow models.Dataflow
-			err = daos.GetDataflow(ctx, dataflow.ID, &findDataflow)
+			err = store.GetDataflow(ctx, dataflow.ID, &findDataflow)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -2,9 +2,7 @@ package daos
 
 import (
 	"context"
-	"os"
 
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,86 +13,53 @@ import (
 const incidentCollection = "incidents"
 
 // CreateIncident creates a new Incident.
-func CreateIncident(ctx context.Context, incident *models.Incident) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.InsertOne(ctx, incidentCollection, incident)
+func (s *Store) CreateIncident(ctx context.Context, incident *models.Incident) error {
+	err := s.Service.InsertOne(ctx, incidentCollection, incident)
 	return err
 }
 
-// CreateIncidents creates many new Incidents.
-func CreateIncidents(ctx context.Context, incidents *[]models.Incident) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
+// CreateIncidents creates many new Incidents, or replaces the Incidents of a deployment starting at the same time.
+// They are written in ordered batches, and each of the Incidents written gets its ID set.
+func (s *Store) CreateIncidents(ctx context.Context, incidents *[]models.Incident) error {
+	filters := make([]bson.M, len(*incidents))
+	documents := make([]any, len(*incidents))
+	for index := range *incidents {
+		incident := &(*incidents)[index]
+
+		filters[index] = bson.M{"deployment_id": incident.DeploymentID, "start_date": incident.StartDate}
+		documents[index] = incident
 	}
-	defer service.Disconnect(ctx)
 
-	for index, incident := range *incidents {
-		err = service.InsertOne(ctx, incidentCollection, &incident)
-		if err != nil {
-			return err
-		}
-		(*incidents)[index] = incident
+	ids, err := s.Service.UpsertMany(ctx, incidentCollection, filters, documents)
+	for index, id := range ids {
+		(*incidents)[index].ID = id
 	}
-	return nil
+	return err
 }
 
 // GetIncident retrieves an Incident.
-func GetIncident(ctx context.Context, incidentID primitive.ObjectID, incident *models.Incident) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.FindOneByID(ctx, incidentCollection, incidentID, incident)
+func (s *Store) GetIncident(ctx context.Context, incidentID primitive.ObjectID, incident *models.Incident) error {
+	err := s.Service.FindOneByID(ctx, incidentCollection, incidentID, incident)
 	return err
 }
 
 // ListIncidents retrieves many Incidents.
-func ListIncidents(ctx context.Context, deploymentID primitive.ObjectID, incidents *[]models.Incident) error {
+func (s *Store) ListIncidents(ctx context.Context, deploymentID primitive.ObjectID, incidents *[]models.Incident) error {
 	filter := bson.M{"deployment_id": deploymentID}
-	err := ListIncidentsByFilter(ctx, filter, incidents)
+	err := s.ListIncidentsByFilter(ctx, filter, incidents)
 	return err
 }
 
 // ListIncidentsByFilter retrieves many Incidents conforming to a filter.
-func ListIncidentsByFilter(ctx context.Context, filter bson.M, incidents *[]models.Incident) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) ListIncidentsByFilter(ctx context.Context, filter bson.M, incidents *[]models.Incident) error {
 	ops := options.Find().SetSort(bson.M{"start_date": 1})
-	err = service.Find(ctx, incidentCollection, filter, incidents, ops)
+	err := s.Service.Find(ctx, incidentCollection, filter, incidents, ops)
 	return err
 }
 
 // UpdateIncident updates an Incident.
-func UpdateIncident(ctx context.Context, incidentID primitive.ObjectID, incident *models.Incident) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.UpdateOne(ctx, incidentCollection, incidentID, &incident)
+func (s *Store) UpdateIncident(ctx context.Context, incidentID primitive.ObjectID, incident *models.Incident) error {
+	err := s.Service.UpdateOne(ctx, incidentCollection, incidentID, &incident)
 	if err != nil {
 		return err
 	}
@@ -104,15 +69,13 @@ func UpdateIncident(ctx context.Context, incidentID primitive.ObjectID, incident
 }
 
 // DeleteIncident deletes an Incident.
-func DeleteIncident(ctx context.Context, incidentID primitive.ObjectID) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
+func (s *Store) DeleteIncident(ctx context.Context, incidentID primitive.ObjectID) error {
+	err := s.Service.DeleteOne(ctx, incidentCollection, incidentID)
+	return err
+}
 
-	err = service.DeleteOne(ctx, incidentCollection, incidentID)
+// DeleteIncidentsByFilter deletes many Incidents conforming to a filter.
+func (s *Store) DeleteIncidentsByFilter(ctx context.Context, filter bson.M) error {
+	err := s.Service.DeleteMany(ctx, incidentCollection, filter)
 	return err
 }
@@ -6,7 +6,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -22,7 +21,7 @@ var _ = Describe("daos.Incident", func() {
 				StartDate:    time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				EndDate:      time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
 			}
-			err := daos.CreateIncident(ctx, &incident)
+			err := store.CreateIncident(ctx, &incident)
 			Expect(err).To(BeNil())
 			Expect(incident.ID).To(Not(BeEmpty()))
 		})
@@ -43,13 +42,50 @@ var _ = Describe("daos.Incident", func() {
 				EndDate:      time.Date(2022, 12, 27, 14, 21, 42, 0, time.UTC),
 			}
 			incidents := []models.Incident{incident1, incident2}
-			err := daos.CreateIncidents(ctx, &incidents)
+			err := store.CreateIncidents(ctx, &incidents)
 			Expect(err).To(BeNil())
 			Expect(incidents[0].ID).To(Not(BeEmpty()))
 			Expect(incidents[1].ID).To(Not(BeEmpty()))
 		})
 	})
 
+	var _ = When("CreateIncidents of a deployment twice", func() {
+		It("replaces the Incidents starting at the same time.", func() {
+			deploymentID := primitive.NewObjectID()
+			incidents := []models.Incident{
+				{
+					DeploymentID: deploymentID,
+					StartDate:    time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+					EndDate:      time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
+				},
+			}
+			err := store.CreateIncidents(ctx, &incidents)
+			Expect(err).To(BeNil())
+			Expect(incidents[0].ID).To(Not(BeEmpty()))
+
+			incidents = []models.Incident{
+				{
+					DeploymentID: deploymentID,
+					StartDate:    time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+					EndDate:      time.Date(2022, 12, 27, 13, 51, 42, 0, time.UTC),
+				},
+				{
+					DeploymentID: deploymentID,
+					StartDate:    time.Date(2022, 12, 27, 14, 16, 42, 0, time.UTC),
+					EndDate:      time.Date(2022, 12, 27, 14, 21, 42, 0, time.UTC),
+				},
+			}
+			err = store.CreateIncidents(ctx, &incidents)
+			Expect(err).To(BeNil())
+
+			var findIncidents []models.Incident
+			err = store.ListIncidents(ctx, deploymentID, &findIncidents)
+			Expect(err).To(BeNil())
+			Expect(len(findIncidents)).To(Equal(2))
+			Expect(findIncidents[0].EndDate).To(Equal(time.Date(2022, 12, 27, 13, 51, 42, 0, time.UTC)))
+		})
+	})
+
 	var _ = When("GetIncident", func() {
 		It("retrieves an Incident.", func() {
 			incident := models.Incident{
@@ -57,12 +93,12 @@ var _ = Describe("daos.Incident", func() {
 				StartDate:    time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				EndDate:      time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
 			}
-			err := daos.CreateIncident(ctx, &incident)
+			err := store.CreateIncident(ctx, &incident)
 			Expect(err).To(BeNil())
 			Expect(incident.ID).To(Not(BeEmpty()))
 
 			var findIncident models.Incident
-			err = daos.GetIncident(ctx, incident.ID, &findIncident)
+			err = store.GetIncident(ctx, incident.ID, &findIncident)
 			Expect(err).To(BeNil())
 			Expect(findIncident.ID).To(Equal(incident.ID))
 		})
@@ -86,15 +122,15 @@ var _ = Describe("daos.Incident", func() {
 				StartDate:    time.Date(2022, 12, 28, 21, 27, 40, 0, time.UTC),
 				EndDate:      time.Date(2022, 12, 28, 21, 45, 46, 0, time.UTC),
 			}
-			_ = daos.CreateIncident(ctx, &incident1)
-			_ = daos.CreateIncident(ctx, &incident2)
-			_ = daos.CreateIncident(ctx, &incident3)
+			_ = store.CreateIncident(ctx, &incident1)
+			_ = store.CreateIncident(ctx, &incident2)
+			_ = store.CreateIncident(ctx, &incident3)
 			Expect(incident1.ID).To(Not(BeNil()))
 			Expect(incident2.ID).To(Not(BeNil()))
 			Expect(incident3.ID).To(Not(BeNil()))
 
 			var findIncidents []models.Incident
-			err := daos.ListIncidents(ctx, deploymentID, &findIncidents)
+			err := store.ListIncidents(ctx, deploymentID, &findIncidents)
 			Expect(err).To(BeNil())
 			Expect(findIncidents).To(HaveLen(3))
 		})
@@ -118,16 +154,16 @@ var _ = Describe("daos.Incident", func() {
 				StartDate:    time.Date(2022, 12, 28, 21, 27, 40, 0, time.UTC),
 				EndDate:      time.Date(2022, 12, 28, 21, 45, 46, 0, time.UTC),
 			}
-			_ = daos.CreateIncident(ctx, &incident1)
-			_ = daos.CreateIncident(ctx, &incident2)
-			_ = daos.CreateIncident(ctx, &incident3)
+			_ = store.CreateIncident(ctx, &incident1)
+			_ = store.CreateIncident(ctx, &incident2)
+			_ = store.CreateIncident(ctx, &incident3)
 			Expect(incident1.ID).To(Not(BeNil()))
 			Expect(incident2.ID).To(Not(BeNil()))
 			Expect(incident3.ID).To(Not(BeNil()))
 
 			var findIncidents []models.Incident
 			filter := bson.M{"deployment_id": deploymentID}
-			err := daos.ListIncidentsByFilter(ctx, filter, &findIncidents)
+			err := store.ListIncidentsByFilter(ctx, filter, &findIncidents)
 			Expect(err).To(BeNil())
 			Expect(findIncidents).To(HaveLen(3))
 		})
@@ -140,7 +176,7 @@ var _ = Describe("daos.Incident", func() {
 				StartDate:    time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				EndDate:      time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
 			}
-			err := daos.CreateIncident(ctx, &incident)
+			err := store.CreateIncident(ctx, &incident)
 			Expect(err).To(BeNil())
 			Expect(incident.ID).To(Not(BeEmpty()))
 
@@ -149,7 +185,7 @@ var _ = Describe("daos.Incident", func() {
 				StartDate:    time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				EndDate:      time.Date(2022, 12, 27, 13, 26, 42, 0, time.UTC),
 			}
-			err = daos.UpdateIncident(ctx, incident.ID, &updateIncident)
+			err = store.UpdateIncident(ctx, incident.ID, &updateIncident)
 			Expect(err).To(BeNil())
 			Expect(updateIncident.EndDate).To(Equal(time.Date(2022, 12, 27, 13, 26, 42, 0, time.UTC)))
 		})
@@ -162,15 +198,15 @@ var _ = Describe("daos.Incident", func() {
 				StartDate:    time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
 				EndDate:      time.Date(2022, 12, 27, 13, 21, 42, 0, time.UTC),
 			}
-			err := daos.CreateIncident(ctx, &incident)
+			err := store.CreateIncident(ctx, &incident)
 			Expect(err).To(BeNil())
 			Expect(incident.ID).To(Not(BeEmpty()))
 
-			err = daos.DeleteIncident(ctx, incident.ID)
+			err = store.DeleteIncident(ctx, incident.ID)
 			Expect(err).To(BeNil())
 
 			var findIncident models.Incident
-			err = daos.GetIncident(ctx, incident.ID, &findIncident)
+			err = store.GetIncident(ctx, incident.ID, &findIncident)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -2,9 +2,7 @@ package daos
 
 import (
 	"context"
-	"os"
 
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,91 +12,58 @@ import (
 // default incidentsPerDayCollection
 const incidentsPerDayCollection = "incidents_per_days"
 
-// CreateIncidentsPerDay creates a new IncidentsPerDay.
-func CreateIncidentsPerDay(ctx context.Context, deploymentID primitive.ObjectID, incidentsPerDay *models.IncidentsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+// CreateIncidentsPerDay creates a new IncidentsPerDay, or replaces the IncidentsPerDay of the same date.
+func (s *Store) CreateIncidentsPerDay(ctx context.Context, deploymentID primitive.ObjectID, incidentsPerDay *models.IncidentsPerDay) error {
 	incidentsPerDay.DeploymentID = deploymentID
 
-	err = service.InsertOne(ctx, incidentsPerDayCollection, incidentsPerDay)
+	filter := bson.M{"deployment_id": deploymentID, "date": incidentsPerDay.Date}
+	err := s.Service.UpsertOne(ctx, incidentsPerDayCollection, filter, incidentsPerDay)
 	return err
 }
 
-// CreateIncidentsPerDays creates many new IncidentsPerDay.
-func CreateIncidentsPerDays(ctx context.Context, deploymentID primitive.ObjectID, incidentsPerDays *[]models.IncidentsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	for index, incidentsPerDay := range *incidentsPerDays {
+// CreateIncidentsPerDays creates many new IncidentsPerDay, or replaces the IncidentsPerDay of the same dates.
+// They are written in ordered batches, and each of the IncidentsPerDay written gets its ID set.
+func (s *Store) CreateIncidentsPerDays(ctx context.Context, deploymentID primitive.ObjectID, incidentsPerDays *[]models.IncidentsPerDay) error {
+	filters := make([]bson.M, len(*incidentsPerDays))
+	documents := make([]any, len(*incidentsPerDays))
+	for index := range *incidentsPerDays {
+		incidentsPerDay := &(*incidentsPerDays)[index]
 		incidentsPerDay.DeploymentID = deploymentID
 
-		err = service.InsertOne(ctx, incidentsPerDayCollection, &incidentsPerDay)
-		if err != nil {
-			return err
-		}
-		(*incidentsPerDays)[index] = incidentsPerDay
+		filters[index] = bson.M{"deployment_id": deploymentID, "date": incidentsPerDay.Date}
+		documents[index] = incidentsPerDay
 	}
-	return nil
-}
 
-// GetIncidentsPerDay retrieves a IncidentsPerDay.
-func GetIncidentsPerDay(ctx context.Context, incidentsPerDayID primitive.ObjectID, incidentsPerDay *models.IncidentsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
+	ids, err := s.Service.UpsertMany(ctx, incidentsPerDayCollection, filters, documents)
+	for index, id := range ids {
+		(*incidentsPerDays)[index].ID = id
 	}
-	defer service.Disconnect(ctx)
+	return err
+}
 
-	err = service.FindOneByID(ctx, incidentsPerDayCollection, incidentsPerDayID, incidentsPerDay)
+// GetIncidentsPerDay retrieves a IncidentsPerDay.
+func (s *Store) GetIncidentsPerDay(ctx context.Context, incidentsPerDayID primitive.ObjectID, incidentsPerDay *models.IncidentsPerDay) error {
+	err := s.Service.FindOneByID(ctx, incidentsPerDayCollection, incidentsPerDayID, incidentsPerDay)
 	return err
 }
 
 // ListIncidentsPerDays retrieves many IncidentsPerDay.
-func ListIncidentsPerDays(ctx context.Context, deploymentID primitive.ObjectID, incidentsPerDay *[]models.IncidentsPerDay) error {
+func (s *Store) ListIncidentsPerDays(ctx context.Context, deploymentID primitive.ObjectID, incidentsPerDay *[]models.IncidentsPerDay) error {
 	filter := bson.M{"deployment_id": deploymentID}
-	err := ListIncidentsPerDaysByFilter(ctx, filter, incidentsPerDay)
+	err := s.ListIncidentsPerDaysByFilter(ctx, filter, incidentsPerDay)
 	return err
 }
 
 // ListIncidentsPerDaysByFilter retrieves many IncidentsPerDay conforming to a filter.
-func ListIncidentsPerDaysByFilter(ctx context.Context, filter bson.M, incidentsPerDay *[]models.IncidentsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) ListIncidentsPerDaysByFilter(ctx context.Context, filter bson.M, incidentsPerDay *[]models.IncidentsPerDay) error {
 	ops := options.Find().SetSort(bson.M{"date": 1})
-	err = service.Find(ctx, incidentsPerDayCollection, filter, incidentsPerDay, ops)
+	err := s.Service.Find(ctx, incidentsPerDayCollection, filter, incidentsPerDay, ops)
 	return err
 }
 
 // UpdateIncidentsPerDay updates a IncidentsPerDay.
-func UpdateIncidentsPerDay(ctx context.Context, incidentsPerDayID primitive.ObjectID, incidentsPerDay *models.IncidentsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.UpdateOne(ctx, incidentsPerDayCollection, incidentsPerDayID, &incidentsPerDay)
+func (s *Store) UpdateIncidentsPerDay(ctx context.Context, incidentsPerDayID primitive.ObjectID, incidentsPerDay *models.IncidentsPerDay) error {
+	err := s.Service.UpdateOne(ctx, incidentsPerDayCollection, incidentsPerDayID, &incidentsPerDay)
 	if err != nil {
 		return err
 	}
@@ -108,15 +73,13 @@ func UpdateIncidentsPerDay(ctx context.Context, incidentsPerDayID primitive.Obje
 }
 
 // DeleteIncidentsPerDay deletes a IncidentsPerDay.
-func DeleteIncidentsPerDay(ctx context.Context, incidentsPerDayID primitive.ObjectID) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
+func (s *Store) DeleteIncidentsPerDay(ctx context.Context, incidentsPerDayID primitive.ObjectID) error {
+	err := s.Service.DeleteOne(ctx, incidentsPerDayCollection, incidentsPerDayID)
+	return err
+}
 
-	err = service.DeleteOne(ctx, incidentsPerDayCollection, incidentsPerDayID)
+// DeleteIncidentsPerDaysByFilter deletes many IncidentsPerDay conforming to a filter.
+func (s *Store) DeleteIncidentsPerDaysByFilter(ctx context.Context, filter bson.M) error {
+	err := s.Service.DeleteMany(ctx, incidentsPerDayCollection, filter)
 	return err
 }
@@ -6,7 +6,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -25,7 +24,7 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalIncidents: 1,
 				TotalDuration:  60,
 			}
-			err := daos.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
+			err := store.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
 			Expect(err).To(BeNil())
 			Expect(incidentsPerDay.ID).To(Not(BeEmpty()))
 		})
@@ -50,7 +49,7 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalDuration:  180,
 			}
 			incidentsPerDays := []models.IncidentsPerDay{incidentsPerDay1, incidentsPerDay2}
-			err := daos.CreateIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
+			err := store.CreateIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
 			Expect(err).To(BeNil())
 			Expect(incidentsPerDays[0].ID).To(Not(BeEmpty()))
 			Expect(incidentsPerDays[1].ID).To(Not(BeEmpty()))
@@ -67,12 +66,12 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalIncidents: 1,
 				TotalDuration:  60,
 			}
-			err := daos.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
+			err := store.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
 			Expect(err).To(BeNil())
 			Expect(incidentsPerDay.ID).To(Not(BeEmpty()))
 
 			var findIncidentsPerDay models.IncidentsPerDay
-			err = daos.GetIncidentsPerDay(ctx, incidentsPerDay.ID, &findIncidentsPerDay)
+			err = store.GetIncidentsPerDay(ctx, incidentsPerDay.ID, &findIncidentsPerDay)
 			Expect(err).To(BeNil())
 			Expect(findIncidentsPerDay.ID).To(Equal(incidentsPerDay.ID))
 		})
@@ -97,11 +96,11 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalDuration:  180,
 			}
 			incidentsPerDays := []models.IncidentsPerDay{incidentsPerDay1, incidentsPerDay2}
-			err := daos.CreateIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
+			err := store.CreateIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
 			Expect(err).To(BeNil())
 
 			var findIncidentsPerDays []models.IncidentsPerDay
-			err = daos.ListIncidentsPerDays(ctx, deploymentID, &findIncidentsPerDays)
+			err = store.ListIncidentsPerDays(ctx, deploymentID, &findIncidentsPerDays)
 			Expect(err).To(BeNil())
 			Expect(len(findIncidentsPerDays)).To(Equal(2))
 		})
@@ -126,12 +125,12 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalDuration:  180,
 			}
 			incidentsPerDays := []models.IncidentsPerDay{incidentsPerDay1, incidentsPerDay2}
-			err := daos.CreateIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
+			err := store.CreateIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
 			Expect(err).To(BeNil())
 
 			var findIncidentsPerDays []models.IncidentsPerDay
 			filter := bson.M{"date": bson.M{"$gte": date2}}
-			err = daos.ListIncidentsPerDaysByFilter(ctx, filter, &findIncidentsPerDays)
+			err = store.ListIncidentsPerDaysByFilter(ctx, filter, &findIncidentsPerDays)
 			Expect(err).To(BeNil())
 			Expect(findIncidentsPerDays).To(HaveLen(1))
 		})
@@ -147,7 +146,7 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalIncidents: 1,
 				TotalDuration:  60,
 			}
-			err := daos.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
+			err := store.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
 			Expect(err).To(BeNil())
 			Expect(incidentsPerDay.ID).To(Not(BeEmpty()))
 
@@ -157,7 +156,7 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalIncidents: 2,
 				TotalDuration:  180,
 			}
-			err = daos.UpdateIncidentsPerDay(ctx, incidentsPerDay.ID, &updateIncidentsPerDay)
+			err = store.UpdateIncidentsPerDay(ctx, incidentsPerDay.ID, &updateIncidentsPerDay)
 			Expect(err).To(BeNil())
 			Expect(updateIncidentsPerDay.TotalIncidents).To(Equal(2))
 		})
@@ -173,15 +172,15 @@ var _ = Describe("daos.IncidentsPerDays", func() {
 				TotalIncidents: 1,
 				TotalDuration:  60,
 			}
-			err := daos.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
+			err := store.CreateIncidentsPerDay(ctx, deploymentID, &incidentsPerDay)
 			Expect(err).To(BeNil())
 			Expect(incidentsPerDay.ID).To(Not(BeEmpty()))
 
-			err = daos.DeleteIncidentsPerDay(ctx, incidentsPerDay.ID)
+			err = store.DeleteIncidentsPerDay(ctx, incidentsPerDay.ID)
 			Expect(err).To(BeNil())
 
 			var findIncidentsPerDay models.IncidentsPerDay
-			err = daos.GetIncidentsPerDay(ctx, incidentsPerDay.ID, &findIncidentsPerDay)
+			err = store.GetIncidentsPerDay(ctx, incidentsPerDay.ID, &findIncidentsPerDay)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -5,7 +5,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -21,7 +20,7 @@ var _ = Describe("daos.Integration", func() {
 				BearerToken: "bearertoken",
 				URI:         "https://gitlab.com",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 			Expect(integration.ID).To(Not(BeEmpty()))
 		})
@@ -35,12 +34,12 @@ var _ = Describe("daos.Integration", func() {
 				BearerToken: "bearertoken",
 				URI:         "https://gitlab.com",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 			Expect(integration.ID).To(Not(BeEmpty()))
 
 			var findIntegration models.Integration
-			err = daos.GetIntegration(ctx, integration.ID, &findIntegration)
+			err = store.GetIntegration(ctx, integration.ID, &findIntegration)
 			Expect(err).To(BeNil())
 			Expect(findIntegration.ID).To(Equal(integration.ID))
 		})
@@ -66,15 +65,15 @@ var _ = Describe("daos.Integration", func() {
 				BearerToken: "bearertoken",
 				URI:         "https://github.com",
 			}
-			_ = daos.CreateIntegration(ctx, &integration1)
-			_ = daos.CreateIntegration(ctx, &integration2)
-			_ = daos.CreateIntegration(ctx, &integration3)
+			_ = store.CreateIntegration(ctx, &integration1)
+			_ = store.CreateIntegration(ctx, &integration2)
+			_ = store.CreateIntegration(ctx, &integration3)
 			Expect(integration1.ID).To(Not(BeNil()))
 			Expect(integration2.ID).To(Not(BeNil()))
 			Expect(integration3.ID).To(Not(BeNil()))
 
 			var findIntegrations []models.Integration
-			err := daos.ListIntegrations(ctx, &findIntegrations)
+			err := store.ListIntegrations(ctx, &findIntegrations)
 			Expect(err).To(BeNil())
 			Expect(findIntegrations).To(HaveLen(3))
 		})
@@ -100,16 +99,16 @@ var _ = Describe("daos.Integration", func() {
 				BearerToken: "bearertoken",
 				URI:         "https://github.com",
 			}
-			_ = daos.CreateIntegration(ctx, &integration1)
-			_ = daos.CreateIntegration(ctx, &integration2)
-			_ = daos.CreateIntegration(ctx, &integration3)
+			_ = store.CreateIntegration(ctx, &integration1)
+			_ = store.CreateIntegration(ctx, &integration2)
+			_ = store.CreateIntegration(ctx, &integration3)
 			Expect(integration1.ID).To(Not(BeNil()))
 			Expect(integration2.ID).To(Not(BeNil()))
 			Expect(integration3.ID).To(Not(BeNil()))
 
 			var findIntegrations []models.Integration
 			filter := bson.M{"type": "sc"}
-			err := daos.ListIntegrationsByFilter(ctx, filter, &findIntegrations)
+			err := store.ListIntegrationsByFilter(ctx, filter, &findIntegrations)
 			Expect(err).To(BeNil())
 			Expect(findIntegrations).To(HaveLen(2))
 		})
@@ -123,7 +122,7 @@ var _ = Describe("daos.Integration", func() {
 				BearerToken: "bearertoken",
 				URI:         "https://gitlab.com",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 			Expect(integration.ID).To(Not(BeEmpty()))
 
@@ -133,7 +132,7 @@ var _ = Describe("daos.Integration", func() {
 				BearerToken: "newbearertoken",
 				URI:         "https://gitlab.com",
 			}
-			err = daos.UpdateIntegration(ctx, integration.ID, &updateIntegration)
+			err = store.UpdateIntegration(ctx, integration.ID, &updateIntegration)
 			Expect(err).To(BeNil())
 			Expect(updateIntegration.BearerToken).To(Equal("newbearertoken"))
 		})
@@ -147,15 +146,15 @@ var _ = Describe("daos.Integration", func() {
 				BearerToken: "bearertoken",
 				URI:         "https://gitlab.com",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 			Expect(integration.ID).To(Not(BeEmpty()))
 
-			err = daos.DeleteIntegration(ctx, integration.ID)
+			err = store.DeleteIntegration(ctx, integration.ID)
 			Expect(err).To(BeNil())
 
 			var findIntegration models.Integration
-			err = daos.GetIntegration(ctx, integration.ID, &findIntegration)
+			err = store.GetIntegration(ctx, integration.ID, &findIntegration)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -0,0 +1,43 @@
+package daos
+
+import (
+	"context"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// default jobCollection
+const jobCollection = "jobs"
+
+// CreateJob creates a new Job.
+func (s *Store) CreateJob(ctx context.Context, job *models.Job) error {
+	err := s.Service.InsertOne(ctx, jobCollection, job)
+	return err
+}
+
+// GetJob retrieves a Job.
+func (s *Store) GetJob(ctx context.Context, jobID primitive.ObjectID, job *models.Job) error {
+	err := s.Service.FindOneByID(ctx, jobCollection, jobID, job)
+	return err
+}
+
+// ListJobsByFilter retrieves many Jobs conforming to a filter, ordered by their creation.
+func (s *Store) ListJobsByFilter(ctx context.Context, filter bson.M, jobs *[]models.Job) error {
+	ops := options.Find().SetSort(bson.M{"created_at": 1})
+	err := s.Service.Find(ctx, jobCollection, filter, jobs, ops)
+	return err
+}
+
+// UpdateJob updates a Job.
+func (s *Store) UpdateJob(ctx context.Context, jobID primitive.ObjectID, job *models.Job) error {
+	err := s.Service.UpdateOne(ctx, jobCollection, jobID, &job)
+	if err != nil {
+		return err
+	}
+
+	job.ID = jobID
+	return nil
+}
@@ -0,0 +1,87 @@
+package daos_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("daos.Job", func() {
+	ctx := context.Background()
+
+	var _ = When("CreateJob", func() {
+		It("creates a new Job.", func() {
+			job := models.Job{
+				DataflowID: primitive.NewObjectID(),
+				Status:     models.JobPending,
+				CreatedAt:  time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			}
+			err := store.CreateJob(ctx, &job)
+			Expect(err).To(BeNil())
+			Expect(job.ID).To(Not(BeEmpty()))
+		})
+	})
+
+	var _ = When("GetJob", func() {
+		It("retrieves a Job.", func() {
+			job := models.Job{
+				DataflowID: primitive.NewObjectID(),
+				Status:     models.JobPending,
+			}
+			err := store.CreateJob(ctx, &job)
+			Expect(err).To(BeNil())
+
+			var findJob models.Job
+			err = store.GetJob(ctx, job.ID, &findJob)
+			Expect(err).To(BeNil())
+			Expect(findJob.DataflowID).To(Equal(job.DataflowID))
+		})
+	})
+
+	var _ = When("ListJobsByFilter", func() {
+		It("retrieves the Jobs conforming to a filter.", func() {
+			for _, status := range []string{models.JobPending, models.JobRunning, models.JobSucceeded} {
+				job := models.Job{
+					DataflowID: primitive.NewObjectID(),
+					Status:     status,
+				}
+				err := store.CreateJob(ctx, &job)
+				Expect(err).To(BeNil())
+			}
+
+			var jobs []models.Job
+			err := store.ListJobsByFilter(ctx, bson.M{"status": models.JobRunning}, &jobs)
+			Expect(err).To(BeNil())
+			Expect(jobs).To(HaveLen(1))
+			Expect(jobs[0].Status).To(Equal(models.JobRunning))
+		})
+	})
+
+	var _ = When("UpdateJob", func() {
+		It("updates a Job.", func() {
+			job := models.Job{
+				DataflowID: primitive.NewObjectID(),
+				Status:     models.JobPending,
+			}
+			err := store.CreateJob(ctx, &job)
+			Expect(err).To(BeNil())
+
+			job.Status = models.JobRunning
+			job.Phase = models.JobPhaseRaw
+			job.Progress.Commits = 10
+			err = store.UpdateJob(ctx, job.ID, &job)
+			Expect(err).To(BeNil())
+
+			var findJob models.Job
+			err = store.GetJob(ctx, job.ID, &findJob)
+			Expect(err).To(BeNil())
+			Expect(findJob.Phase).To(Equal(models.JobPhaseRaw))
+			Expect(findJob.Progress.Commits).To(Equal(10))
+		})
+	})
+})
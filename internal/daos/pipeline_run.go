@@ -2,9 +2,7 @@ package daos
 
 import (
 	"context"
-	"os"
 
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,91 +12,58 @@ import (
 // default pipelineRunCollection
 const pipelineRunCollection = "pipeline_runs"
 
-// CreatePipelineRun creates a new PipelineRun.
-func CreatePipelineRun(ctx context.Context, pipelineID primitive.ObjectID, pipelineRun *models.PipelineRun) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+// CreatePipelineRun creates a new PipelineRun, or replaces the PipelineRun with the same external ID in the pipeline.
+func (s *Store) CreatePipelineRun(ctx context.Context, pipelineID primitive.ObjectID, pipelineRun *models.PipelineRun) error {
 	pipelineRun.PipelineID = pipelineID
 
-	err = service.InsertOne(ctx, pipelineRunCollection, pipelineRun)
+	filter := bson.M{"pipeline_id": pipelineID, "external_id": pipelineRun.ExternalID}
+	err := s.Service.UpsertOne(ctx, pipelineRunCollection, filter, pipelineRun)
 	return err
 }
 
-// CreatePipelineRuns creates many new PipelineRuns.
-func CreatePipelineRuns(ctx context.Context, pipelineID primitive.ObjectID, pipelineRuns *[]models.PipelineRun) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	for index, pipelineRun := range *pipelineRuns {
+// CreatePipelineRuns creates many new PipelineRuns, or replaces the PipelineRuns with the same external ID in the pipeline.
+// They are written in ordered batches, and each of the PipelineRuns written gets its ID set.
+func (s *Store) CreatePipelineRuns(ctx context.Context, pipelineID primitive.ObjectID, pipelineRuns *[]models.PipelineRun) error {
+	filters := make([]bson.M, len(*pipelineRuns))
+	documents := make([]any, len(*pipelineRuns))
+	for index := range *pipelineRuns {
+		pipelineRun := &(*pipelineRuns)[index]
 		pipelineRun.PipelineID = pipelineID
 
-		err = service.InsertOne(ctx, pipelineRunCollection, &pipelineRun)
-		if err != nil {
-			return err
-		}
-		(*pipelineRuns)[index] = pipelineRun
+		filters[index] = bson.M{"pipeline_id": pipelineID, "external_id": pipelineRun.ExternalID}
+		documents[index] = pipelineRun
 	}
-	return nil
-}
 
-// GetPipelineRun retrieves an PipelineRun.
-func GetPipelineRun(ctx context.Context, pipelineRunID primitive.ObjectID, pipelineRun *models.PipelineRun) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
+	ids, err := s.Service.UpsertMany(ctx, pipelineRunCollection, filters, documents)
+	for index, id := range ids {
+		(*pipelineRuns)[index].ID = id
 	}
-	defer service.Disconnect(ctx)
+	return err
+}
 
-	err = service.FindOneByID(ctx, pipelineRunCollection, pipelineRunID, pipelineRun)
+// GetPipelineRun retrieves an PipelineRun.
+func (s *Store) GetPipelineRun(ctx context.Context, pipelineRunID primitive.ObjectID, pipelineRun *models.PipelineRun) error {
+	err := s.Service.FindOneByID(ctx, pipelineRunCollection, pipelineRunID, pipelineRun)
 	return err
 }
 
 // ListPipelineRuns retrieves many PipelineRuns.
-func ListPipelineRuns(ctx context.Context, pipelineID primitive.ObjectID, pipelineRuns *[]models.PipelineRun) error {
+func (s *Store) ListPipelineRuns(ctx context.Context, pipelineID primitive.ObjectID, pipelineRuns *[]models.PipelineRun) error {
 	filter := bson.M{"pipeline_id": pipelineID}
-	err := ListPipelineRunsByFilter(ctx, filter, pipelineRuns)
+	err := s.ListPipelineRunsByFilter(ctx, filter, pipelineRuns)
 	return err
 }
 
 // ListPipelineRunsByFilter retrieves many PipelineRuns conforming to a filter.
-func ListPipelineRunsByFilter(ctx context.Context, filter bson.M, pipelineRuns *[]models.PipelineRun) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) ListPipelineRunsByFilter(ctx context.Context, filter bson.M, pipelineRuns *[]models.PipelineRun) error {
 	ops := options.Find().SetSort(bson.M{"created_at": 1})
-	err = service.Find(ctx, pipelineRunCollection, filter, pipelineRuns, ops)
+	err := s.Service.Find(ctx, pipelineRunCollection, filter, pipelineRuns, ops)
 	return err
 }
 
 // UpdatePipelineRun updates an PipelineRun.
-func UpdatePipelineRun(ctx context.Context, pipelineRunID primitive.ObjectID, pipelineRun *models.PipelineRun) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.UpdateOne(ctx, pipelineRunCollection, pipelineRunID, &pipelineRun)
+func (s *Store) UpdatePipelineRun(ctx context.Context, pipelineRunID primitive.ObjectID, pipelineRun *models.PipelineRun) error {
+	err := s.Service.UpdateOne(ctx, pipelineRunCollection, pipelineRunID, &pipelineRun)
 	if err != nil {
 		return err
 	}
@@ -108,15 +73,7 @@ func UpdatePipelineRun(ctx context.Context, pipelineRunID primitive.ObjectID, pi
 }
 
 // DeletePipelineRun deletes an PipelineRun.
-func DeletePipelineRun(ctx context.Context, pipelineRunID primitive.ObjectID) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.DeleteOne(ctx, pipelineRunCollection, pipelineRunID)
+func (s *Store) DeletePipelineRun(ctx context.Context, pipelineRunID primitive.ObjectID) error {
+	err := s.Service.DeleteOne(ctx, pipelineRunCollection, pipelineRunID)
 	return err
 }
@@ -6,7 +6,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -32,7 +31,7 @@ var _ = Describe("daos.PipelineRun", func() {
 				UpdatedAt:   updatedAt,
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884002",
 			}
-			err := daos.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
+			err := store.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
 			Expect(err).To(BeNil())
 			Expect(pipelineRun.ID).To(Not(BeEmpty()))
 		})
@@ -59,7 +58,7 @@ var _ = Describe("daos.PipelineRun", func() {
 			updatedAt2, _ := time.Parse(time.RFC3339, "2020-02-04T14:45:51.459Z")
 			pipelineRun2 := models.PipelineRun{
 				PipelineID:  pipelineID,
-				ExternalID:  externalID,
+				ExternalID:  externalID + 1,
 				Sha:         "345207c839e94a939aebdc86835ae2e2a6c85acb",
 				Ref:         "main",
 				Status:      "success",
@@ -69,13 +68,36 @@ var _ = Describe("daos.PipelineRun", func() {
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884003",
 			}
 			pipelineRuns := []models.PipelineRun{pipelineRun1, pipelineRun2}
-			err := daos.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
 			Expect(err).To(BeNil())
 			Expect(pipelineRuns[0].ID).To(Not(BeEmpty()))
 			Expect(pipelineRuns[1].ID).To(Not(BeEmpty()))
 		})
 	})
 
+	var _ = When("CreatePipelineRuns", func() {
+		It("replaces PipelineRuns with the same external ID instead of creating them again.", func() {
+			pipelineID := primitive.NewObjectID()
+			pipelineRuns := []models.PipelineRun{
+				{ExternalID: externalID, Status: "running"},
+			}
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			Expect(err).To(BeNil())
+
+			pipelineRuns = []models.PipelineRun{
+				{ExternalID: externalID, Status: "success"},
+			}
+			err = store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			Expect(err).To(BeNil())
+
+			var findPipelineRuns []models.PipelineRun
+			err = store.ListPipelineRuns(ctx, pipelineID, &findPipelineRuns)
+			Expect(err).To(BeNil())
+			Expect(findPipelineRuns).To(HaveLen(1))
+			Expect(findPipelineRuns[0].Status).To(Equal("success"))
+		})
+	})
+
 	var _ = When("GetPipelineRun", func() {
 		It("retrieves an PipelineRun.", func() {
 			pipelineID := primitive.NewObjectID()
@@ -92,12 +114,12 @@ var _ = Describe("daos.PipelineRun", func() {
 				UpdatedAt:   updatedAt,
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884002",
 			}
-			err := daos.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
+			err := store.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
 			Expect(err).To(BeNil())
 			Expect(pipelineRun.ID).To(Not(BeEmpty()))
 
 			var findPipelineRun models.PipelineRun
-			err = daos.GetPipelineRun(ctx, pipelineRun.ID, &findPipelineRun)
+			err = store.GetPipelineRun(ctx, pipelineRun.ID, &findPipelineRun)
 			Expect(err).To(BeNil())
 			Expect(findPipelineRun.ID).To(Equal(pipelineRun.ID))
 		})
@@ -124,7 +146,7 @@ var _ = Describe("daos.PipelineRun", func() {
 			updatedAt2, _ := time.Parse(time.RFC3339, "2020-02-04T14:45:51.459Z")
 			pipelineRun2 := models.PipelineRun{
 				PipelineID:  pipelineID,
-				ExternalID:  externalID,
+				ExternalID:  externalID + 1,
 				Sha:         "345207c839e94a939aebdc86835ae2e2a6c85acb",
 				Ref:         "main",
 				Status:      "success",
@@ -134,11 +156,11 @@ var _ = Describe("daos.PipelineRun", func() {
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884003",
 			}
 			pipelineRuns := []models.PipelineRun{pipelineRun1, pipelineRun2}
-			err := daos.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
 			Expect(err).To(BeNil())
 
 			var findPipelineRuns []models.PipelineRun
-			err = daos.ListPipelineRuns(ctx, pipelineID, &findPipelineRuns)
+			err = store.ListPipelineRuns(ctx, pipelineID, &findPipelineRuns)
 			Expect(err).To(BeNil())
 			Expect(len(findPipelineRuns)).To(Equal(2))
 		})
@@ -165,7 +187,7 @@ var _ = Describe("daos.PipelineRun", func() {
 			updatedAt2, _ := time.Parse(time.RFC3339, "2020-02-04T14:45:51.459Z")
 			pipelineRun2 := models.PipelineRun{
 				PipelineID:  pipelineID,
-				ExternalID:  externalID,
+				ExternalID:  externalID + 1,
 				Sha:         "345207c839e94a939aebdc86835ae2e2a6c85acb",
 				Ref:         "develop",
 				Status:      "success",
@@ -175,12 +197,12 @@ var _ = Describe("daos.PipelineRun", func() {
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884003",
 			}
 			pipelineRuns := []models.PipelineRun{pipelineRun1, pipelineRun2}
-			err := daos.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
 			Expect(err).To(BeNil())
 
 			var findPipelineRuns []models.PipelineRun
 			filter := bson.M{"ref": "main"}
-			err = daos.ListPipelineRunsByFilter(ctx, filter, &findPipelineRuns)
+			err = store.ListPipelineRunsByFilter(ctx, filter, &findPipelineRuns)
 			Expect(err).To(BeNil())
 			Expect(findPipelineRuns).To(HaveLen(1))
 		})
@@ -202,7 +224,7 @@ var _ = Describe("daos.PipelineRun", func() {
 				UpdatedAt:   updatedAt,
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884002",
 			}
-			err := daos.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
+			err := store.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
 			Expect(err).To(BeNil())
 			Expect(pipelineRun.ID).To(Not(BeEmpty()))
 
@@ -218,7 +240,7 @@ var _ = Describe("daos.PipelineRun", func() {
 				UpdatedAt:   newUpdatedAt,
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884002",
 			}
-			err = daos.UpdatePipelineRun(ctx, pipelineRun.ID, &updatePipelineRun)
+			err = store.UpdatePipelineRun(ctx, pipelineRun.ID, &updatePipelineRun)
 			Expect(err).To(BeNil())
 			Expect(updatePipelineRun.UpdatedAt).To(Equal(newUpdatedAt))
 		})
@@ -240,15 +262,15 @@ var _ = Describe("daos.PipelineRun", func() {
 				UpdatedAt:   updatedAt,
 				URI:         "https://gitlab.com/foobar/foobar/-/pipelines/114884002",
 			}
-			err := daos.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
+			err := store.CreatePipelineRun(ctx, pipelineID, &pipelineRun)
 			Expect(err).To(BeNil())
 			Expect(pipelineRun.ID).To(Not(BeEmpty()))
 
-			err = daos.DeletePipelineRun(ctx, pipelineRun.ID)
+			err = store.DeletePipelineRun(ctx, pipelineRun.ID)
 			Expect(err).To(BeNil())
 
 			var findPipelineRun models.PipelineRun
-			err = daos.GetPipelineRun(ctx, pipelineRun.ID, &findPipelineRun)
+			err = store.GetPipelineRun(ctx, pipelineRun.ID, &findPipelineRun)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -2,9 +2,7 @@ package daos
 
 import (
 	"context"
-	"os"
 
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,91 +12,58 @@ import (
 // default pipelineRunsPerDayCollection
 const pipelineRunsPerDayCollection = "pipeline_runs_per_days"
 
-// CreatePipelineRunsPerDay creates a new PipelineRunsPerDay.
-func CreatePipelineRunsPerDay(ctx context.Context, pipelineID primitive.ObjectID, pipelineRunsPerDay *models.PipelineRunsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+// CreatePipelineRunsPerDay creates a new PipelineRunsPerDay, or replaces the PipelineRunsPerDay of the same date.
+func (s *Store) CreatePipelineRunsPerDay(ctx context.Context, pipelineID primitive.ObjectID, pipelineRunsPerDay *models.PipelineRunsPerDay) error {
 	pipelineRunsPerDay.PipelineID = pipelineID
 
-	err = service.InsertOne(ctx, pipelineRunsPerDayCollection, pipelineRunsPerDay)
+	filter := bson.M{"pipeline_id": pipelineID, "date": pipelineRunsPerDay.Date}
+	err := s.Service.UpsertOne(ctx, pipelineRunsPerDayCollection, filter, pipelineRunsPerDay)
 	return err
 }
 
-// CreatePipelineRunsPerDays creates many new PipelineRunsPerDay.
-func CreatePipelineRunsPerDays(ctx context.Context, pipelineID primitive.ObjectID, pipelineRunsPerDays *[]models.PipelineRunsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	for index, pipelineRunsPerDay := range *pipelineRunsPerDays {
+// CreatePipelineRunsPerDays creates many new PipelineRunsPerDay, or replaces the PipelineRunsPerDay of the same dates.
+// They are written in ordered batches, and each of the PipelineRunsPerDay written gets its ID set.
+func (s *Store) CreatePipelineRunsPerDays(ctx context.Context, pipelineID primitive.ObjectID, pipelineRunsPerDays *[]models.PipelineRunsPerDay) error {
+	filters := make([]bson.M, len(*pipelineRunsPerDays))
+	documents := make([]any, len(*pipelineRunsPerDays))
+	for index := range *pipelineRunsPerDays {
+		pipelineRunsPerDay := &(*pipelineRunsPerDays)[index]
 		pipelineRunsPerDay.PipelineID = pipelineID
 
-		err = service.InsertOne(ctx, pipelineRunsPerDayCollection, &pipelineRunsPerDay)
-		if err != nil {
-			return err
-		}
-		(*pipelineRunsPerDays)[index] = pipelineRunsPerDay
+		filters[index] = bson.M{"pipeline_id": pipelineID, "date": pipelineRunsPerDay.Date}
+		documents[index] = pipelineRunsPerDay
 	}
-	return nil
-}
 
-// GetPipelineRunsPerDay retrieves a PipelineRunsPerDay.
-func GetPipelineRunsPerDay(ctx context.Context, pipelineRunsPerDayID primitive.ObjectID, pipelineRunsPerDay *models.PipelineRunsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
+	ids, err := s.Service.UpsertMany(ctx, pipelineRunsPerDayCollection, filters, documents)
+	for index, id := range ids {
+		(*pipelineRunsPerDays)[index].ID = id
 	}
-	defer service.Disconnect(ctx)
+	return err
+}
 
-	err = service.FindOneByID(ctx, pipelineRunsPerDayCollection, pipelineRunsPerDayID, pipelineRunsPerDay)
+// GetPipelineRunsPerDay retrieves a PipelineRunsPerDay.
+func (s *Store) GetPipelineRunsPerDay(ctx context.Context, pipelineRunsPerDayID primitive.ObjectID, pipelineRunsPerDay *models.PipelineRunsPerDay) error {
+	err := s.Service.FindOneByID(ctx, pipelineRunsPerDayCollection, pipelineRunsPerDayID, pipelineRunsPerDay)
 	return err
 }
 
 // ListPipelineRunsPerDays retrieves many PipelineRunsPerDay.
-func ListPipelineRunsPerDays(ctx context.Context, pipelineID primitive.ObjectID, pipelineRunsPerDay *[]models.PipelineRunsPerDay) error {
+func (s *Store) ListPipelineRunsPerDays(ctx context.Context, pipelineID primitive.ObjectID, pipelineRunsPerDay *[]models.PipelineRunsPerDay) error {
 	filter := bson.M{"pipeline_id": pipelineID}
-	err := ListPipelineRunsPerDaysByFilter(ctx, filter, pipelineRunsPerDay)
+	err := s.ListPipelineRunsPerDaysByFilter(ctx, filter, pipelineRunsPerDay)
 	return err
 }
 
 // ListPipelineRunsPerDaysByFilter retrieves many PipelineRunsPerDay conforming to a filter.
-func ListPipelineRunsPerDaysByFilter(ctx context.Context, filter bson.M, pipelineRunsPerDay *[]models.PipelineRunsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
+func (s *Store) ListPipelineRunsPerDaysByFilter(ctx context.Context, filter bson.M, pipelineRunsPerDay *[]models.PipelineRunsPerDay) error {
 	ops := options.Find().SetSort(bson.M{"date": 1})
-	err = service.Find(ctx, pipelineRunsPerDayCollection, filter, pipelineRunsPerDay, ops)
+	err := s.Service.Find(ctx, pipelineRunsPerDayCollection, filter, pipelineRunsPerDay, ops)
 	return err
 }
 
 // UpdatePipelineRunsPerDay updates a PipelineRunsPerDay.
-func UpdatePipelineRunsPerDay(ctx context.Context, pipelineRunsPerDayID primitive.ObjectID, pipelineRunsPerDay *models.PipelineRunsPerDay) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
-
-	err = service.UpdateOne(ctx, pipelineRunsPerDayCollection, pipelineRunsPerDayID, &pipelineRunsPerDay)
+func (s *Store) UpdatePipelineRunsPerDay(ctx context.Context, pipelineRunsPerDayID primitive.ObjectID, pipelineRunsPerDay *models.PipelineRunsPerDay) error {
+	err := s.Service.UpdateOne(ctx, pipelineRunsPerDayCollection, pipelineRunsPerDayID, &pipelineRunsPerDay)
 	if err != nil {
 		return err
 	}
@@ -108,15 +73,13 @@ func UpdatePipelineRunsPerDay(ctx context.Context, pipelineRunsPerDayID primitiv
 }
 
 // DeletePipelineRunsPerDay deletes a PipelineRunsPerDay.
-func DeletePipelineRunsPerDay(ctx context.Context, pipelineRunsPerDayID primitive.ObjectID) error {
-	service := mongodb.NewService()
-	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
-	if err != nil {
-		return err
-	}
-	defer service.Disconnect(ctx)
+func (s *Store) DeletePipelineRunsPerDay(ctx context.Context, pipelineRunsPerDayID primitive.ObjectID) error {
+	err := s.Service.DeleteOne(ctx, pipelineRunsPerDayCollection, pipelineRunsPerDayID)
+	return err
+}
 
-	err = service.DeleteOne(ctx, pipelineRunsPerDayCollection, pipelineRunsPerDayID)
+// DeletePipelineRunsPerDaysByFilter deletes many PipelineRunsPerDay conforming to a filter.
+func (s *Store) DeletePipelineRunsPerDaysByFilter(ctx context.Context, filter bson.M) error {
+	err := s.Service.DeleteMany(ctx, pipelineRunsPerDayCollection, filter)
 	return err
 }
@@ -6,7 +6,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -24,7 +23,7 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				Date:              date,
 				TotalPipelineRuns: 1,
 			}
-			err := daos.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
+			err := store.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
 			Expect(err).To(BeNil())
 			Expect(pipelineRunsPerDay.ID).To(Not(BeEmpty()))
 		})
@@ -47,7 +46,7 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				TotalPipelineRuns: 2,
 			}
 			pipelineRunsPerDays := []models.PipelineRunsPerDay{pipelineRunsPerDay1, pipelineRunsPerDay2}
-			err := daos.CreatePipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
+			err := store.CreatePipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
 			Expect(err).To(BeNil())
 			Expect(pipelineRunsPerDays[0].ID).To(Not(BeEmpty()))
 			Expect(pipelineRunsPerDays[1].ID).To(Not(BeEmpty()))
@@ -63,12 +62,12 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				Date:              date,
 				TotalPipelineRuns: 1,
 			}
-			err := daos.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
+			err := store.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
 			Expect(err).To(BeNil())
 			Expect(pipelineRunsPerDay.ID).To(Not(BeEmpty()))
 
 			var findPipelineRunsPerDay models.PipelineRunsPerDay
-			err = daos.GetPipelineRunsPerDay(ctx, pipelineRunsPerDay.ID, &findPipelineRunsPerDay)
+			err = store.GetPipelineRunsPerDay(ctx, pipelineRunsPerDay.ID, &findPipelineRunsPerDay)
 			Expect(err).To(BeNil())
 			Expect(findPipelineRunsPerDay.ID).To(Equal(pipelineRunsPerDay.ID))
 		})
@@ -91,11 +90,11 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				TotalPipelineRuns: 2,
 			}
 			pipelineRunsPerDays := []models.PipelineRunsPerDay{pipelineRunsPerDay1, pipelineRunsPerDay2}
-			err := daos.CreatePipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
+			err := store.CreatePipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
 			Expect(err).To(BeNil())
 
 			var findPipelineRunsPerDays []models.PipelineRunsPerDay
-			err = daos.ListPipelineRunsPerDays(ctx, pipelineID, &findPipelineRunsPerDays)
+			err = store.ListPipelineRunsPerDays(ctx, pipelineID, &findPipelineRunsPerDays)
 			Expect(err).To(BeNil())
 			Expect(len(findPipelineRunsPerDays)).To(Equal(2))
 		})
@@ -118,12 +117,12 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				TotalPipelineRuns: 2,
 			}
 			pipelineRunsPerDays := []models.PipelineRunsPerDay{pipelineRunsPerDay1, pipelineRunsPerDay2}
-			err := daos.CreatePipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
+			err := store.CreatePipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
 			Expect(err).To(BeNil())
 
 			var findPipelineRunsPerDays []models.PipelineRunsPerDay
 			filter := bson.M{"date": bson.M{"$gte": date2}}
-			err = daos.ListPipelineRunsPerDaysByFilter(ctx, filter, &findPipelineRunsPerDays)
+			err = store.ListPipelineRunsPerDaysByFilter(ctx, filter, &findPipelineRunsPerDays)
 			Expect(err).To(BeNil())
 			Expect(findPipelineRunsPerDays).To(HaveLen(1))
 		})
@@ -138,7 +137,7 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				Date:              date,
 				TotalPipelineRuns: 1,
 			}
-			err := daos.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
+			err := store.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
 			Expect(err).To(BeNil())
 			Expect(pipelineRunsPerDay.ID).To(Not(BeEmpty()))
 
@@ -147,7 +146,7 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				Date:              date,
 				TotalPipelineRuns: 2,
 			}
-			err = daos.UpdatePipelineRunsPerDay(ctx, pipelineRunsPerDay.ID, &updatePipelineRunsPerDay)
+			err = store.UpdatePipelineRunsPerDay(ctx, pipelineRunsPerDay.ID, &updatePipelineRunsPerDay)
 			Expect(err).To(BeNil())
 			Expect(updatePipelineRunsPerDay.TotalPipelineRuns).To(Equal(2))
 		})
@@ -162,15 +161,15 @@ var _ = Describe("daos.PipelineRunsPerDays", func() {
 				Date:              date,
 				TotalPipelineRuns: 1,
 			}
-			err := daos.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
+			err := store.CreatePipelineRunsPerDay(ctx, pipelineID, &pipelineRunsPerDay)
 			Expect(err).To(BeNil())
 			Expect(pipelineRunsPerDay.ID).To(Not(BeEmpty()))
 
-			err = daos.DeletePipelineRunsPerDay(ctx, pipelineRunsPerDay.ID)
+			err = store.DeletePipelineRunsPerDay(ctx, pipelineRunsPerDay.ID)
 			Expect(err).To(BeNil())
 
 			var findPipelineRunsPerDay models.PipelineRunsPerDay
-			err = daos.GetPipelineRunsPerDay(ctx, pipelineRunsPerDay.ID, &findPipelineRunsPerDay)
+			err = store.GetPipelineRunsPerDay(ctx, pipelineRunsPerDay.ID, &findPipelineRunsPerDay)
 			Expect(err).To(Not(BeNil()))
 		})
 	})
@@ -0,0 +1,44 @@
+package daos
+
+import (
+	"context"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// default pullRequestCollection
+const pullRequestCollection = "pull_requests"
+
+// CreatePullRequests creates many new PullRequests, or replaces the PullRequests with the same ID in the repository.
+// They are written in ordered batches.
+func (s *Store) CreatePullRequests(ctx context.Context, repositoryID primitive.ObjectID, pullRequests *[]models.PullRequest) error {
+	filters := make([]bson.M, len(*pullRequests))
+	documents := make([]any, len(*pullRequests))
+	for index := range *pullRequests {
+		pullRequest := &(*pullRequests)[index]
+		pullRequest.RepositoryID = repositoryID
+
+		filters[index] = bson.M{"repository_id": repositoryID, "external_id": pullRequest.ID}
+		documents[index] = pullRequest
+	}
+
+	_, err := s.Service.UpsertMany(ctx, pullRequestCollection, filters, documents)
+	return err
+}
+
+// ListPullRequests retrieves many PullRequests.
+func (s *Store) ListPullRequests(ctx context.Context, repositoryID primitive.ObjectID, pullRequests *[]models.PullRequest) error {
+	filter := bson.M{"repository_id": repositoryID}
+	err := s.ListPullRequestsByFilter(ctx, filter, pullRequests)
+	return err
+}
+
+// ListPullRequestsByFilter retrieves many PullRequests conforming to a filter.
+func (s *Store) ListPullRequestsByFilter(ctx context.Context, filter bson.M, pullRequests *[]models.PullRequest) error {
+	ops := options.Find().SetSort(bson.M{"updated_at": 1})
+	err := s.Service.Find(ctx, pullRequestCollection, filter, pullRequests, ops)
+	return err
+}
@@ -0,0 +1,62 @@
+package daos_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("daos.PullRequest", func() {
+	ctx := context.Background()
+
+	var _ = When("CreatePullRequests", func() {
+		It("creates many new PullRequests, and replaces them afterwards.", func() {
+			repositoryID := primitive.NewObjectID()
+			pullRequests := []models.PullRequest{
+				{
+					ID:             118601409,
+					IID:            2,
+					UpdatedAt:      time.Date(2022, 12, 28, 13, 1, 11, 0, time.UTC),
+					MergeCommitSha: "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+				},
+			}
+			err := store.CreatePullRequests(ctx, repositoryID, &pullRequests)
+			Expect(err).To(BeNil())
+
+			pullRequests[0].FirstCommitDate = time.Date(2022, 12, 28, 12, 46, 21, 0, time.UTC)
+			err = store.CreatePullRequests(ctx, repositoryID, &pullRequests)
+			Expect(err).To(BeNil())
+
+			var findPullRequests []models.PullRequest
+			err = store.ListPullRequests(ctx, repositoryID, &findPullRequests)
+			Expect(err).To(BeNil())
+			Expect(findPullRequests).To(HaveLen(1))
+			Expect(findPullRequests[0].IID).To(Equal(2))
+			Expect(findPullRequests[0].FirstCommitDate).To(Equal(pullRequests[0].FirstCommitDate))
+		})
+	})
+
+	var _ = When("ListPullRequestsByFilter", func() {
+		It("retrieves many PullRequests conforming to a filter.", func() {
+			repositoryID := primitive.NewObjectID()
+			pullRequests := []models.PullRequest{
+				{ID: 118601409, MergeCommitSha: "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7"},
+				{ID: 118601410, MergeCommitSha: "3d95fe3bf954501d3832e50fdd803c5f9eae3f94"},
+			}
+			err := store.CreatePullRequests(ctx, repositoryID, &pullRequests)
+			Expect(err).To(BeNil())
+
+			var findPullRequests []models.PullRequest
+			filter := bson.M{"merge_commit_sha": bson.M{"$in": []string{"3d95fe3bf954501d3832e50fdd803c5f9eae3f94"}}}
+			err = store.ListPullRequestsByFilter(ctx, filter, &findPullRequests)
+			Expect(err).To(BeNil())
+			Expect(findPullRequests).To(HaveLen(1))
+			Expect(findPullRequests[0].ID).To(Equal(118601410))
+		})
+	})
+})
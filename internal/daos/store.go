@@ -0,0 +1,28 @@
+package daos
+
+import (
+	"context"
+
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+)
+
+// Store holds the connection to the database, and provides all DAOs as its methods.
+type Store struct {
+	Service *mongodb.Service
+}
+
+// NewStore creates a new Store connected to a database.
+func NewStore(ctx context.Context, database string) (*Store, error) {
+	service := mongodb.NewService()
+	err := service.Connect(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{Service: service}, nil
+}
+
+// Close removes the connection of a Store.
+func (s *Store) Close(ctx context.Context) error {
+	return s.Service.Disconnect(ctx)
+}
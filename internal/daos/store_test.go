@@ -0,0 +1,35 @@
+package daos_test
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+)
+
+var _ = Describe("daos.Store", func() {
+	ctx := context.Background()
+
+	var _ = When("NewStore", func() {
+		It("makes all DAOs of the Store use its connection.", func() {
+			otherStore, err := daos.NewStore(ctx, os.Getenv("MONGODB_DATABASE"))
+			Expect(err).To(BeNil())
+			defer otherStore.Close(ctx)
+
+			integration := models.Integration{
+				Type:     "vc",
+				Provider: "gitlab",
+			}
+			err = otherStore.CreateIntegration(ctx, &integration)
+			Expect(err).To(BeNil())
+
+			var findIntegration models.Integration
+			err = otherStore.Service.FindOneByID(ctx, "integrations", integration.ID, &findIntegration)
+			Expect(err).To(BeNil())
+			Expect(findIntegration.Provider).To(Equal("gitlab"))
+		})
+	})
+})
@@ -8,7 +8,7 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/daos"
 )
 
 func TestDAOS(t *testing.T) {
@@ -16,16 +16,21 @@ func TestDAOS(t *testing.T) {
 	RunSpecs(t, "daos Suite")
 }
 
+// store is connected to the test database before each spec
+var store *daos.Store
+
 var _ = BeforeEach(func() {
 	_ = godotenv.Load("./../../test/.env")
+
+	var err error
+	store, err = daos.NewStore(context.Background(), os.Getenv("MONGODB_DATABASE"))
+	Expect(err).To(BeNil())
 })
 
 var _ = AfterEach(func() {
 	ctx := context.Background()
-	service := mongodb.NewService()
-	service.Connect(ctx, os.Getenv("MONGODB_DATABASE"))
-	service.DB.Drop(ctx)
-	defer service.Disconnect(ctx)
+	store.Service.DB.Drop(ctx)
+	defer store.Close(ctx)
 
 	os.Remove("MONGODB_URI")
 	os.Remove("MONGODB_PORT")
@@ -0,0 +1,28 @@
+package daos
+
+import (
+	"context"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// default syncStateCollection
+const syncStateCollection = "sync_states"
+
+// GetSyncState retrieves the SyncState of a Dataflow.
+func (s *Store) GetSyncState(ctx context.Context, dataflowID primitive.ObjectID, syncState *models.SyncState) error {
+	filter := bson.M{"dataflow_id": dataflowID}
+	err := s.Service.FindOne(ctx, syncStateCollection, filter, syncState)
+	return err
+}
+
+// UpsertSyncState creates the SyncState of a Dataflow, or replaces it if it already exists.
+func (s *Store) UpsertSyncState(ctx context.Context, dataflowID primitive.ObjectID, syncState *models.SyncState) error {
+	syncState.DataflowID = dataflowID
+
+	filter := bson.M{"dataflow_id": dataflowID}
+	err := s.Service.UpsertOne(ctx, syncStateCollection, filter, syncState)
+	return err
+}
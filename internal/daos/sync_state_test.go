@@ -0,0 +1,55 @@
+package daos_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("daos.SyncState", func() {
+	ctx := context.Background()
+
+	var _ = When("UpsertSyncState", func() {
+		It("creates a new SyncState and replaces it afterwards.", func() {
+			dataflowID := primitive.NewObjectID()
+			syncState := models.SyncState{
+				LastCommitDate:      time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+				LastPipelineRunDate: time.Date(2022, 12, 27, 13, 20, 42, 0, time.UTC),
+				LastAlertDate:       time.Date(2022, 12, 27, 14, 0, 0, 0, time.UTC),
+			}
+			err := store.UpsertSyncState(ctx, dataflowID, &syncState)
+			Expect(err).To(BeNil())
+			Expect(syncState.ID).To(Not(BeEmpty()))
+
+			updateSyncState := models.SyncState{
+				LastCommitDate:      time.Date(2022, 12, 28, 13, 16, 42, 0, time.UTC),
+				LastPipelineRunDate: time.Date(2022, 12, 28, 13, 20, 42, 0, time.UTC),
+				LastAlertDate:       time.Date(2022, 12, 28, 14, 0, 0, 0, time.UTC),
+			}
+			err = store.UpsertSyncState(ctx, dataflowID, &updateSyncState)
+			Expect(err).To(BeNil())
+			Expect(updateSyncState.ID).To(Equal(syncState.ID))
+		})
+	})
+
+	var _ = When("GetSyncState", func() {
+		It("retrieves the SyncState of a Dataflow.", func() {
+			dataflowID := primitive.NewObjectID()
+			syncState := models.SyncState{
+				LastCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+			}
+			err := store.UpsertSyncState(ctx, dataflowID, &syncState)
+			Expect(err).To(BeNil())
+
+			var findSyncState models.SyncState
+			err = store.GetSyncState(ctx, dataflowID, &findSyncState)
+			Expect(err).To(BeNil())
+			Expect(findSyncState.ID).To(Equal(syncState.ID))
+			Expect(findSyncState.LastCommitDate).To(Equal(syncState.LastCommitDate))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// default migrationCollection
+const migrationCollection = "migrations"
+
+// Migration changes the indexes or documents of the database from the previous version to its own.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, service *mongodb.Service) error
+}
+
+// Run applies all Migrations newer than the version of the database in ascending order, and records each one applied.
+// Two instances starting at the same time can both apply a Migration, as only recording it is unique per version,
+// so the instance recording it second fails. Hence every Migration must be safe to apply twice.
+func Run(ctx context.Context, service *mongodb.Service, migrations []Migration) error {
+	err := service.CreateUniqueIndex(ctx, migrationCollection, bson.D{{Key: "version", Value: 1}})
+	if err != nil {
+		return err
+	}
+
+	version, err := Version(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			continue
+		}
+
+		err = migration.Up(ctx, service)
+		if err != nil {
+			return fmt.Errorf("could not apply migration %d (%s): %s", migration.Version, migration.Description, err.Error())
+		}
+
+		applied := models.Migration{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		}
+		err = service.InsertOne(ctx, migrationCollection, &applied)
+		if err != nil {
+			return fmt.Errorf("could not record migration %d: %s", migration.Version, err.Error())
+		}
+
+		log.Printf("Applied migration %d: %s", migration.Version, migration.Description)
+	}
+
+	return nil
+}
+
+// Version returns the version of the latest Migration applied, or 0 if none has been applied yet.
+func Version(ctx context.Context, service *mongodb.Service) (int, error) {
+	var applied []models.Migration
+	ops := options.Find().SetSort(bson.M{"version": -1}).SetLimit(1)
+	err := service.Find(ctx, migrationCollection, bson.M{}, &applied, ops)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(applied) == 0 {
+		return 0, nil
+	}
+	return applied[0].Version, nil
+}
@@ -0,0 +1,156 @@
+package migrations_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/database/migrations"
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestMigrations(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "migrations Suite")
+}
+
+var _ = Describe("migrations", func() {
+	ctx := context.Background()
+	var service *mongodb.Service
+
+	var _ = BeforeEach(func() {
+		_ = godotenv.Load("./../../../test/.env")
+
+		service = mongodb.NewService()
+		service.Connect(ctx, os.Getenv("MONGODB_DATABASE"))
+	})
+
+	var _ = AfterEach(func() {
+		service.DB.Drop(ctx)
+		defer service.Disconnect(ctx)
+	})
+
+	var _ = When("Run", func() {
+		It("applies all Migrations once.", func() {
+			err := migrations.Run(ctx, service, migrations.All)
+			Expect(err).To(BeNil())
+
+			version, err := migrations.Version(ctx, service)
+			Expect(err).To(BeNil())
+			Expect(version).To(Equal(len(migrations.All)))
+
+			err = migrations.Run(ctx, service, migrations.All)
+			Expect(err).To(BeNil())
+
+			var applied []models.Migration
+			err = service.Find(ctx, "migrations", bson.M{}, &applied, options.Find())
+			Expect(err).To(BeNil())
+			Expect(applied).To(HaveLen(len(migrations.All)))
+		})
+
+		It("applies only the Migrations newer than the version of the database.", func() {
+			applied := 0
+			up := func(ctx context.Context, service *mongodb.Service) error {
+				applied++
+				return nil
+			}
+
+			err := migrations.Run(ctx, service, []migrations.Migration{{Version: 1, Up: up}})
+			Expect(err).To(BeNil())
+
+			err = migrations.Run(ctx, service, []migrations.Migration{{Version: 1, Up: up}, {Version: 2, Up: up}})
+			Expect(err).To(BeNil())
+			Expect(applied).To(Equal(2))
+		})
+
+		It("sets the defaults of Dataflows created without them.", func() {
+			err := service.InsertOne(ctx, "dataflows", &bson.M{"repository": bson.M{"namespaced_name": "foo/bar"}})
+			Expect(err).To(BeNil())
+
+			err = migrations.Run(ctx, service, migrations.All)
+			Expect(err).To(BeNil())
+
+			var dataflow models.Dataflow
+			err = service.FindOne(ctx, "dataflows", bson.M{}, &dataflow)
+			Expect(err).To(BeNil())
+			Expect(dataflow.BackfillDays).To(Equal(30))
+			Expect(dataflow.SyncInterval).To(Equal(60))
+		})
+
+		It("deletes duplicates before creating unique indexes.", func() {
+			commits := []any{
+				bson.M{"repository_id": "foo", "sha": "3d95fe3bf954501d3832e50fdd803c5f9eae3f94"},
+				bson.M{"repository_id": "foo", "sha": "3d95fe3bf954501d3832e50fdd803c5f9eae3f94"},
+			}
+			_, err := service.InsertMany(ctx, "commits", commits)
+			Expect(err).To(BeNil())
+
+			err = migrations.Run(ctx, service, migrations.All)
+			Expect(err).To(BeNil())
+
+			var findCommits []bson.M
+			err = service.Find(ctx, "commits", bson.M{}, &findCommits, options.Find())
+			Expect(err).To(BeNil())
+			Expect(findCommits).To(HaveLen(1))
+		})
+
+		It("renames the legacy type of version control Integrations.", func() {
+			err := service.InsertOne(ctx, "integrations", &models.Integration{Type: models.LegacySourceControl, Provider: "gitlab"})
+			Expect(err).To(BeNil())
+
+			err = migrations.Run(ctx, service, migrations.All)
+			Expect(err).To(BeNil())
+
+			var integration models.Integration
+			err = service.FindOne(ctx, "integrations", bson.M{}, &integration)
+			Expect(err).To(BeNil())
+			Expect(integration.Type).To(Equal(models.VersionControl))
+		})
+
+		It("fills the lead times of changes per days created without them.", func() {
+			dataflow := models.Dataflow{
+				Repository: models.Repository{ID: primitive.NewObjectID()},
+				Pipeline:   models.Pipeline{ID: primitive.NewObjectID()},
+				Deployment: models.Deployment{ID: primitive.NewObjectID()},
+			}
+			err := service.InsertOne(ctx, "dataflows", &dataflow)
+			Expect(err).To(BeNil())
+
+			change := models.Change{
+				RepositoryID:    dataflow.Repository.ID,
+				PipelineID:      dataflow.Pipeline.ID,
+				FirstCommitDate: time.Date(2022, 12, 27, 8, 0, 0, 0, time.UTC),
+				DeploymentDate:  time.Date(2022, 12, 27, 10, 0, 0, 0, time.UTC),
+			}
+			err = service.InsertOne(ctx, "changes", &change)
+			Expect(err).To(BeNil())
+
+			// created before the lead times were recorded
+			changesPerDay := bson.M{
+				"repository_id":   dataflow.Repository.ID,
+				"pipeline_id":     dataflow.Pipeline.ID,
+				"date":            time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC),
+				"total_changes":   1,
+				"total_lead_time": 7200.0,
+			}
+			err = service.InsertOne(ctx, "changes_per_days", &changesPerDay)
+			Expect(err).To(BeNil())
+
+			err = migrations.Run(ctx, service, migrations.All)
+			Expect(err).To(BeNil())
+
+			var findChangesPerDay models.ChangesPerDay
+			err = service.FindOne(ctx, "changes_per_days", bson.M{}, &findChangesPerDay)
+			Expect(err).To(BeNil())
+			Expect(findChangesPerDay.LeadTimes).To(Equal([]float64{7200}))
+		})
+	})
+})
@@ -0,0 +1,253 @@
+package migrations
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All holds every Migration by ascending version.
+// A released Migration must never be changed, only be followed by a new one,
+// hence the collections and fields are spelled out instead of taken from the DAOs.
+var All = []Migration{
+	{
+		Version:     1,
+		Description: "create unique indexes on the natural keys of upserted documents",
+		Up: createIndexes(true, map[string]bson.D{
+			"commits":                {{Key: "repository_id", Value: 1}, {Key: "sha", Value: 1}},
+			"pipeline_runs":          {{Key: "pipeline_id", Value: 1}, {Key: "external_id", Value: 1}},
+			"changes_per_days":       {{Key: "repository_id", Value: 1}, {Key: "pipeline_id", Value: 1}, {Key: "date", Value: 1}},
+			"pipeline_runs_per_days": {{Key: "pipeline_id", Value: 1}, {Key: "date", Value: 1}},
+			"incidents_per_days":     {{Key: "deployment_id", Value: 1}, {Key: "date", Value: 1}},
+			"sync_states":            {{Key: "dataflow_id", Value: 1}},
+		}),
+	},
+	{
+		Version:     2,
+		Description: "create indexes for the queries of syncs and metrics",
+		Up: createIndexes(false, map[string]bson.D{
+			"commits":       {{Key: "repository_id", Value: 1}, {Key: "created_at", Value: 1}},
+			"pipeline_runs": {{Key: "pipeline_id", Value: 1}, {Key: "updated_at", Value: 1}},
+			"changes":       {{Key: "repository_id", Value: 1}, {Key: "pipeline_id", Value: 1}, {Key: "deployment_date", Value: 1}},
+			"incidents":     {{Key: "deployment_id", Value: 1}, {Key: "start_date", Value: 1}},
+			"jobs":          {{Key: "dataflow_id", Value: 1}},
+		}),
+	},
+	{
+		Version:     3,
+		Description: "set the default backfill window and sync interval of dataflows created without them",
+		// 30 days and 60 minutes, the defaults at the time of this Migration
+		Up: func(ctx context.Context, service *mongodb.Service) error {
+			filter := bson.M{"backfill_days": bson.M{"$exists": false}}
+			update := bson.M{"$set": bson.M{"backfill_days": 30}}
+			err := service.UpdateMany(ctx, "dataflows", filter, update)
+			if err != nil {
+				return err
+			}
+
+			filter = bson.M{"sync_interval": bson.M{"$exists": false}}
+			update = bson.M{"$set": bson.M{"sync_interval": 60}}
+			err = service.UpdateMany(ctx, "dataflows", filter, update)
+			return err
+		},
+	},
+	{
+		Version:     4,
+		Description: "create a unique index on the natural key of pull requests",
+		Up: createIndexes(true, map[string]bson.D{
+			"pull_requests": {{Key: "repository_id", Value: 1}, {Key: "external_id", Value: 1}},
+		}),
+	},
+	{
+		Version:     5,
+		Description: "create indexes on the date of daily aggregates for general metrics rolled up to weeks and months",
+		Up: createIndexes(false, map[string]bson.D{
+			"changes_per_days":       {{Key: "date", Value: 1}},
+			"pipeline_runs_per_days": {{Key: "date", Value: 1}},
+			"incidents_per_days":     {{Key: "date", Value: 1}},
+		}),
+	},
+	{
+		Version:     6,
+		Description: "rename the legacy type sc of version control integrations to vc",
+		Up: func(ctx context.Context, service *mongodb.Service) error {
+			filter := bson.M{"type": "sc"}
+			update := bson.M{"$set": bson.M{"type": "vc"}}
+			return service.UpdateMany(ctx, "integrations", filter, update)
+		},
+	},
+	{
+		Version:     7,
+		Description: "replace the indexes of changes and incidents by unique indexes on their natural keys",
+		Up: func(ctx context.Context, service *mongodb.Service) error {
+			// the indexes created by version 2 have the same keys, but are not unique
+			for collection, name := range map[string]string{
+				"changes":   "repository_id_1_pipeline_id_1_deployment_date_1",
+				"incidents": "deployment_id_1_start_date_1",
+			} {
+				err := service.DropIndex(ctx, collection, name)
+				if err != nil {
+					return err
+				}
+			}
+
+			return createIndexes(true, map[string]bson.D{
+				"changes":   {{Key: "repository_id", Value: 1}, {Key: "pipeline_id", Value: 1}, {Key: "deployment_date", Value: 1}},
+				"incidents": {{Key: "deployment_id", Value: 1}, {Key: "start_date", Value: 1}},
+			})(ctx, service)
+		},
+	},
+	{
+		Version:     8,
+		Description: "fill the lead times and durations of daily aggregates created before they were recorded",
+		Up:          fillDailyValues,
+	},
+}
+
+// createIndexes returns a Migration step creating an index on the keys of each collection.
+// Before a unique index is created, the duplicates written before it existed are deleted, keeping the latest one.
+func createIndexes(unique bool, indexes map[string]bson.D) func(ctx context.Context, service *mongodb.Service) error {
+	return func(ctx context.Context, service *mongodb.Service) error {
+		for collection, keys := range indexes {
+			if !unique {
+				err := service.CreateIndex(ctx, collection, keys)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			deleted, err := service.DeleteDuplicates(ctx, collection, keys)
+			if err != nil {
+				return err
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d duplicates of %s", deleted, collection)
+			}
+
+			err = service.CreateUniqueIndex(ctx, collection, keys)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// dataflowKeys holds the fields of a dataflow needed by fillDailyValues.
+type dataflowKeys struct {
+	Repository struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"repository"`
+	Pipeline struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"pipeline"`
+	Deployment struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"deployment"`
+	Timezone string `bson:"timezone"`
+}
+
+// fillDailyValues sets the lead times of changes per days and the durations of incidents per days
+// missing them, from the changes and incidents of the same day in the timezone of their dataflow.
+func fillDailyValues(ctx context.Context, service *mongodb.Service) error {
+	var dataflows []dataflowKeys
+	err := service.Find(ctx, "dataflows", bson.M{}, &dataflows, options.Find())
+	if err != nil {
+		return err
+	}
+
+	for _, dataflow := range dataflows {
+		// an empty timezone is UTC
+		location, err := time.LoadLocation(dataflow.Timezone)
+		if err != nil {
+			return err
+		}
+
+		err = fillDays(ctx, service, location,
+			"changes_per_days", bson.M{"repository_id": dataflow.Repository.ID, "pipeline_id": dataflow.Pipeline.ID}, "lead_times",
+			"changes", bson.M{"repository_id": dataflow.Repository.ID, "pipeline_id": dataflow.Pipeline.ID}, "first_commit_date", "deployment_date", "deployment_date")
+		if err != nil {
+			return err
+		}
+
+		err = fillDays(ctx, service, location,
+			"incidents_per_days", bson.M{"deployment_id": dataflow.Deployment.ID}, "durations",
+			"incidents", bson.M{"deployment_id": dataflow.Deployment.ID}, "start_date", "end_date", "start_date")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillDays sets a field of the daily aggregates matching a filter, where it is missing or empty,
+// to the seconds from start to end of each raw document matching a filter, bucketed per day by a date field.
+func fillDays(ctx context.Context, service *mongodb.Service, location *time.Location,
+	aggregates string, aggregateFilter bson.M, field string,
+	raws string, rawFilter bson.M, start string, end string, date string) error {
+	filter := bson.M{field: bson.M{"$in": bson.A{nil, bson.A{}}}}
+	for key, value := range aggregateFilter {
+		filter[key] = value
+	}
+
+	var days []bson.M
+	err := service.Find(ctx, aggregates, filter, &days, options.Find())
+	if err != nil || len(days) == 0 {
+		return err
+	}
+
+	var documents []bson.M
+	ops := options.Find().SetSort(bson.D{{Key: date, Value: 1}})
+	err = service.Find(ctx, raws, rawFilter, &documents, ops)
+	if err != nil {
+		return err
+	}
+
+	values := map[time.Time][]float64{}
+	for _, document := range documents {
+		startDate, startOk := document[start].(primitive.DateTime)
+		endDate, endOk := document[end].(primitive.DateTime)
+		dayDate, dateOk := document[date].(primitive.DateTime)
+		if !startOk || !endOk || !dateOk {
+			continue
+		}
+
+		// the same bucketing as the aggregates, a date at midnight UTC for the day in the location
+		t := dayDate.Time().In(location)
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		values[day] = append(values[day], endDate.Time().Sub(startDate.Time()).Seconds())
+	}
+
+	filled := 0
+	for _, aggregate := range days {
+		dayDate, ok := aggregate["date"].(primitive.DateTime)
+		if !ok {
+			continue
+		}
+
+		// days without raw documents left keep their totals only
+		dayValues, ok := values[dayDate.Time().UTC()]
+		if !ok {
+			continue
+		}
+
+		update := bson.M{"$set": bson.M{field: dayValues}}
+		err = service.UpdateMany(ctx, aggregates, bson.M{"_id": aggregate["_id"]}, update)
+		if err != nil {
+			return err
+		}
+		filled++
+	}
+
+	if filled > 0 {
+		log.Printf("Filled the %s of %d %s", field, filled, aggregates)
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,6 +14,34 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Codes of the command errors of MongoDB for a missing collection or index.
+const (
+	namespaceNotFound = 26
+	indexNotFound     = 27
+)
+
+// BatchSize is the maximum number of documents written in one round trip by the bulk writes.
+const BatchSize = 1000
+
+// BatchError reports the failure of a batch of an ordered bulk write.
+// All documents before the one at Offset+Written have been written, all documents after it have not.
+type BatchError struct {
+	Batch   int
+	Offset  int
+	Written int
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("could not write batch %d after %d of its documents: %s", e.Batch, e.Written, e.Err.Error())
+}
+
+// Unwrap returns the error of the failed write.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
 // Service provides the functionality to use
 type Service struct {
 	Client *mongo.Client
@@ -156,3 +186,326 @@ func (s *Service) DeleteOne(ctx context.Context, collection string, objectID pri
 
 	return nil
 }
+
+// UpsertOne replaces the document matching a filter in a collection, or inserts it if none matches.
+func (s *Service) UpsertOne(ctx context.Context, collection string, filter bson.M, v any) error {
+	coll := s.DB.Collection(collection)
+
+	ops := options.Replace().SetUpsert(true)
+	_, err := coll.ReplaceOne(ctx, filter, v, ops)
+	if err != nil {
+		return err
+	}
+
+	err = s.FindOne(ctx, collection, filter, v)
+
+	return err
+}
+
+// DeleteMany deletes all documents matching a filter in a collection.
+func (s *Service) DeleteMany(ctx context.Context, collection string, filter bson.M) error {
+	coll := s.DB.Collection(collection)
+
+	_, err := coll.DeleteMany(ctx, filter)
+
+	return err
+}
+
+// CreateUniqueIndex creates a unique index on the keys of a collection, if it does not exist yet.
+func (s *Service) CreateUniqueIndex(ctx context.Context, collection string, keys bson.D) error {
+	coll := s.DB.Collection(collection)
+
+	index := mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := coll.Indexes().CreateOne(ctx, index)
+
+	return err
+}
+
+// DeleteDuplicates deletes all but the latest inserted document of each group of documents
+// sharing the same values of the keys in a collection, e.g. before a unique index on the keys is created.
+// It returns the number of documents deleted.
+func (s *Service) DeleteDuplicates(ctx context.Context, collection string, keys bson.D) (int, error) {
+	coll := s.DB.Collection(collection)
+
+	group := bson.M{}
+	for _, key := range keys {
+		group[key.Key] = fmt.Sprintf("$%s", key.Key)
+	}
+
+	// ObjectIDs ascend with their creation, so the first ID of each group is the latest inserted
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"_id": -1}}},
+		{{Key: "$group", Value: bson.M{"_id": group, "ids": bson.M{"$push": "$_id"}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+	cursor, err := coll.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, err
+	}
+
+	var duplicates []struct {
+		IDs []primitive.ObjectID `bson:"ids"`
+	}
+	err = cursor.All(ctx, &duplicates)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, duplicate := range duplicates {
+		deleteResult, err := coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": duplicate.IDs[1:]}})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int(deleteResult.DeletedCount)
+	}
+
+	return deleted, nil
+}
+
+// DropIndex drops an index of a collection by its name, if it exists.
+func (s *Service) DropIndex(ctx context.Context, collection string, name string) error {
+	coll := s.DB.Collection(collection)
+
+	_, err := coll.Indexes().DropOne(ctx, name)
+
+	var commandError mongo.CommandError
+	if errors.As(err, &commandError) && (commandError.Code == namespaceNotFound || commandError.Code == indexNotFound) {
+		return nil
+	}
+	return err
+}
+
+// InsertMany inserts many documents into a collection in ordered batches,
+// and returns the IDs of all documents written, even if a batch failed.
+func (s *Service) InsertMany(ctx context.Context, collection string, vs []any) ([]primitive.ObjectID, error) {
+	coll := s.DB.Collection(collection)
+	ops := options.InsertMany().SetOrdered(true)
+
+	ids := make([]primitive.ObjectID, 0, len(vs))
+	for offset := 0; offset < len(vs); offset += BatchSize {
+		end := batchEnd(offset, len(vs))
+
+		insertManyResult, err := coll.InsertMany(ctx, vs[offset:end], ops)
+		if err != nil {
+			written := writtenBefore(err)
+			if insertManyResult != nil {
+				ids = appendObjectIDs(ids, insertManyResult.InsertedIDs[:written])
+			}
+			return ids, &BatchError{Batch: offset / BatchSize, Offset: offset, Written: written, Err: err}
+		}
+		ids = appendObjectIDs(ids, insertManyResult.InsertedIDs)
+	}
+
+	return ids, nil
+}
+
+// BulkWrite executes many write operations on a collection in ordered batches.
+// The indices of the upserted IDs in the result refer to all write operations, not to a batch.
+func (s *Service) BulkWrite(ctx context.Context, collection string, writeModels []mongo.WriteModel) (*mongo.BulkWriteResult, error) {
+	coll := s.DB.Collection(collection)
+	ops := options.BulkWrite().SetOrdered(true)
+
+	result := mongo.BulkWriteResult{UpsertedIDs: map[int64]interface{}{}}
+	for offset := 0; offset < len(writeModels); offset += BatchSize {
+		end := batchEnd(offset, len(writeModels))
+
+		bulkWriteResult, err := coll.BulkWrite(ctx, writeModels[offset:end], ops)
+		if bulkWriteResult != nil {
+			result.InsertedCount += bulkWriteResult.InsertedCount
+			result.MatchedCount += bulkWriteResult.MatchedCount
+			result.ModifiedCount += bulkWriteResult.ModifiedCount
+			result.DeletedCount += bulkWriteResult.DeletedCount
+			result.UpsertedCount += bulkWriteResult.UpsertedCount
+			for index, id := range bulkWriteResult.UpsertedIDs {
+				result.UpsertedIDs[int64(offset)+index] = id
+			}
+		}
+		if err != nil {
+			return &result, &BatchError{Batch: offset / BatchSize, Offset: offset, Written: writtenBefore(err), Err: err}
+		}
+	}
+
+	return &result, nil
+}
+
+// UpsertMany replaces the documents matching each filter in a collection, or inserts them if none match,
+// in ordered batches. It returns the IDs of the documents inserted or replaced by their index.
+// If a batch fails, only the IDs of the documents inserted before are returned.
+func (s *Service) UpsertMany(ctx context.Context, collection string, filters []bson.M, vs []any) (map[int]primitive.ObjectID, error) {
+	writeModels := make([]mongo.WriteModel, len(vs))
+	for index, v := range vs {
+		writeModels[index] = mongo.NewReplaceOneModel().SetFilter(filters[index]).SetReplacement(v).SetUpsert(true)
+	}
+
+	result, err := s.BulkWrite(ctx, collection, writeModels)
+
+	ids := map[int]primitive.ObjectID{}
+	if result != nil {
+		for index, id := range result.UpsertedIDs {
+			if objectID, ok := id.(primitive.ObjectID); ok {
+				ids[int(index)] = objectID
+			}
+		}
+	}
+	if err != nil {
+		return ids, err
+	}
+
+	// a replacement does not return the ID of the document replaced
+	err = s.findIDs(ctx, collection, filters, ids)
+	return ids, err
+}
+
+// findIDs finds the IDs of the documents matching each filter without an ID yet in ordered batches,
+// assuming each filter matches at most one document, e.g. a filter on a natural key.
+func (s *Service) findIDs(ctx context.Context, collection string, filters []bson.M, ids map[int]primitive.ObjectID) error {
+	coll := s.DB.Collection(collection)
+
+	for offset := 0; offset < len(filters); offset += BatchSize {
+		end := batchEnd(offset, len(filters))
+
+		missing := []int{}
+		or := []bson.M{}
+		for index := offset; index < end; index++ {
+			if _, ok := ids[index]; !ok {
+				missing = append(missing, index)
+				or = append(or, filters[index])
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		cursor, err := coll.Find(ctx, bson.M{"$or": or})
+		if err != nil {
+			return err
+		}
+
+		var documents []bson.Raw
+		err = cursor.All(ctx, &documents)
+		if err != nil {
+			return err
+		}
+
+		// the documents are keyed on the values of the fields filtered, for each set of fields filtered
+		idsByKey := map[string]primitive.ObjectID{}
+		fieldsSeen := map[string]bool{}
+		for _, index := range missing {
+			fields := filterFields(filters[index])
+			if fieldsSeen[strings.Join(fields, ",")] {
+				continue
+			}
+			fieldsSeen[strings.Join(fields, ",")] = true
+
+			for _, document := range documents {
+				id, ok := document.Lookup("_id").ObjectIDOK()
+				if !ok {
+					continue
+				}
+				idsByKey[documentKey(document, fields)] = id
+			}
+		}
+
+		for _, index := range missing {
+			key, err := filterKey(filters[index])
+			if err != nil {
+				return err
+			}
+			if id, ok := idsByKey[key]; ok {
+				ids[index] = id
+			}
+		}
+	}
+
+	return nil
+}
+
+// filterFields returns the fields of a filter in ascending order.
+func filterFields(filter bson.M) []string {
+	fields := make([]string, 0, len(filter))
+	for field := range filter {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// filterKey returns a key identifying the values of a filter by their BSON encoding.
+func filterKey(filter bson.M) (string, error) {
+	var key strings.Builder
+	for _, field := range filterFields(filter) {
+		valueType, data, err := bson.MarshalValue(filter[field])
+		if err != nil {
+			return "", err
+		}
+		key.WriteString(field)
+		key.WriteByte(byte(valueType))
+		key.Write(data)
+	}
+	return key.String(), nil
+}
+
+// documentKey returns a key identifying the values of the fields of a document by their BSON encoding,
+// equal to the filterKey of a filter on these fields matching the document.
+func documentKey(document bson.Raw, fields []string) string {
+	var key strings.Builder
+	for _, field := range fields {
+		value := document.Lookup(field)
+		key.WriteString(field)
+		key.WriteByte(byte(value.Type))
+		key.Write(value.Value)
+	}
+	return key.String()
+}
+
+// CreateIndex creates an index on the keys of a collection, if it does not exist yet.
+func (s *Service) CreateIndex(ctx context.Context, collection string, keys bson.D) error {
+	coll := s.DB.Collection(collection)
+
+	index := mongo.IndexModel{
+		Keys: keys,
+	}
+	_, err := coll.Indexes().CreateOne(ctx, index)
+
+	return err
+}
+
+// UpdateMany updates all documents matching a filter in a collection.
+func (s *Service) UpdateMany(ctx context.Context, collection string, filter bson.M, update bson.M) error {
+	coll := s.DB.Collection(collection)
+
+	_, err := coll.UpdateMany(ctx, filter, update)
+
+	return err
+}
+
+// batchEnd returns the end of the batch starting at an offset.
+func batchEnd(offset int, length int) int {
+	if offset+BatchSize < length {
+		return offset + BatchSize
+	}
+	return length
+}
+
+// writtenBefore returns the number of documents of an ordered batch written before it failed.
+func writtenBefore(err error) int {
+	var bulkWriteException mongo.BulkWriteException
+	if errors.As(err, &bulkWriteException) && len(bulkWriteException.WriteErrors) > 0 {
+		return bulkWriteException.WriteErrors[0].Index
+	}
+	return 0
+}
+
+// appendObjectIDs appends the ObjectIDs among many IDs.
+func appendObjectIDs(objectIDs []primitive.ObjectID, ids []interface{}) []primitive.ObjectID {
+	for _, id := range ids {
+		if objectID, ok := id.(primitive.ObjectID); ok {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	return objectIDs
+}
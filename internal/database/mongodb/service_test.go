@@ -2,8 +2,10 @@ package mongodb_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
@@ -11,6 +13,7 @@ import (
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
@@ -183,4 +186,182 @@ var _ = Describe("mongodb.Service", func() {
 			Expect(err).To(Not(BeNil()))
 		})
 	})
+
+	var _ = When("UpsertOne", func() {
+		It("inserts a document once and replaces it afterwards", func() {
+			filter := bson.M{"provider": "gitlab"}
+			integration := models.Integration{
+				Type:        "vc",
+				Provider:    "gitlab",
+				BearerToken: "bearertoken",
+				URI:         "https://gitlab.com",
+			}
+			err := service.UpsertOne(ctx, "integrations", filter, &integration)
+			Expect(err).To(BeNil())
+			Expect(integration.ID).To(Not(BeNil()))
+
+			upsertIntegration := models.Integration{
+				Type:        "vc",
+				Provider:    "gitlab",
+				BearerToken: "newbearertoken",
+				URI:         "https://gitlab.com",
+			}
+			err = service.UpsertOne(ctx, "integrations", filter, &upsertIntegration)
+			Expect(err).To(BeNil())
+			Expect(upsertIntegration.ID).To(Equal(integration.ID))
+			Expect(upsertIntegration.BearerToken).To(Equal("newbearertoken"))
+		})
+	})
+
+	var _ = When("DeleteMany", func() {
+		It("deletes all documents matching a filter in a collection", func() {
+			for _, provider := range []string{"gitlab", "gitlab", "github"} {
+				integration := models.Integration{
+					Type:     "vc",
+					Provider: provider,
+				}
+				service.InsertOne(ctx, "integrations", &integration)
+			}
+
+			err := service.DeleteMany(ctx, "integrations", bson.M{"provider": "gitlab"})
+			Expect(err).To(BeNil())
+
+			var integrations []models.Integration
+			err = service.Find(ctx, "integrations", bson.M{}, &integrations, options.Find())
+			Expect(err).To(BeNil())
+			Expect(integrations).To(HaveLen(1))
+		})
+	})
+
+	var _ = When("CreateUniqueIndex", func() {
+		It("rejects a second document with the same keys", func() {
+			err := service.CreateUniqueIndex(ctx, "integrations", bson.D{{Key: "provider", Value: 1}})
+			Expect(err).To(BeNil())
+
+			integration := models.Integration{
+				Type:     "vc",
+				Provider: "gitlab",
+			}
+			err = service.InsertOne(ctx, "integrations", &integration)
+			Expect(err).To(BeNil())
+
+			duplicateIntegration := models.Integration{
+				Type:     "cicd",
+				Provider: "gitlab",
+			}
+			err = service.InsertOne(ctx, "integrations", &duplicateIntegration)
+			Expect(mongo.IsDuplicateKeyError(err)).To(BeTrue())
+		})
+	})
+
+	var _ = When("DropIndex", func() {
+		It("drops an index, and ignores a missing one", func() {
+			err := service.CreateUniqueIndex(ctx, "integrations", bson.D{{Key: "provider", Value: 1}})
+			Expect(err).To(BeNil())
+
+			err = service.DropIndex(ctx, "integrations", "provider_1")
+			Expect(err).To(BeNil())
+
+			err = service.DropIndex(ctx, "integrations", "provider_1")
+			Expect(err).To(BeNil())
+
+			_, err = service.InsertMany(ctx, "integrations", []any{
+				&models.Integration{Type: "vc", Provider: "gitlab"},
+				&models.Integration{Type: "cicd", Provider: "gitlab"},
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	var _ = When("DeleteDuplicates", func() {
+		It("keeps only the latest document with the same keys", func() {
+			integrations := []any{
+				&models.Integration{Type: "vc", Provider: "gitlab", URI: "https://old.example.com"},
+				&models.Integration{Type: "vc", Provider: "gitlab", URI: "https://new.example.com"},
+				&models.Integration{Type: "cicd", Provider: "gitlab"},
+			}
+			_, err := service.InsertMany(ctx, "integrations", integrations)
+			Expect(err).To(BeNil())
+
+			deleted, err := service.DeleteDuplicates(ctx, "integrations", bson.D{{Key: "type", Value: 1}, {Key: "provider", Value: 1}})
+			Expect(err).To(BeNil())
+			Expect(deleted).To(Equal(1))
+
+			var findIntegrations []models.Integration
+			err = service.Find(ctx, "integrations", bson.M{"type": "vc"}, &findIntegrations, options.Find())
+			Expect(err).To(BeNil())
+			Expect(findIntegrations).To(HaveLen(1))
+			Expect(findIntegrations[0].URI).To(Equal("https://new.example.com"))
+		})
+	})
+
+	var _ = When("InsertMany", func() {
+		It("inserts many documents into a collection", func() {
+			integrations := []any{
+				&models.Integration{Type: "vc", Provider: "gitlab"},
+				&models.Integration{Type: "cicd", Provider: "github"},
+			}
+			ids, err := service.InsertMany(ctx, "integrations", integrations)
+			Expect(err).To(BeNil())
+			Expect(ids).To(HaveLen(2))
+		})
+
+		It("reports the documents written before a batch failed", func() {
+			err := service.CreateUniqueIndex(ctx, "integrations", bson.D{{Key: "provider", Value: 1}})
+			Expect(err).To(BeNil())
+
+			integrations := []any{
+				&models.Integration{Type: "vc", Provider: "gitlab"},
+				&models.Integration{Type: "cicd", Provider: "gitlab"},
+				&models.Integration{Type: "cicd", Provider: "github"},
+			}
+			ids, err := service.InsertMany(ctx, "integrations", integrations)
+			Expect(ids).To(HaveLen(1))
+
+			var batchErr *mongodb.BatchError
+			Expect(errors.As(err, &batchErr)).To(BeTrue())
+			Expect(batchErr.Batch).To(Equal(0))
+			Expect(batchErr.Written).To(Equal(1))
+		})
+	})
+
+	var _ = When("UpsertMany", func() {
+		It("inserts many documents once and replaces them afterwards", func() {
+			filters := []bson.M{{"provider": "gitlab"}, {"provider": "github"}}
+			integrations := []any{
+				&models.Integration{Type: "vc", Provider: "gitlab"},
+				&models.Integration{Type: "vc", Provider: "github"},
+			}
+			ids, err := service.UpsertMany(ctx, "integrations", filters, integrations)
+			Expect(err).To(BeNil())
+			Expect(ids).To(HaveLen(2))
+
+			integrations = []any{
+				&models.Integration{Type: "cicd", Provider: "gitlab"},
+				&models.Integration{Type: "cicd", Provider: "github"},
+			}
+			replacedIDs, err := service.UpsertMany(ctx, "integrations", filters, integrations)
+			Expect(err).To(BeNil())
+			Expect(replacedIDs).To(Equal(ids))
+
+			var findIntegrations []models.Integration
+			err = service.Find(ctx, "integrations", bson.M{"type": "cicd"}, &findIntegrations, options.Find())
+			Expect(err).To(BeNil())
+			Expect(findIntegrations).To(HaveLen(2))
+		})
+
+		It("returns the IDs of documents replaced on filters of several fields and types", func() {
+			createdAt := time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC)
+			filters := []bson.M{{"provider": "gitlab", "created_at": createdAt}}
+			integrations := []any{bson.M{"provider": "gitlab", "created_at": createdAt, "type": "vc"}}
+
+			ids, err := service.UpsertMany(ctx, "integrations", filters, integrations)
+			Expect(err).To(BeNil())
+
+			integrations = []any{bson.M{"provider": "gitlab", "created_at": createdAt, "type": "cicd"}}
+			replacedIDs, err := service.UpsertMany(ctx, "integrations", filters, integrations)
+			Expect(err).To(BeNil())
+			Expect(replacedIDs).To(Equal(ids))
+		})
+	})
 })
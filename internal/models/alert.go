@@ -4,5 +4,6 @@ import "time"
 
 // Alert describes a single alert.
 type Alert struct {
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time         `json:"created_at"`
+	Labels    map[string]string `json:"labels,omitempty"` // labels of the series the alert is a sample of
 }
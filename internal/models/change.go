@@ -8,10 +8,12 @@ import (
 
 // Change describes a single change from first commit to deployment.
 type Change struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty"`
-	RepositoryID    primitive.ObjectID `json:"repository_id" bson:"repository_id"`
-	PipelineID      primitive.ObjectID `json:"pipeline_id" bson:"pipeline_id"`
-	FirstCommitDate time.Time          `json:"first_commit_date" bson:"first_commit_date"`
-	DeploymentDate  time.Time          `json:"deployment_date" bson:"deployment_date"`
-	LeadTime        float64            `json:"lead_time" bson:"lead_time"`
+	ID              primitive.ObjectID      `bson:"_id,omitempty"`
+	RepositoryID    primitive.ObjectID      `json:"repository_id" bson:"repository_id"`
+	PipelineID      primitive.ObjectID      `json:"pipeline_id" bson:"pipeline_id"`
+	FirstCommitDate time.Time               `json:"first_commit_date" bson:"first_commit_date"`
+	DeploymentDate  time.Time               `json:"deployment_date" bson:"deployment_date"`
+	LeadTime        float64                 `json:"lead_time" bson:"lead_time"`
+	MergeRequests   []MergeRequestReference `json:"merge_requests,omitempty" bson:"merge_requests,omitempty"`       // only set by the LeadTimeMergeRequests strategy
+	CommitLeadTimes []float64               `json:"commit_lead_times,omitempty" bson:"commit_lead_times,omitempty"` // lead time of each commit deployed, only set if LeadTimePerCommit
 }
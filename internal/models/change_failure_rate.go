@@ -12,6 +12,8 @@ type ChangeFailureRate struct {
 	StartDate        time.Time          `bson:"start_date" json:"start_date"`
 	EndDate          time.Time          `bson:"end_date" json:"end_date"`
 	Window           int                `bson:"window" json:"window"`
+	Granularity      string             `bson:"granularity" json:"granularity"`
+	Timezone         string             `bson:"timezone" json:"timezone"`
 	Dates            []time.Time        `bson:"date" json:"date"`
 	DailyIncidents   []int              `bson:"daily_incidents" json:"daily_incidents"`
 	DailyDeployments []int              `bson:"daily_deployments" json:"daily_deployments"`
@@ -23,6 +25,8 @@ type GeneralChangeFailureRate struct {
 	StartDate        time.Time   `bson:"start_date" json:"start_date"`
 	EndDate          time.Time   `bson:"end_date" json:"end_date"`
 	Window           int         `bson:"window" json:"window"`
+	Granularity      string      `bson:"granularity" json:"granularity"`
+	Timezone         string      `bson:"timezone" json:"timezone"`
 	Dates            []time.Time `bson:"date" json:"date"`
 	DailyIncidents   []int       `bson:"daily_incidents" json:"daily_incidents"`
 	DailyDeployments []int       `bson:"daily_deployments" json:"daily_deployments"`
@@ -8,10 +8,14 @@ import (
 
 // ChangesPerDay represents the daily changes.
 type ChangesPerDay struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty"`
-	RepositoryID  primitive.ObjectID `bson:"repository_id" json:"repository_id"`
-	PipelineID    primitive.ObjectID `bson:"pipeline_id" json:"pipeline_id"`
-	Date          time.Time          `bson:"date" json:"date"`
-	TotalChanges  int                `bson:"total_changes" json:"total_changes"`
-	TotalLeadTime float64            `bson:"total_lead_time" json:"total_lead_time"`
+	ID                  primitive.ObjectID `bson:"_id,omitempty"`
+	RepositoryID        primitive.ObjectID `bson:"repository_id" json:"repository_id"`
+	PipelineID          primitive.ObjectID `bson:"pipeline_id" json:"pipeline_id"`
+	Date                time.Time          `bson:"date" json:"date"`
+	TotalChanges        int                `bson:"total_changes" json:"total_changes"`
+	TotalLeadTime       float64            `bson:"total_lead_time" json:"total_lead_time"`
+	TotalCommits        int                `bson:"total_commits" json:"total_commits"`                   // only counted if LeadTimePerCommit
+	TotalCommitLeadTime float64            `bson:"total_commit_lead_time" json:"total_commit_lead_time"` // only summed if LeadTimePerCommit
+	LeadTimes           []float64          `bson:"lead_times" json:"lead_times"`
+	CommitLeadTimes     []float64          `bson:"commit_lead_times,omitempty" json:"commit_lead_times,omitempty"` // only set if LeadTimePerCommit
 }
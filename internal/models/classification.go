@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Performance tiers of the DORA State of DevOps report, from best to worst.
+const (
+	TierElite   = "elite"
+	TierHigh    = "high"
+	TierMedium  = "medium"
+	TierLow     = "low"
+	TierUnknown = "unknown"
+)
+
+// TierBounds represents the bounds a metric has to reach for the elite, high and medium tier.
+// Any value beyond the medium bound is classified as low.
+type TierBounds struct {
+	Elite  float64 `bson:"elite" json:"elite"`
+	High   float64 `bson:"high" json:"high"`
+	Medium float64 `bson:"medium" json:"medium"`
+}
+
+// TierThresholds represents the bounds of all tiers for each metric.
+type TierThresholds struct {
+	DeploymentFrequency TierBounds `bson:"deployment_frequency" json:"deployment_frequency"`   // minimum deployments per day
+	LeadTimeForChanges  TierBounds `bson:"lead_time_for_changes" json:"lead_time_for_changes"` // maximum seconds
+	MeanTimeToRestore   TierBounds `bson:"mean_time_to_restore" json:"mean_time_to_restore"`   // maximum seconds
+	ChangeFailureRate   TierBounds `bson:"change_failure_rate" json:"change_failure_rate"`     // maximum ratio of incidents per deployment
+}
+
+// DefaultTierThresholds returns the bounds of the DORA State of DevOps report.
+func DefaultTierThresholds() TierThresholds {
+	return TierThresholds{
+		// on demand, between once per day and once per week, between once per week and once per month
+		DeploymentFrequency: TierBounds{Elite: 1, High: 1.0 / 7, Medium: 1.0 / 30},
+		// less than one hour, less than one week, less than six months
+		LeadTimeForChanges: TierBounds{Elite: 60 * 60, High: 7 * 24 * 60 * 60, Medium: 180 * 24 * 60 * 60},
+		// less than one hour, less than one day, less than one week
+		MeanTimeToRestore: TierBounds{Elite: 60 * 60, High: 24 * 60 * 60, Medium: 7 * 24 * 60 * 60},
+		// up to 15%, up to 30%, up to 45%
+		ChangeFailureRate: TierBounds{Elite: 0.15, High: 0.30, Medium: 0.45},
+	}
+}
+
+// MetricTier represents the value of a metric over a period and the tier it is classified as.
+type MetricTier struct {
+	Value float64 `bson:"value" json:"value"`
+	Tier  string  `bson:"tier" json:"tier"`
+}
+
+// ClassificationRequest represents a classification request body for a specific dataflow.
+type ClassificationRequest struct {
+	DataflowID primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
+	StartDate  time.Time          `bson:"start_date" json:"start_date"`
+	EndDate    time.Time          `bson:"end_date" json:"end_date"`
+	Thresholds *TierThresholds    `bson:"thresholds" json:"thresholds"` // DefaultTierThresholds for each bound not set
+	Timezone   string             `bson:"timezone" json:"timezone"`     // IANA timezone bucketing days, must be the timezone of the dataflow if set
+}
+
+// GeneralClassificationRequest represents a general classification request body.
+type GeneralClassificationRequest struct {
+	StartDate  time.Time       `bson:"start_date" json:"start_date"`
+	EndDate    time.Time       `bson:"end_date" json:"end_date"`
+	Thresholds *TierThresholds `bson:"thresholds" json:"thresholds"` // DefaultTierThresholds for each bound not set
+}
+
+// Classification represents the tier of each metric and the overall tier of a specific dataflow.
+type Classification struct {
+	DataflowID          primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
+	StartDate           time.Time          `bson:"start_date" json:"start_date"`
+	EndDate             time.Time          `bson:"end_date" json:"end_date"`
+	DeploymentFrequency MetricTier         `bson:"deployment_frequency" json:"deployment_frequency"`
+	LeadTimeForChanges  MetricTier         `bson:"lead_time_for_changes" json:"lead_time_for_changes"`
+	MeanTimeToRestore   MetricTier         `bson:"mean_time_to_restore" json:"mean_time_to_restore"`
+	ChangeFailureRate   MetricTier         `bson:"change_failure_rate" json:"change_failure_rate"`
+	Tier                string             `bson:"tier" json:"tier"`
+}
+
+// GeneralClassification represents the general tier of each metric and the overall tier.
+type GeneralClassification struct {
+	StartDate           time.Time  `bson:"start_date" json:"start_date"`
+	EndDate             time.Time  `bson:"end_date" json:"end_date"`
+	DeploymentFrequency MetricTier `bson:"deployment_frequency" json:"deployment_frequency"`
+	LeadTimeForChanges  MetricTier `bson:"lead_time_for_changes" json:"lead_time_for_changes"`
+	MeanTimeToRestore   MetricTier `bson:"mean_time_to_restore" json:"mean_time_to_restore"`
+	ChangeFailureRate   MetricTier `bson:"change_failure_rate" json:"change_failure_rate"`
+	Tier                string     `bson:"tier" json:"tier"`
+}
@@ -2,12 +2,63 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+const (
+	// DefaultBackfillDays is the number of days of history imported for a Dataflow without a backfill window.
+	DefaultBackfillDays = 30
+	// DefaultSyncInterval is the number of minutes between two syncs of a Dataflow without a sync interval.
+	DefaultSyncInterval = 60
+	// DefaultStep is the number of seconds between two samples of a Deployment query without a step.
+	DefaultStep = 60
+)
+
+// Strategies to find the start of a Change, which defines its lead time.
+const (
+	// LeadTimeCommits starts a Change at the oldest commit deployed for the first time.
+	LeadTimeCommits = "commits"
+	// LeadTimeMergeRequests starts a Change at the oldest commit of the merge requests it deploys.
+	LeadTimeMergeRequests = "merge_requests"
+)
+
+// Relations of a sample of a Deployment query to its threshold, under which the sample is an alert.
+const (
+	RelationGreater        = ">"
+	RelationGreaterOrEqual = ">="
+	RelationLess           = "<"
+	RelationLessOrEqual    = "<="
+	RelationEqual          = "=="
+	RelationNotEqual       = "!="
+)
+
+// LegacyRelations maps the relations Deployments were stored with before the operators to their operator.
+var LegacyRelations = map[string]string{
+	"gt": RelationGreater,
+	"ge": RelationGreaterOrEqual,
+	"lt": RelationLess,
+	"le": RelationLessOrEqual,
+	"eq": RelationEqual,
+	"ne": RelationNotEqual,
+}
+
+// DeploymentRelation returns the relation of a Deployment, resolving relations stored by earlier versions.
+func DeploymentRelation(relation string) string {
+	operator, ok := LegacyRelations[relation]
+	if ok {
+		return operator
+	}
+	return relation
+}
+
 // Dataflow represents a complete dataflow, from repository, to pipeline, to deployment
 type Dataflow struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	Repository Repository         `bson:"repository" json:"repository"`
-	Pipeline   Pipeline           `bson:"pipeline" json:"pipeline"`
-	Deployment Deployment         `bson:"deployment" json:"deployment"`
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Repository        Repository         `bson:"repository" json:"repository"`
+	Pipeline          Pipeline           `bson:"pipeline" json:"pipeline"`
+	Deployment        Deployment         `bson:"deployment" json:"deployment"`
+	BackfillDays      int                `bson:"backfill_days" json:"backfill_days"`               // days of history imported initially, DefaultBackfillDays if not set
+	SyncInterval      int                `bson:"sync_interval" json:"sync_interval"`               // minutes between two syncs, DefaultSyncInterval if not set
+	LeadTimeStrategy  string             `bson:"lead_time_strategy" json:"lead_time_strategy"`     // one of LeadTimeCommits or LeadTimeMergeRequests, LeadTimeCommits if not set
+	LeadTimePerCommit bool               `bson:"lead_time_per_commit" json:"lead_time_per_commit"` // records the lead time of every commit deployed, not only of each change
+	Timezone          string             `bson:"timezone" json:"timezone"`                         // IANA timezone bucketing days, UTC if not set
 }
 
 // Repository represents a repository used for version control
@@ -30,10 +81,15 @@ type Pipeline struct {
 
 // Deployment represents a running deployment
 type Deployment struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
-	IntegrationID primitive.ObjectID `bson:"integration_id,omitempty" json:"integration_id"`
-	Query         string             `bson:"query" json:"query"`
-	Step          int                `bson:"step" json:"step"` // step is the time between each query according to the Prometheus API
-	Relation      string             `bson:"relation" json:"relation"`
-	Threshold     float64            `bson:"threshold" json:"threshold"`
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	IntegrationID       primitive.ObjectID `bson:"integration_id,omitempty" json:"integration_id"`
+	Query               string             `bson:"query" json:"query"`
+	Step                int                `bson:"step" json:"step"`                                   // step is the time between each query according to the Prometheus API
+	Relation            string             `bson:"relation" json:"relation"`                           // one of the relations, every sample is an alert if not set
+	Threshold           float64            `bson:"threshold" json:"threshold"`                         // a sample is an alert if it is in relation to the threshold
+	Matchers            []string           `bson:"matchers" json:"matchers"`                           // label matchers of the alerts of alerting sources, e.g. alertname="TargetDown"
+	IncidentGap         int                `bson:"incident_gap" json:"incident_gap"`                   // seconds between two alerts splitting an incident, twice the step if not set
+	TailPadding         int                `bson:"tail_padding" json:"tail_padding"`                   // seconds an incident lasts after its last alert, half the step if not set
+	MinIncidentDuration int                `bson:"min_incident_duration" json:"min_incident_duration"` // seconds an incident lasts at least, shorter ones are dropped as flapping
+	MinAlerts           int                `bson:"min_alerts" json:"min_alerts"`                       // number of alerts an incident has at least, fewer are dropped as flapping
 }
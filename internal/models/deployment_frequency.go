@@ -12,6 +12,8 @@ type DeploymentFrequency struct {
 	StartDate         time.Time          `bson:"start_date" json:"start_date"`
 	EndDate           time.Time          `bson:"end_date" json:"end_date"`
 	Window            int                `bson:"window" json:"window"`
+	Granularity       string             `bson:"granularity" json:"granularity"`
+	Timezone          string             `bson:"timezone" json:"timezone"`
 	Dates             []time.Time        `bson:"date" json:"date"`
 	DailyPipelineRuns []int              `bson:"daily_pipeline_runs" json:"daily_pipeline_runs"`
 	MovingAverages    []float64          `bson:"moving_averages" json:"moving_averages"`
@@ -22,6 +24,8 @@ type GeneralDeploymentFrequency struct {
 	StartDate         time.Time   `bson:"start_date" json:"start_date"`
 	EndDate           time.Time   `bson:"end_date" json:"end_date"`
 	Window            int         `bson:"window" json:"window"`
+	Granularity       string      `bson:"granularity" json:"granularity"`
+	Timezone          string      `bson:"timezone" json:"timezone"`
 	Dates             []time.Time `bson:"date" json:"date"`
 	DailyPipelineRuns []int       `bson:"daily_pipeline_runs" json:"daily_pipeline_runs"`
 	MovingAverages    []float64   `bson:"moving_averages" json:"moving_averages"`
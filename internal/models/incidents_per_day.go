@@ -13,4 +13,5 @@ type IncidentsPerDay struct {
 	Date           time.Time          `bson:"date" json:"date"`
 	TotalIncidents int                `bson:"total_incidents" json:"total_incidents"`
 	TotalDuration  float64            `bson:"total_duration" json:"total_duration"`
+	Durations      []float64          `bson:"durations" json:"durations"`
 }
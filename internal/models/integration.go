@@ -2,10 +2,28 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// Types of an Integration, defining which kind of data it provides.
+const (
+	VersionControl        = "vc"
+	ContinuousIntegration = "cicd"
+	IncidentManagement    = "im"
+
+	// LegacySourceControl is the type Integrations providing version control were stored with before VersionControl.
+	LegacySourceControl = "sc"
+)
+
+// IntegrationType returns the type of an Integration, resolving types stored by earlier versions.
+func IntegrationType(integrationType string) string {
+	if integrationType == LegacySourceControl {
+		return VersionControl
+	}
+	return integrationType
+}
+
 // Integration represents an third-party integration
 type Integration struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Type        string             `bson:"type" json:"type"` // TODO change this to an enum
+	Type        string             `bson:"type" json:"type"` // one of VersionControl, ContinuousIntegration or IncidentManagement
 	Provider    string             `bson:"provider" json:"provider"`
 	URI         string             `bson:"uri" json:"uri"`
 	BearerToken string             `bson:"bearer_token" json:"bearer_token"`
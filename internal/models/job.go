@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Statuses of a Job.
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Phases of a Job, in the order they run.
+const (
+	JobPhaseRaw       = "raw"
+	JobPhaseAdvanced  = "advanced"
+	JobPhaseAggregate = "aggregate"
+)
+
+// Job represents a sync of a Dataflow running in the background.
+type Job struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	DataflowID primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
+	Status     string             `bson:"status" json:"status"` // pending, running, succeeded or failed
+	Phase      string             `bson:"phase" json:"phase"`   // raw, advanced or aggregate
+	Progress   JobProgress        `bson:"progress" json:"progress"`
+	Error      string             `bson:"error" json:"error"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt  time.Time          `bson:"started_at" json:"started_at"`
+	FinishedAt time.Time          `bson:"finished_at" json:"finished_at"`
+}
+
+// JobProgress counts the records processed by a Job so far.
+type JobProgress struct {
+	Commits      int `bson:"commits" json:"commits"`
+	PipelineRuns int `bson:"pipeline_runs" json:"pipeline_runs"`
+	Changes      int `bson:"changes" json:"changes"`
+	Alerts       int `bson:"alerts" json:"alerts"`
+	PullRequests int `bson:"pull_requests" json:"pull_requests"`
+}
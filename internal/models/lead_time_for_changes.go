@@ -8,23 +8,32 @@ import (
 
 // LeadTimeForChanges represents the time of a change between first commit and deployment for a specific dataflow.
 type LeadTimeForChanges struct {
-	DataflowID     primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
-	StartDate      time.Time          `bson:"start_date" json:"start_date"`
-	EndDate        time.Time          `bson:"end_date" json:"end_date"`
-	Window         int                `bson:"window" json:"window"`
-	Dates          []time.Time        `bson:"date" json:"date"`
-	DailyChanges   []int              `bson:"daily_changes" json:"daily_changes"`
-	DailyLeadTimes []int              `bson:"daily_lead_times" json:"daily_lead_times"`
-	MovingAverages []float64          `bson:"moving_averages" json:"moving_averages"`
+	DataflowID        primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
+	StartDate         time.Time          `bson:"start_date" json:"start_date"`
+	EndDate           time.Time          `bson:"end_date" json:"end_date"`
+	Window            int                `bson:"window" json:"window"`
+	Granularity       string             `bson:"granularity" json:"granularity"`
+	Timezone          string             `bson:"timezone" json:"timezone"`
+	Dates             []time.Time        `bson:"date" json:"date"`
+	DailyChanges      []int              `bson:"daily_changes" json:"daily_changes"`
+	DailyCommits      []int              `bson:"daily_commits,omitempty" json:"daily_commits,omitempty"` // only set if LeadTimePerCommit
+	DailyLeadTimes    []int              `bson:"daily_lead_times" json:"daily_lead_times"`
+	MovingAverages    []float64          `bson:"moving_averages" json:"moving_averages"`
+	Statistic         string             `bson:"statistic" json:"statistic"`
+	MovingPercentiles []float64          `bson:"moving_percentiles,omitempty" json:"moving_percentiles,omitempty"` // only set if Statistic is a percentile
 }
 
 // GeneralLeadTimeForChanges represents the general time of a change between first commit and deployment.
 type GeneralLeadTimeForChanges struct {
-	StartDate      time.Time   `bson:"start_date" json:"start_date"`
-	EndDate        time.Time   `bson:"end_date" json:"end_date"`
-	Window         int         `bson:"window" json:"window"`
-	Dates          []time.Time `bson:"date" json:"date"`
-	DailyChanges   []int       `bson:"daily_changes" json:"daily_changes"`
-	DailyLeadTimes []int       `bson:"daily_lead_times" json:"daily_lead_times"`
-	MovingAverages []float64   `bson:"moving_averages" json:"moving_averages"`
+	StartDate         time.Time   `bson:"start_date" json:"start_date"`
+	EndDate           time.Time   `bson:"end_date" json:"end_date"`
+	Window            int         `bson:"window" json:"window"`
+	Granularity       string      `bson:"granularity" json:"granularity"`
+	Timezone          string      `bson:"timezone" json:"timezone"`
+	Dates             []time.Time `bson:"date" json:"date"`
+	DailyChanges      []int       `bson:"daily_changes" json:"daily_changes"`
+	DailyLeadTimes    []int       `bson:"daily_lead_times" json:"daily_lead_times"`
+	MovingAverages    []float64   `bson:"moving_averages" json:"moving_averages"`
+	Statistic         string      `bson:"statistic" json:"statistic"`
+	MovingPercentiles []float64   `bson:"moving_percentiles,omitempty" json:"moving_percentiles,omitempty"` // only set if Statistic is a percentile
 }
@@ -8,23 +8,31 @@ import (
 
 // MeanTimeToRestore represents the mean time to restore after an incident for a specific dataflow.
 type MeanTimeToRestore struct {
-	DataflowID     primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
-	StartDate      time.Time          `bson:"start_date" json:"start_date"`
-	EndDate        time.Time          `bson:"end_date" json:"end_date"`
-	Window         int                `bson:"window" json:"window"`
-	Dates          []time.Time        `bson:"date" json:"date"`
-	DailyIncidents []int              `bson:"daily_incidents" json:"daily_incidents"`
-	DailyDurations []int              `bson:"daily_durations" json:"daily_durations"`
-	MovingAverages []float64          `bson:"moving_averages" json:"moving_averages"`
+	DataflowID        primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
+	StartDate         time.Time          `bson:"start_date" json:"start_date"`
+	EndDate           time.Time          `bson:"end_date" json:"end_date"`
+	Window            int                `bson:"window" json:"window"`
+	Granularity       string             `bson:"granularity" json:"granularity"`
+	Timezone          string             `bson:"timezone" json:"timezone"`
+	Dates             []time.Time        `bson:"date" json:"date"`
+	DailyIncidents    []int              `bson:"daily_incidents" json:"daily_incidents"`
+	DailyDurations    []int              `bson:"daily_durations" json:"daily_durations"`
+	MovingAverages    []float64          `bson:"moving_averages" json:"moving_averages"`
+	Statistic         string             `bson:"statistic" json:"statistic"`
+	MovingPercentiles []float64          `bson:"moving_percentiles,omitempty" json:"moving_percentiles,omitempty"` // only set if Statistic is a percentile
 }
 
 // GeneralMeanTimeToRestore represents the general mean time to restore after an incident.
 type GeneralMeanTimeToRestore struct {
-	StartDate      time.Time   `bson:"start_date" json:"start_date"`
-	EndDate        time.Time   `bson:"end_date" json:"end_date"`
-	Window         int         `bson:"window" json:"window"`
-	Dates          []time.Time `bson:"date" json:"date"`
-	DailyIncidents []int       `bson:"daily_incidents" json:"daily_incidents"`
-	DailyDurations []int       `bson:"daily_durations" json:"daily_durations"`
-	MovingAverages []float64   `bson:"moving_averages" json:"moving_averages"`
+	StartDate         time.Time   `bson:"start_date" json:"start_date"`
+	EndDate           time.Time   `bson:"end_date" json:"end_date"`
+	Window            int         `bson:"window" json:"window"`
+	Granularity       string      `bson:"granularity" json:"granularity"`
+	Timezone          string      `bson:"timezone" json:"timezone"`
+	Dates             []time.Time `bson:"date" json:"date"`
+	DailyIncidents    []int       `bson:"daily_incidents" json:"daily_incidents"`
+	DailyDurations    []int       `bson:"daily_durations" json:"daily_durations"`
+	MovingAverages    []float64   `bson:"moving_averages" json:"moving_averages"`
+	Statistic         string      `bson:"statistic" json:"statistic"`
+	MovingPercentiles []float64   `bson:"moving_percentiles,omitempty" json:"moving_percentiles,omitempty"` // only set if Statistic is a percentile
 }
@@ -6,17 +6,46 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Statistics which metrics can be calculated with besides their moving averages.
+const (
+	StatisticMean = "mean"
+	StatisticP50  = "p50"
+	StatisticP75  = "p75"
+	StatisticP90  = "p90"
+	StatisticP95  = "p95"
+)
+
+// Percentiles maps each percentile statistic to its percentile.
+var Percentiles = map[string]float64{
+	StatisticP50: 50,
+	StatisticP75: 75,
+	StatisticP90: 90,
+	StatisticP95: 95,
+}
+
+// Granularities which metrics can be bucketed by.
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week" // ISO weeks, starting on Monday
+	GranularityMonth = "month"
+)
+
 // MetricsRequest represents a generic metrics request body for a specific dataflow.
 type MetricsRequest struct {
-	DataflowID primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
-	StartDate  time.Time          `bson:"start_date" json:"start_date"`
-	EndDate    time.Time          `bson:"end_date" json:"end_date"`
-	Window     int                `bson:"window" json:"window"`
+	DataflowID  primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
+	StartDate   time.Time          `bson:"start_date" json:"start_date"`
+	EndDate     time.Time          `bson:"end_date" json:"end_date"`
+	Window      int                `bson:"window" json:"window"`
+	Statistic   string             `bson:"statistic" json:"statistic"`     // one of the statistics, StatisticMean if not set
+	Granularity string             `bson:"granularity" json:"granularity"` // one of the granularities, GranularityDay if not set
+	Timezone    string             `bson:"timezone" json:"timezone"`       // IANA timezone bucketing days, must be the timezone of the dataflow if set
 }
 
 // GeneralMetricsRequest represents a general generic metrics request body.
 type GeneralMetricsRequest struct {
-	StartDate time.Time `bson:"start_date" json:"start_date"`
-	EndDate   time.Time `bson:"end_date" json:"end_date"`
-	Window    int       `bson:"window" json:"window"`
+	StartDate   time.Time `bson:"start_date" json:"start_date"`
+	EndDate     time.Time `bson:"end_date" json:"end_date"`
+	Window      int       `bson:"window" json:"window"`
+	Statistic   string    `bson:"statistic" json:"statistic"`     // one of the statistics, StatisticMean if not set
+	Granularity string    `bson:"granularity" json:"granularity"` // one of the granularities, GranularityDay if not set
 }
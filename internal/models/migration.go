@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Migration records a version of the database schema which has been applied.
+type Migration struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Version     int                `bson:"version" json:"version"`
+	Description string             `bson:"description" json:"description"`
+	AppliedAt   time.Time          `bson:"applied_at" json:"applied_at"`
+}
@@ -1,18 +1,32 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // PullRequest describes a successfully merged pull-request
 type PullRequest struct {
-	ID               int       `json:"id"`
-	ProjectID        int       `json:"project_id"`
-	Title            string    `json:"title"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
-	TargetBranch     string    `json:"target_branch"`
-	SourceBranch     string    `json:"source_branch"`
-	PreCommitTailSha string    `json:"sha"`
-	MergeCommitSha   string    `json:"merge_commit_sha"` // could also be a squashed commit
-	Reference        string    `json:"reference"`
-	WebURL           string    `json:"web_url"`
+	ID               int                `json:"id" bson:"external_id"`
+	IID              int                `json:"iid" bson:"iid"` // number of the pull-request within its repository
+	RepositoryID     primitive.ObjectID `json:"repository_id" bson:"repository_id"`
+	ProjectID        int                `json:"project_id" bson:"project_id"`
+	Title            string             `json:"title" bson:"title"`
+	CreatedAt        time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at" bson:"updated_at"`
+	TargetBranch     string             `json:"target_branch" bson:"target_branch"`
+	SourceBranch     string             `json:"source_branch" bson:"source_branch"`
+	PreCommitTailSha string             `json:"sha" bson:"sha"`
+	MergeCommitSha   string             `json:"merge_commit_sha" bson:"merge_commit_sha"` // could also be a squashed commit
+	Reference        string             `json:"reference" bson:"reference"`
+	WebURL           string             `json:"web_url" bson:"web_url"`
+	FirstCommitDate  time.Time          `json:"first_commit_date" bson:"first_commit_date"` // creation of the oldest commit of the pull-request
+}
+
+// MergeRequestReference refers to a merged pull-request deployed by a Change.
+type MergeRequestReference struct {
+	IID       int    `json:"iid" bson:"iid"`
+	Reference string `json:"reference" bson:"reference"`
+	WebURL    string `json:"web_url" bson:"web_url"`
 }
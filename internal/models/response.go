@@ -4,3 +4,9 @@ package models
 type IDResponse struct {
 	ID string `json:"id" uri:"id"`
 }
+
+// DataflowJobResponse defines the response to a new Dataflow, which is onboarded by a Job in the background
+type DataflowJobResponse struct {
+	Dataflow
+	JobID string `json:"job_id"`
+}
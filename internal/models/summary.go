@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SummaryRequest represents a summary request body for a specific dataflow, or for all dataflows.
+type SummaryRequest struct {
+	DataflowID  primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"` // all dataflows if not set
+	StartDate   time.Time          `bson:"start_date" json:"start_date"`
+	EndDate     time.Time          `bson:"end_date" json:"end_date"`
+	Window      int                `bson:"window" json:"window"`
+	Statistic   string             `bson:"statistic" json:"statistic"`     // one of the statistics, StatisticMean if not set
+	Granularity string             `bson:"granularity" json:"granularity"` // one of the granularities, GranularityDay if not set
+	Timezone    string             `bson:"timezone" json:"timezone"`       // IANA timezone bucketing days, must be the timezone of the dataflow, or UTC for all dataflows, if set
+	Compare     bool               `bson:"compare" json:"compare"`         // compares each metric with the preceding period of equal length
+}
+
+// Trends of a metric within a period.
+const (
+	TrendImproving = "improving"
+	TrendStable    = "stable"
+	TrendDegrading = "degrading"
+)
+
+// Comparison represents how a metric changed compared to the preceding period of equal length.
+type Comparison struct {
+	PreviousStartDate time.Time `bson:"previous_start_date" json:"previous_start_date"`
+	PreviousEndDate   time.Time `bson:"previous_end_date" json:"previous_end_date"`
+	PreviousAverage   float64   `bson:"previous_average" json:"previous_average"`
+	Delta             float64   `bson:"delta" json:"delta"`
+	RelativeDelta     float64   `bson:"relative_delta" json:"relative_delta"` // 0 if the previous average is 0
+	Slope             float64   `bson:"slope" json:"slope"`                   // change per bucket of the series
+	Trend             string    `bson:"trend" json:"trend"`
+}
+
+// Summary represents all four metrics of a specific dataflow, or of all dataflows, over the same period.
+type Summary struct {
+	DataflowID          primitive.ObjectID         `bson:"dataflow_id,omitempty" json:"dataflow_id,omitempty"`
+	StartDate           time.Time                  `bson:"start_date" json:"start_date"`
+	EndDate             time.Time                  `bson:"end_date" json:"end_date"`
+	Window              int                        `bson:"window" json:"window"`
+	Statistic           string                     `bson:"statistic" json:"statistic"`
+	Granularity         string                     `bson:"granularity" json:"granularity"`
+	Timezone            string                     `bson:"timezone" json:"timezone"`
+	Dates               []time.Time                `bson:"date" json:"date"`
+	DeploymentFrequency DeploymentFrequencySummary `bson:"deployment_frequency" json:"deployment_frequency"`
+	LeadTimeForChanges  LeadTimeForChangesSummary  `bson:"lead_time_for_changes" json:"lead_time_for_changes"`
+	MeanTimeToRestore   MeanTimeToRestoreSummary   `bson:"mean_time_to_restore" json:"mean_time_to_restore"`
+	ChangeFailureRate   ChangeFailureRateSummary   `bson:"change_failure_rate" json:"change_failure_rate"`
+}
+
+// DeploymentFrequencySummary represents the deployment frequency within a Summary.
+type DeploymentFrequencySummary struct {
+	TotalPipelineRuns int         `bson:"total_pipeline_runs" json:"total_pipeline_runs"`
+	Average           float64     `bson:"average" json:"average"` // pipeline runs per bucket
+	DailyPipelineRuns []int       `bson:"daily_pipeline_runs" json:"daily_pipeline_runs"`
+	MovingAverages    []float64   `bson:"moving_averages" json:"moving_averages"`
+	Comparison        *Comparison `bson:"comparison,omitempty" json:"comparison,omitempty"` // only set if compared
+}
+
+// LeadTimeForChangesSummary represents the lead time for changes within a Summary.
+type LeadTimeForChangesSummary struct {
+	TotalChanges      int         `bson:"total_changes" json:"total_changes"`
+	TotalCommits      int         `bson:"total_commits,omitempty" json:"total_commits,omitempty"` // only set if LeadTimePerCommit
+	TotalLeadTime     int         `bson:"total_lead_time" json:"total_lead_time"`
+	Average           float64     `bson:"average" json:"average"` // seconds per change, or per commit if LeadTimePerCommit
+	DailyChanges      []int       `bson:"daily_changes" json:"daily_changes"`
+	DailyCommits      []int       `bson:"daily_commits,omitempty" json:"daily_commits,omitempty"` // only set if LeadTimePerCommit
+	DailyLeadTimes    []int       `bson:"daily_lead_times" json:"daily_lead_times"`
+	MovingAverages    []float64   `bson:"moving_averages" json:"moving_averages"`
+	MovingPercentiles []float64   `bson:"moving_percentiles,omitempty" json:"moving_percentiles,omitempty"` // only set if Statistic is a percentile
+	Comparison        *Comparison `bson:"comparison,omitempty" json:"comparison,omitempty"`                 // only set if compared
+}
+
+// MeanTimeToRestoreSummary represents the mean time to restore within a Summary.
+type MeanTimeToRestoreSummary struct {
+	TotalIncidents    int         `bson:"total_incidents" json:"total_incidents"`
+	TotalDuration     int         `bson:"total_duration" json:"total_duration"`
+	Average           float64     `bson:"average" json:"average"` // seconds per incident
+	DailyIncidents    []int       `bson:"daily_incidents" json:"daily_incidents"`
+	DailyDurations    []int       `bson:"daily_durations" json:"daily_durations"`
+	MovingAverages    []float64   `bson:"moving_averages" json:"moving_averages"`
+	MovingPercentiles []float64   `bson:"moving_percentiles,omitempty" json:"moving_percentiles,omitempty"` // only set if Statistic is a percentile
+	Comparison        *Comparison `bson:"comparison,omitempty" json:"comparison,omitempty"`                 // only set if compared
+}
+
+// ChangeFailureRateSummary represents the change failure rate within a Summary.
+type ChangeFailureRateSummary struct {
+	TotalIncidents   int         `bson:"total_incidents" json:"total_incidents"`
+	TotalDeployments int         `bson:"total_deployments" json:"total_deployments"`
+	Average          float64     `bson:"average" json:"average"` // incidents per deployment
+	DailyIncidents   []int       `bson:"daily_incidents" json:"daily_incidents"`
+	DailyDeployments []int       `bson:"daily_deployments" json:"daily_deployments"`
+	MovingAverages   []float64   `bson:"moving_averages" json:"moving_averages"`
+	Comparison       *Comparison `bson:"comparison,omitempty" json:"comparison,omitempty"` // only set if compared
+}
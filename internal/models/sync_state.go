@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SyncState holds the high-water marks of each source of a Dataflow, up to which data has been synced.
+type SyncState struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	DataflowID          primitive.ObjectID `bson:"dataflow_id" json:"dataflow_id"`
+	LastCommitDate      time.Time          `bson:"last_commit_date" json:"last_commit_date"`
+	LastPipelineRunDate time.Time          `bson:"last_pipeline_run_date" json:"last_pipeline_run_date"` // updated_at of the last pipeline run
+	LastAlertDate       time.Time          `bson:"last_alert_date" json:"last_alert_date"`
+	LastPullRequestDate time.Time          `bson:"last_pull_request_date" json:"last_pull_request_date"` // updated_at of the last pull-request, only synced for LeadTimeMergeRequests
+	SyncedAt            time.Time          `bson:"synced_at" json:"synced_at"`                           // time of the last successful run
+	LastRunAt           time.Time          `bson:"last_run_at" json:"last_run_at"`                       // time of the last run, successful or not
+	LastError           string             `bson:"last_error" json:"last_error"`                         // error of the last run, empty if successful
+}
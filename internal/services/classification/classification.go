@@ -0,0 +1,190 @@
+package classification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/metrics"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tiers are ordered from best to worst.
+var tiers = []string{models.TierElite, models.TierHigh, models.TierMedium, models.TierLow}
+
+// Classify classifies the metrics of a specific dataflow over a period into the DORA performance tiers.
+// Days are bucketed in the timezone of the dataflow, hence a different timezone given is an error.
+func Classify(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, thresholds models.TierThresholds, timezone string) (*models.Classification, error) {
+	err := ValidateThresholds(thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := metrics.Summary(ctx, store, dataflowID, startDate, endDate, 1, models.StatisticMean, models.GranularityDay, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error summarizing metrics: %w", err)
+	}
+
+	classification := models.Classification{
+		DataflowID:          dataflowID,
+		StartDate:           startDate,
+		EndDate:             endDate,
+		DeploymentFrequency: classifyDeploymentFrequency(summary, thresholds),
+		LeadTimeForChanges:  classifyLeadTimeForChanges(summary, thresholds),
+		MeanTimeToRestore:   ClassifyRatio(summary.MeanTimeToRestore.TotalDuration, summary.MeanTimeToRestore.TotalIncidents, thresholds.MeanTimeToRestore, false),
+		ChangeFailureRate:   ClassifyRatio(summary.ChangeFailureRate.TotalIncidents, summary.ChangeFailureRate.TotalDeployments, thresholds.ChangeFailureRate, false),
+	}
+	classification.Tier = OverallTier(
+		classification.DeploymentFrequency.Tier,
+		classification.LeadTimeForChanges.Tier,
+		classification.MeanTimeToRestore.Tier,
+		classification.ChangeFailureRate.Tier,
+	)
+
+	return &classification, nil
+}
+
+// GeneralClassify classifies the general metrics over all dataflows over a period into the DORA performance tiers.
+func GeneralClassify(ctx context.Context, store *daos.Store, startDate time.Time, endDate time.Time, thresholds models.TierThresholds) (*models.GeneralClassification, error) {
+	err := ValidateThresholds(thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := metrics.Summary(ctx, store, primitive.NilObjectID, startDate, endDate, 1, models.StatisticMean, models.GranularityDay, "")
+	if err != nil {
+		return nil, fmt.Errorf("error summarizing metrics: %w", err)
+	}
+
+	classification := models.GeneralClassification{
+		StartDate:           startDate,
+		EndDate:             endDate,
+		DeploymentFrequency: classifyDeploymentFrequency(summary, thresholds),
+		LeadTimeForChanges:  classifyLeadTimeForChanges(summary, thresholds),
+		MeanTimeToRestore:   ClassifyRatio(summary.MeanTimeToRestore.TotalDuration, summary.MeanTimeToRestore.TotalIncidents, thresholds.MeanTimeToRestore, false),
+		ChangeFailureRate:   ClassifyRatio(summary.ChangeFailureRate.TotalIncidents, summary.ChangeFailureRate.TotalDeployments, thresholds.ChangeFailureRate, false),
+	}
+	classification.Tier = OverallTier(
+		classification.DeploymentFrequency.Tier,
+		classification.LeadTimeForChanges.Tier,
+		classification.MeanTimeToRestore.Tier,
+		classification.ChangeFailureRate.Tier,
+	)
+
+	return &classification, nil
+}
+
+// ClassifyRatio classifies the ratio of two totals, e.g. the lead time per change.
+// Without a denominator, e.g. without any changes, the tier is unknown.
+func ClassifyRatio(numerator int, denominator int, bounds models.TierBounds, higherIsBetter bool) models.MetricTier {
+	if denominator == 0 {
+		return models.MetricTier{Tier: models.TierUnknown}
+	}
+
+	value := float64(numerator) / float64(denominator)
+	return models.MetricTier{
+		Value: value,
+		Tier:  Tier(value, bounds, higherIsBetter),
+	}
+}
+
+// Tier classifies a value into a tier, given the bounds of each tier.
+// Values equal to a bound belong to the better tier.
+func Tier(value float64, bounds models.TierBounds, higherIsBetter bool) string {
+	for index, bound := range []float64{bounds.Elite, bounds.High, bounds.Medium} {
+		if (higherIsBetter && value >= bound) || (!higherIsBetter && value <= bound) {
+			return tiers[index]
+		}
+	}
+	return models.TierLow
+}
+
+// OverallTier returns the worst of all known tiers, since a team only performs as well as its weakest metric.
+// If all tiers are unknown, the overall tier is unknown too.
+func OverallTier(metricTiers ...string) string {
+	overall := -1
+	for _, metricTier := range metricTiers {
+		for index, tier := range tiers {
+			if tier == metricTier && index > overall {
+				overall = index
+			}
+		}
+	}
+
+	if overall < 0 {
+		return models.TierUnknown
+	}
+	return tiers[overall]
+}
+
+// WithDefaults returns the thresholds given, where every bound not set takes the bound of DefaultTierThresholds,
+// so that the thresholds of a request only need to set the bounds differing from the defaults.
+func WithDefaults(thresholds models.TierThresholds) models.TierThresholds {
+	defaults := models.DefaultTierThresholds()
+	return models.TierThresholds{
+		DeploymentFrequency: boundsWithDefaults(thresholds.DeploymentFrequency, defaults.DeploymentFrequency),
+		LeadTimeForChanges:  boundsWithDefaults(thresholds.LeadTimeForChanges, defaults.LeadTimeForChanges),
+		MeanTimeToRestore:   boundsWithDefaults(thresholds.MeanTimeToRestore, defaults.MeanTimeToRestore),
+		ChangeFailureRate:   boundsWithDefaults(thresholds.ChangeFailureRate, defaults.ChangeFailureRate),
+	}
+}
+
+// boundsWithDefaults replaces each bound left at zero by its default.
+func boundsWithDefaults(bounds models.TierBounds, defaults models.TierBounds) models.TierBounds {
+	if bounds.Elite == 0 {
+		bounds.Elite = defaults.Elite
+	}
+	if bounds.High == 0 {
+		bounds.High = defaults.High
+	}
+	if bounds.Medium == 0 {
+		bounds.Medium = defaults.Medium
+	}
+	return bounds
+}
+
+// ValidateThresholds validates that the bounds of each metric get stricter from the medium to the elite tier.
+func ValidateThresholds(thresholds models.TierThresholds) error {
+	if !ordered(thresholds.DeploymentFrequency, true) {
+		return fmt.Errorf("deployment frequency bounds must decrease from elite to medium")
+	}
+
+	if !ordered(thresholds.LeadTimeForChanges, false) {
+		return fmt.Errorf("lead time for changes bounds must increase from elite to medium")
+	}
+
+	if !ordered(thresholds.MeanTimeToRestore, false) {
+		return fmt.Errorf("mean time to restore bounds must increase from elite to medium")
+	}
+
+	if !ordered(thresholds.ChangeFailureRate, false) {
+		return fmt.Errorf("change failure rate bounds must increase from elite to medium")
+	}
+
+	return nil
+}
+
+// ordered returns true if the bounds get stricter from the medium to the elite tier.
+func ordered(bounds models.TierBounds, higherIsBetter bool) bool {
+	if higherIsBetter {
+		return bounds.Elite >= bounds.High && bounds.High >= bounds.Medium
+	}
+	return bounds.Elite <= bounds.High && bounds.High <= bounds.Medium
+}
+
+// classifyDeploymentFrequency classifies the pipeline runs per day of a Summary.
+func classifyDeploymentFrequency(summary *models.Summary, thresholds models.TierThresholds) models.MetricTier {
+	return ClassifyRatio(summary.DeploymentFrequency.TotalPipelineRuns, len(summary.Dates), thresholds.DeploymentFrequency, true)
+}
+
+// classifyLeadTimeForChanges classifies the lead time per change of a Summary,
+// or per commit if the lead time of every commit is recorded.
+func classifyLeadTimeForChanges(summary *models.Summary, thresholds models.TierThresholds) models.MetricTier {
+	leadTimeForChanges := summary.LeadTimeForChanges
+	if leadTimeForChanges.DailyCommits != nil {
+		return ClassifyRatio(leadTimeForChanges.TotalLeadTime, leadTimeForChanges.TotalCommits, thresholds.LeadTimeForChanges, false)
+	}
+	return ClassifyRatio(leadTimeForChanges.TotalLeadTime, leadTimeForChanges.TotalChanges, thresholds.LeadTimeForChanges, false)
+}
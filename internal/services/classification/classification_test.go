@@ -0,0 +1,90 @@
+package classification_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/classification"
+)
+
+var _ = Describe("services.classification", func() {
+	thresholds := models.DefaultTierThresholds()
+
+	var _ = When("Tier", func() {
+		It("classifies a deployment frequency, where higher is better.", func() {
+			Expect(classification.Tier(3, thresholds.DeploymentFrequency, true)).To(Equal(models.TierElite))
+			Expect(classification.Tier(0.5, thresholds.DeploymentFrequency, true)).To(Equal(models.TierHigh))
+			Expect(classification.Tier(0.1, thresholds.DeploymentFrequency, true)).To(Equal(models.TierMedium))
+			Expect(classification.Tier(0, thresholds.DeploymentFrequency, true)).To(Equal(models.TierLow))
+		})
+
+		It("classifies a lead time, where lower is better.", func() {
+			Expect(classification.Tier(1800, thresholds.LeadTimeForChanges, false)).To(Equal(models.TierElite))
+			Expect(classification.Tier(3600, thresholds.LeadTimeForChanges, false)).To(Equal(models.TierElite))
+			Expect(classification.Tier(86400, thresholds.LeadTimeForChanges, false)).To(Equal(models.TierHigh))
+			Expect(classification.Tier(365*86400, thresholds.LeadTimeForChanges, false)).To(Equal(models.TierLow))
+		})
+	})
+
+	var _ = When("ClassifyRatio", func() {
+		It("classifies the ratio of two totals.", func() {
+			metricTier := classification.ClassifyRatio(3, 10, thresholds.ChangeFailureRate, false)
+			Expect(metricTier.Value).To(Equal(0.3))
+			Expect(metricTier.Tier).To(Equal(models.TierHigh))
+		})
+
+		It("does not classify a ratio without a denominator.", func() {
+			metricTier := classification.ClassifyRatio(0, 0, thresholds.MeanTimeToRestore, false)
+			Expect(metricTier.Tier).To(Equal(models.TierUnknown))
+		})
+	})
+
+	var _ = When("OverallTier", func() {
+		It("returns the worst known tier.", func() {
+			tier := classification.OverallTier(models.TierElite, models.TierMedium, models.TierUnknown, models.TierHigh)
+			Expect(tier).To(Equal(models.TierMedium))
+		})
+
+		It("returns unknown if no tier is known.", func() {
+			tier := classification.OverallTier(models.TierUnknown, models.TierUnknown)
+			Expect(tier).To(Equal(models.TierUnknown))
+		})
+	})
+
+	var _ = When("ValidateThresholds", func() {
+		It("accepts the default thresholds.", func() {
+			Expect(classification.ValidateThresholds(thresholds)).To(BeNil())
+		})
+
+		It("rejects bounds which are not ordered.", func() {
+			invalid := models.DefaultTierThresholds()
+			invalid.MeanTimeToRestore = models.TierBounds{Elite: 86400, High: 3600, Medium: 604800}
+			Expect(classification.ValidateThresholds(invalid)).ToNot(BeNil())
+		})
+	})
+
+	var _ = When("WithDefaults", func() {
+		It("takes the default of each bound not set.", func() {
+			partial := models.TierThresholds{
+				LeadTimeForChanges: models.TierBounds{Elite: 1800},
+			}
+
+			merged := classification.WithDefaults(partial)
+			Expect(merged.DeploymentFrequency).To(Equal(thresholds.DeploymentFrequency))
+			Expect(merged.LeadTimeForChanges).To(Equal(models.TierBounds{Elite: 1800, High: thresholds.LeadTimeForChanges.High, Medium: thresholds.LeadTimeForChanges.Medium}))
+			Expect(merged.MeanTimeToRestore).To(Equal(thresholds.MeanTimeToRestore))
+			Expect(merged.ChangeFailureRate).To(Equal(thresholds.ChangeFailureRate))
+			Expect(classification.ValidateThresholds(merged)).To(BeNil())
+		})
+
+		It("does not classify the metrics not set as low.", func() {
+			merged := classification.WithDefaults(models.TierThresholds{
+				ChangeFailureRate: models.TierBounds{Elite: 0.1, High: 0.2, Medium: 0.3},
+			})
+
+			Expect(classification.ClassifyRatio(1800, 1, merged.MeanTimeToRestore, false).Tier).To(Equal(models.TierElite))
+			Expect(classification.ClassifyRatio(2, 1, merged.DeploymentFrequency, true).Tier).To(Equal(models.TierElite))
+		})
+	})
+})
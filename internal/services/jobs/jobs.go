@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/trigger"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// DefaultWorkers is the number of Jobs running in parallel.
+	DefaultWorkers = 4
+	// QueueSize is the number of Jobs waiting for a worker, before new Jobs are rejected.
+	QueueSize = 100
+)
+
+// ErrQueueFull is returned if a Job can not be queued, because all workers are busy and the queue is full.
+var ErrQueueFull = fmt.Errorf("job queue is full")
+
+// ErrInterrupted is recorded for a Job still running when the server stopped.
+var ErrInterrupted = fmt.Errorf("job was interrupted by a restart of the server")
+
+// task is a Job waiting for a worker.
+type task struct {
+	job      models.Job
+	dataflow models.Dataflow
+}
+
+// queue holds the Jobs waiting for a worker
+var queue = make(chan task, QueueSize)
+
+// Start starts a pool of workers running the queued Jobs, until the context is done.
+func Start(ctx context.Context, store *daos.Store, workers int) {
+	for i := 0; i < workers; i++ {
+		go work(ctx, store)
+	}
+}
+
+// Submit creates a pending Job syncing a Dataflow, which onboards a new Dataflow, and queues it for the workers.
+func Submit(ctx context.Context, store *daos.Store, dataflow *models.Dataflow) (*models.Job, error) {
+	job := models.Job{
+		DataflowID: dataflow.ID,
+		Status:     models.JobPending,
+		CreatedAt:  time.Now(),
+	}
+	err := store.CreateJob(ctx, &job)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case queue <- task{job: job, dataflow: *dataflow}:
+		return &job, nil
+	default:
+		err = fail(ctx, store, &job, ErrQueueFull)
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrQueueFull
+	}
+}
+
+// Resume queues the Jobs left pending when the server stopped, and fails the Jobs left running,
+// as they were interrupted. Jobs whose Dataflow can not be found anymore are failed as well.
+func Resume(ctx context.Context, store *daos.Store) error {
+	var pendingJobs []models.Job
+	err := store.ListJobsByFilter(ctx, bson.M{"status": models.JobPending}, &pendingJobs)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range pendingJobs {
+		var dataflow models.Dataflow
+		err := store.GetDataflow(ctx, job.DataflowID, &dataflow)
+		if err != nil {
+			err = fail(ctx, store, &job, err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		select {
+		case queue <- task{job: job, dataflow: dataflow}:
+		default:
+			err = fail(ctx, store, &job, ErrQueueFull)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var runningJobs []models.Job
+	err = store.ListJobsByFilter(ctx, bson.M{"status": models.JobRunning}, &runningJobs)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range runningJobs {
+		err = fail(ctx, store, &job, ErrInterrupted)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Resumed %d pending jobs and failed %d interrupted jobs", len(pendingJobs), len(runningJobs))
+
+	return nil
+}
+
+// fail records a Job as failed with an error.
+func fail(ctx context.Context, store *daos.Store, job *models.Job, err error) error {
+	job.Status = models.JobFailed
+	job.Error = err.Error()
+	job.FinishedAt = time.Now()
+	return store.UpdateJob(ctx, job.ID, job)
+}
+
+// work runs queued Jobs one after another, until the context is done.
+func work(ctx context.Context, store *daos.Store) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-queue:
+			err := Run(ctx, store, &task.job, &task.dataflow)
+			if err != nil {
+				log.Printf("Job %s failed: %s", task.job.ID.Hex(), err.Error())
+			}
+		}
+	}
+}
+
+// Run runs a Job syncing a Dataflow, and records its status, timestamps and error.
+// A Dataflow already being synced fails the Job with trigger.ErrSyncRunning.
+func Run(ctx context.Context, store *daos.Store, job *models.Job, dataflow *models.Dataflow) error {
+	job.Status = models.JobRunning
+	job.StartedAt = time.Now()
+	err := store.UpdateJob(ctx, job.ID, job)
+	if err != nil {
+		return err
+	}
+
+	err = trigger.OnNewDataflow(ctx, store, dataflow, job)
+	if err != nil {
+		job.Status = models.JobFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = models.JobSucceeded
+	}
+	job.FinishedAt = time.Now()
+
+	updateErr := store.UpdateJob(ctx, job.ID, job)
+	if err != nil {
+		return err
+	}
+	return updateErr
+}
@@ -0,0 +1,110 @@
+package jobs_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/jobs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestJobs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "services.jobs Suite")
+}
+
+var _ = Describe("services.jobs", func() {
+	ctx := context.Background()
+
+	var store *daos.Store
+
+	var _ = BeforeEach(func() {
+		_ = godotenv.Load("./../../../test/.env")
+
+		var err error
+		store, err = daos.NewStore(ctx, os.Getenv("MONGODB_DATABASE"))
+		Expect(err).To(BeNil())
+	})
+
+	var _ = AfterEach(func() {
+		store.Service.DB.Drop(ctx)
+		defer store.Close(ctx)
+
+		os.Remove("MONGODB_URI")
+		os.Remove("MONGODB_PORT")
+		os.Remove("MONGODB_USER")
+		os.Remove("MONGODB_PASSWORD")
+	})
+
+	var _ = When("Submit", func() {
+		It("creates a pending Job for a Dataflow.", func() {
+			dataflow := models.Dataflow{ID: primitive.NewObjectID()}
+
+			job, err := jobs.Submit(ctx, store, &dataflow)
+			Expect(err).To(BeNil())
+
+			var findJob models.Job
+			err = store.GetJob(ctx, job.ID, &findJob)
+			Expect(err).To(BeNil())
+			Expect(findJob.DataflowID).To(Equal(dataflow.ID))
+			Expect(findJob.Status).To(Equal(models.JobPending))
+		})
+	})
+
+	var _ = When("Run", func() {
+		It("records the phase and error of a failed Job.", func() {
+			// the integrations of this Dataflow do not exist, so the raw phase fails
+			dataflow := models.Dataflow{
+				ID:         primitive.NewObjectID(),
+				Repository: models.Repository{IntegrationID: primitive.NewObjectID()},
+				Pipeline:   models.Pipeline{IntegrationID: primitive.NewObjectID()},
+			}
+			job := models.Job{DataflowID: dataflow.ID, Status: models.JobPending}
+			err := store.CreateJob(ctx, &job)
+			Expect(err).To(BeNil())
+
+			err = jobs.Run(ctx, store, &job, &dataflow)
+			Expect(err).To(Not(BeNil()))
+
+			var findJob models.Job
+			err = store.GetJob(ctx, job.ID, &findJob)
+			Expect(err).To(BeNil())
+			Expect(findJob.Status).To(Equal(models.JobFailed))
+			Expect(findJob.Phase).To(Equal(models.JobPhaseRaw))
+			Expect(findJob.Error).To(Not(BeEmpty()))
+			Expect(findJob.FinishedAt.After(findJob.StartedAt)).To(BeTrue())
+		})
+	})
+
+	var _ = When("Resume", func() {
+		It("fails the Jobs interrupted by a restart and the Jobs without a Dataflow.", func() {
+			running := models.Job{DataflowID: primitive.NewObjectID(), Status: models.JobRunning}
+			err := store.CreateJob(ctx, &running)
+			Expect(err).To(BeNil())
+
+			// the Dataflow of this Job does not exist
+			pending := models.Job{DataflowID: primitive.NewObjectID(), Status: models.JobPending}
+			err = store.CreateJob(ctx, &pending)
+			Expect(err).To(BeNil())
+
+			err = jobs.Resume(ctx, store)
+			Expect(err).To(BeNil())
+
+			var findJob models.Job
+			err = store.GetJob(ctx, running.ID, &findJob)
+			Expect(err).To(BeNil())
+			Expect(findJob.Status).To(Equal(models.JobFailed))
+			Expect(findJob.Error).To(Equal(jobs.ErrInterrupted.Error()))
+
+			err = store.GetJob(ctx, pending.ID, &findJob)
+			Expect(err).To(BeNil())
+			Expect(findJob.Status).To(Equal(models.JobFailed))
+		})
+	})
+})
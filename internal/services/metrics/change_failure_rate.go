@@ -13,7 +13,7 @@ import (
 )
 
 // ChangeFailureRate calculates the change failure rate for a specific dataflow.
-func ChangeFailureRate(ctx context.Context, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int) (*models.ChangeFailureRate, error) {
+func ChangeFailureRate(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int, granularity string, timezone string) (*models.ChangeFailureRate, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -22,47 +22,48 @@ func ChangeFailureRate(ctx context.Context, dataflowID primitive.ObjectID, start
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	granularity, err := ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
 	var dataflow models.Dataflow
-	err := daos.GetDataflow(ctx, dataflowID, &dataflow)
+	err = store.GetDataflow(ctx, dataflowID, &dataflow)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := ParseTimezone(timezone, dataflow.Timezone)
 	if err != nil {
 		return nil, err
 	}
 
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var incidentsPerDays []models.IncidentsPerDay
 	filter := bson.M{"deployment_id": dataflow.Deployment.ID, "date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err = daos.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
+	err = store.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
 	if err != nil {
 		return nil, fmt.Errorf("error listing incidents per days: %w", err)
 	}
 
 	var pipelineRunsPerDays []models.PipelineRunsPerDay
 	filter = bson.M{"pipeline_id": dataflow.Pipeline.ID, "date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err = daos.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDays)
+	err = store.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDays)
 	if err != nil {
 		return nil, fmt.Errorf("error listing pipeline runs per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyIncidents, _, err := CompleteIncidentsPerDays(&incidentsPerDays, dates)
-	if err != nil {
-		return nil, fmt.Errorf("error completing incidents per days: %w", err)
-	}
-
-	dailyDeployments, err := CompletePipelineRunsPerDays(&pipelineRunsPerDays, dates)
-	if err != nil {
-		return nil, fmt.Errorf("error completing pipeline runs per days: %w", err)
-	}
-
-	movingAverages, err := MovingAveragesRatio(dailyIncidents, dailyDeployments, window)
+	summary, err := summarizeChangeFailureRate(&incidentsPerDays, &pipelineRunsPerDays, dates, window, granularity)
 	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.ChangeFailureRate{
@@ -70,15 +71,17 @@ func ChangeFailureRate(ctx context.Context, dataflowID primitive.ObjectID, start
 		StartDate:        startDate,
 		EndDate:          endDate,
 		Window:           window,
+		Granularity:      granularity,
+		Timezone:         location.String(),
 		Dates:            (*dates)[offset:],
-		DailyIncidents:   (*dailyIncidents)[offset:],
-		DailyDeployments: (*dailyDeployments)[offset:],
-		MovingAverages:   (*movingAverages),
-	}, err
+		DailyIncidents:   summary.DailyIncidents,
+		DailyDeployments: summary.DailyDeployments,
+		MovingAverages:   summary.MovingAverages,
+	}, nil
 }
 
 // GeneralChangeFailureRate calculates the general change failure rate over all dataflows.
-func GeneralChangeFailureRate(ctx context.Context, startDate time.Time, endDate time.Time, window int) (*models.GeneralChangeFailureRate, error) {
+func GeneralChangeFailureRate(ctx context.Context, store *daos.Store, startDate time.Time, endDate time.Time, window int, granularity string) (*models.GeneralChangeFailureRate, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -87,50 +90,51 @@ func GeneralChangeFailureRate(ctx context.Context, startDate time.Time, endDate
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	granularity, err := ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	// the days of each Dataflow are aggregated in its own timezone, and dated in UTC
+	location := time.UTC
+
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var incidentsPerDays []models.IncidentsPerDay
 	filter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err := daos.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
+	err = store.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
 	if err != nil {
 		return nil, fmt.Errorf("error listing incidents per days: %w", err)
 	}
 
 	var pipelineRunsPerDays []models.PipelineRunsPerDay
 	filter = bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err = daos.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDays)
+	err = store.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDays)
 	if err != nil {
 		return nil, fmt.Errorf("error listing pipeline runs per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyIncidents, _, err := CompleteIncidentsPerDays(&incidentsPerDays, dates)
+	summary, err := summarizeChangeFailureRate(&incidentsPerDays, &pipelineRunsPerDays, dates, window, granularity)
 	if err != nil {
-		return nil, fmt.Errorf("error completing incidents per days: %w", err)
-	}
-
-	dailyDeployments, err := CompletePipelineRunsPerDays(&pipelineRunsPerDays, dates)
-	if err != nil {
-		return nil, fmt.Errorf("error completing pipeline runs per days: %w", err)
-	}
-
-	movingAverages, err := MovingAveragesRatio(dailyIncidents, dailyDeployments, window)
-	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.GeneralChangeFailureRate{
 		StartDate:        startDate,
 		EndDate:          endDate,
 		Window:           window,
+		Granularity:      granularity,
+		Timezone:         location.String(),
 		Dates:            (*dates)[offset:],
-		DailyIncidents:   (*dailyIncidents)[offset:],
-		DailyDeployments: (*dailyDeployments)[offset:],
-		MovingAverages:   (*movingAverages),
-	}, err
+		DailyIncidents:   summary.DailyIncidents,
+		DailyDeployments: summary.DailyDeployments,
+		MovingAverages:   summary.MovingAverages,
+	}, nil
 }
@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
@@ -62,7 +61,7 @@ var _ = Describe("services.metrics.change_failure_rate", func() {
 				Pipeline:   pipeline,
 				Deployment: deployment,
 			}
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 
 			// create incidents per days
@@ -111,7 +110,7 @@ var _ = Describe("services.metrics.change_failure_rate", func() {
 				},
 			}
 
-			err = daos.CreateIncidentsPerDays(ctx, dataflow.Deployment.ID, &incidentsPerDays)
+			err = store.CreateIncidentsPerDays(ctx, dataflow.Deployment.ID, &incidentsPerDays)
 			Expect(err).To(BeNil())
 
 			// create pipeline runs per days
@@ -153,7 +152,7 @@ var _ = Describe("services.metrics.change_failure_rate", func() {
 				},
 			}
 
-			err = daos.CreatePipelineRunsPerDays(ctx, dataflow.Pipeline.ID, &pipelineRunsPerDays)
+			err = store.CreatePipelineRunsPerDays(ctx, dataflow.Pipeline.ID, &pipelineRunsPerDays)
 			Expect(err).To(BeNil())
 
 			// calculate change failure rate
@@ -161,7 +160,7 @@ var _ = Describe("services.metrics.change_failure_rate", func() {
 			endDate := time.Date(2022, 12, 29, 23, 59, 59, 0, time.UTC)
 			window := 3
 
-			cfr, err := metrics.ChangeFailureRate(ctx, dataflow.ID, startDate, endDate, window)
+			cfr, err := metrics.ChangeFailureRate(ctx, store, dataflow.ID, startDate, endDate, window, "", "")
 			Expect(err).To(BeNil())
 			Expect(cfr.DailyDeployments).To(Equal([]int{6, 2, 8, 5}))
 			Expect(cfr.DailyIncidents).To(Equal([]int{2, 1, 0, 2}))
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/utils/numeric"
+	"github.com/unnmdnwb3/dora/internal/utils/times"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StableTrend is the relative change over a period below which a metric is considered stable.
+const StableTrend = 0.1
+
+// CompareSummary compares each metric of a Summary with the preceding period of equal length,
+// and detects the trend of its series within the period.
+func CompareSummary(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, summary *models.Summary) error {
+	if len(summary.Dates) == 0 {
+		return fmt.Errorf("no dates to compare")
+	}
+
+	buckets := len(summary.Dates)
+	previousStartDate := AddBuckets(summary.Dates[0], -buckets, summary.Granularity)
+
+	// dates are days already bucketed in the timezone of the summary, hence they start at their midnight in it
+	previousEndDate := summary.Dates[0].AddDate(0, 0, -1)
+	location, err := times.Location(summary.Timezone)
+	if err != nil {
+		return err
+	}
+
+	previous, err := Summary(ctx, store, dataflowID, times.Midnight(previousStartDate, location), times.Midnight(previousEndDate, location), 1, models.StatisticMean, summary.Granularity, summary.Timezone)
+	if err != nil {
+		return fmt.Errorf("error summarizing previous period: %w", err)
+	}
+
+	deploymentFrequency := Compare(summary.DeploymentFrequency.Average, previous.DeploymentFrequency.Average, DailyTotals(summary.DeploymentFrequency.DailyPipelineRuns), true)
+	summary.DeploymentFrequency.Comparison = &deploymentFrequency
+
+	// every commit counts if the lead time of every commit is recorded
+	dailyChanges := summary.LeadTimeForChanges.DailyChanges
+	if summary.LeadTimeForChanges.DailyCommits != nil {
+		dailyChanges = summary.LeadTimeForChanges.DailyCommits
+	}
+	leadTimeForChanges := Compare(summary.LeadTimeForChanges.Average, previous.LeadTimeForChanges.Average, DailyRatios(summary.LeadTimeForChanges.DailyLeadTimes, dailyChanges), false)
+	summary.LeadTimeForChanges.Comparison = &leadTimeForChanges
+
+	meanTimeToRestore := Compare(summary.MeanTimeToRestore.Average, previous.MeanTimeToRestore.Average, DailyRatios(summary.MeanTimeToRestore.DailyDurations, summary.MeanTimeToRestore.DailyIncidents), false)
+	summary.MeanTimeToRestore.Comparison = &meanTimeToRestore
+
+	changeFailureRate := Compare(summary.ChangeFailureRate.Average, previous.ChangeFailureRate.Average, DailyRatios(summary.ChangeFailureRate.DailyIncidents, summary.ChangeFailureRate.DailyDeployments), false)
+	summary.ChangeFailureRate.Comparison = &changeFailureRate
+
+	for _, comparison := range []*models.Comparison{&deploymentFrequency, &leadTimeForChanges, &meanTimeToRestore, &changeFailureRate} {
+		comparison.PreviousStartDate = previous.StartDate
+		comparison.PreviousEndDate = previousEndDate
+	}
+
+	return nil
+}
+
+// Compare compares the average of a metric with the average of the preceding period,
+// and labels the trend of its daily series, given as points of day and value.
+func Compare(average float64, previousAverage float64, daily [][2]float64, higherIsBetter bool) models.Comparison {
+	delta := average - previousAverage
+
+	relativeDelta := 0.0
+	if previousAverage != 0 {
+		relativeDelta = delta / previousAverage
+	}
+
+	slope, trend := Trend(daily, higherIsBetter)
+	return models.Comparison{
+		PreviousAverage: previousAverage,
+		Delta:           numeric.Round(delta, 2),
+		RelativeDelta:   numeric.Round(relativeDelta, 2),
+		Slope:           numeric.Round(slope, 2),
+		Trend:           trend,
+	}
+}
+
+// Trend calculates the slope of a daily series, given as points of day and value, and labels its trend.
+// A series changing less than StableTrend relative to its mean over the whole period is stable.
+func Trend(daily [][2]float64, higherIsBetter bool) (float64, string) {
+	if len(daily) < 2 {
+		return 0, models.TrendStable
+	}
+
+	xs := make([]float64, len(daily))
+	ys := make([]float64, len(daily))
+	mean := 0.0
+	for index, point := range daily {
+		xs[index] = point[0]
+		ys[index] = point[1]
+		mean += point[1] / float64(len(daily))
+	}
+
+	slope := numeric.Slope(xs, ys)
+	if mean == 0 {
+		return slope, models.TrendStable
+	}
+
+	change := slope * (xs[len(xs)-1] - xs[0]) / math.Abs(mean)
+	if math.Abs(change) < StableTrend {
+		return slope, models.TrendStable
+	}
+
+	if (change > 0) == higherIsBetter {
+		return slope, models.TrendImproving
+	}
+	return slope, models.TrendDegrading
+}
+
+// DailyTotals returns the points of day and total of a daily series.
+func DailyTotals(totals []int) [][2]float64 {
+	daily := make([][2]float64, len(totals))
+	for index, total := range totals {
+		daily[index] = [2]float64{float64(index), float64(total)}
+	}
+	return daily
+}
+
+// DailyRatios returns the points of day and ratio of two daily series, e.g. the lead time per change.
+// Days without a denominator are left out.
+func DailyRatios(numerators []int, denominators []int) [][2]float64 {
+	daily := [][2]float64{}
+	for index := range numerators {
+		if index >= len(denominators) || denominators[index] == 0 {
+			continue
+		}
+		daily = append(daily, [2]float64{float64(index), float64(numerators[index]) / float64(denominators[index])})
+	}
+	return daily
+}
@@ -0,0 +1,50 @@
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/metrics"
+)
+
+var _ = Describe("services.metrics.comparison", func() {
+
+	var _ = When("Compare", func() {
+		It("calculates the delta to the previous period.", func() {
+			daily := metrics.DailyTotals([]int{2, 2, 2, 2})
+
+			comparison := metrics.Compare(2, 1.6, daily, true)
+			Expect(comparison.PreviousAverage).To(Equal(1.6))
+			Expect(comparison.Delta).To(Equal(0.4))
+			Expect(comparison.RelativeDelta).To(Equal(0.25))
+			Expect(comparison.Trend).To(Equal(models.TrendStable))
+		})
+
+		It("has no relative delta without a previous average.", func() {
+			comparison := metrics.Compare(2, 0, metrics.DailyTotals([]int{2}), true)
+			Expect(comparison.RelativeDelta).To(Equal(0.0))
+		})
+	})
+
+	var _ = When("Trend", func() {
+		It("detects an improving deployment frequency.", func() {
+			slope, trend := metrics.Trend(metrics.DailyTotals([]int{1, 2, 3, 4}), true)
+			Expect(slope).To(Equal(1.0))
+			Expect(trend).To(Equal(models.TrendImproving))
+		})
+
+		It("detects a degrading lead time, leaving out days without changes.", func() {
+			daily := metrics.DailyRatios([]int{600, 0, 1800, 3600}, []int{1, 0, 1, 2})
+			Expect(daily).To(HaveLen(3))
+
+			_, trend := metrics.Trend(daily, false)
+			Expect(trend).To(Equal(models.TrendDegrading))
+		})
+
+		It("is stable with less than two days.", func() {
+			_, trend := metrics.Trend(metrics.DailyRatios([]int{600}, []int{1}), false)
+			Expect(trend).To(Equal(models.TrendStable))
+		})
+	})
+})
@@ -13,7 +13,7 @@ import (
 )
 
 // DeploymentFrequency calculates the deployment frequency for a specific dataflow.
-func DeploymentFrequency(ctx context.Context, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int) (*models.DeploymentFrequency, error) {
+func DeploymentFrequency(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int, granularity string, timezone string) (*models.DeploymentFrequency, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -22,35 +22,41 @@ func DeploymentFrequency(ctx context.Context, dataflowID primitive.ObjectID, sta
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	granularity, err := ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
 	var dataflow models.Dataflow
-	err := daos.GetDataflow(ctx, dataflowID, &dataflow)
+	err = store.GetDataflow(ctx, dataflowID, &dataflow)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dataflow: %w", err)
 	}
 
+	location, err := ParseTimezone(timezone, dataflow.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var pipelineRunsPerDay []models.PipelineRunsPerDay
 	filter := bson.M{"pipeline_id": dataflow.Pipeline.ID, "date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err = daos.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDay)
+	err = store.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDay)
 	if err != nil {
 		return nil, fmt.Errorf("error getting pipeline runs per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyPipelineRuns, err := CompletePipelineRunsPerDays(&pipelineRunsPerDay, dates)
-	if err != nil {
-		return nil, fmt.Errorf("error completing pipeline runs per days: %w", err)
-	}
-
-	movingAverages, err := MovingAverages(dailyPipelineRuns, window)
+	summary, err := summarizeDeploymentFrequency(&pipelineRunsPerDay, dates, window, granularity)
 	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.DeploymentFrequency{
@@ -58,14 +64,16 @@ func DeploymentFrequency(ctx context.Context, dataflowID primitive.ObjectID, sta
 		StartDate:         startDate,
 		EndDate:           endDate,
 		Window:            window,
+		Granularity:       granularity,
+		Timezone:          location.String(),
 		Dates:             (*dates)[offset:],
-		DailyPipelineRuns: (*dailyPipelineRuns)[offset:],
-		MovingAverages:    (*movingAverages),
+		DailyPipelineRuns: summary.DailyPipelineRuns,
+		MovingAverages:    summary.MovingAverages,
 	}, nil
 }
 
 // GeneralDeploymentFrequency calculates the general deployment frequency over all dataflows.
-func GeneralDeploymentFrequency(ctx context.Context, startDate time.Time, endDate time.Time, window int) (*models.GeneralDeploymentFrequency, error) {
+func GeneralDeploymentFrequency(ctx context.Context, store *daos.Store, startDate time.Time, endDate time.Time, window int, granularity string) (*models.GeneralDeploymentFrequency, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -74,37 +82,43 @@ func GeneralDeploymentFrequency(ctx context.Context, startDate time.Time, endDat
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	granularity, err := ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	// the days of each Dataflow are aggregated in its own timezone, and dated in UTC
+	location := time.UTC
+
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var pipelineRunsPerDay []models.PipelineRunsPerDay
 	filter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err := daos.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDay)
+	err = store.ListPipelineRunsPerDaysByFilter(ctx, filter, &pipelineRunsPerDay)
 	if err != nil {
 		return nil, fmt.Errorf("error getting pipeline runs per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyPipelineRuns, err := CompletePipelineRunsPerDays(&pipelineRunsPerDay, dates)
-	if err != nil {
-		return nil, fmt.Errorf("error completing pipeline runs per days: %w", err)
-	}
-
-	movingAverages, err := MovingAverages(dailyPipelineRuns, window)
+	summary, err := summarizeDeploymentFrequency(&pipelineRunsPerDay, dates, window, granularity)
 	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.GeneralDeploymentFrequency{
 		StartDate:         startDate,
 		EndDate:           endDate,
 		Window:            window,
+		Granularity:       granularity,
+		Timezone:          location.String(),
 		Dates:             (*dates)[offset:],
-		DailyPipelineRuns: (*dailyPipelineRuns)[offset:],
-		MovingAverages:    (*movingAverages),
+		DailyPipelineRuns: summary.DailyPipelineRuns,
+		MovingAverages:    summary.MovingAverages,
 	}, nil
 }
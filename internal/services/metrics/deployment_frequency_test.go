@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
@@ -65,7 +64,7 @@ var _ = Describe("services.metrics.deployment_frequency", func() {
 				Pipeline:   pipeline,
 				Deployment: deployment,
 			}
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 
 			// create pipeline runs per days
@@ -116,14 +115,14 @@ var _ = Describe("services.metrics.deployment_frequency", func() {
 					TotalPipelineRuns: 0,
 				},
 			}
-			err = daos.CreatePipelineRunsPerDays(ctx, dataflow.Pipeline.ID, &pipelineRunsPerDays)
+			err = store.CreatePipelineRunsPerDays(ctx, dataflow.Pipeline.ID, &pipelineRunsPerDays)
 			Expect(err).To(BeNil())
 
 			startDate := time.Date(2022, 2, 4, 0, 0, 0, 0, time.UTC)
 			endDate := time.Date(2022, 2, 9, 0, 0, 0, 0, time.UTC)
 			window := 3
 
-			deploymentFrequency, err := metrics.DeploymentFrequency(ctx, dataflow.ID, startDate, endDate, window)
+			deploymentFrequency, err := metrics.DeploymentFrequency(ctx, store, dataflow.ID, startDate, endDate, window, "", "")
 			Expect(err).To(BeNil())
 			Expect(deploymentFrequency.DataflowID).To(Equal(dataflow.ID))
 			Expect(deploymentFrequency.MovingAverages).To(Equal([]float64{1.0, 1.0, 1.0, 1.0, 1.0, 1.0}))
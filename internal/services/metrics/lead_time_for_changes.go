@@ -13,7 +13,7 @@ import (
 )
 
 // LeadTimeForChanges calculates the lead time for changes for a specific dataflow.
-func LeadTimeForChanges(ctx context.Context, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int) (*models.LeadTimeForChanges, error) {
+func LeadTimeForChanges(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int, statistic string, granularity string, timezone string) (*models.LeadTimeForChanges, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -22,51 +22,67 @@ func LeadTimeForChanges(ctx context.Context, dataflowID primitive.ObjectID, star
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	statistic, err := ParseStatistic(statistic)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity, err = ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
 	var dataflow models.Dataflow
-	err := daos.GetDataflow(ctx, dataflowID, &dataflow)
+	err = store.GetDataflow(ctx, dataflowID, &dataflow)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := ParseTimezone(timezone, dataflow.Timezone)
 	if err != nil {
 		return nil, err
 	}
 
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var changesPerDay []models.ChangesPerDay
 	filter := bson.M{"repository_id": dataflow.Repository.ID, "date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err = daos.ListChangesPerDaysByFilter(ctx, filter, &changesPerDay)
+	err = store.ListChangesPerDaysByFilter(ctx, filter, &changesPerDay)
 	if err != nil {
 		return nil, fmt.Errorf("error listing changes per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyChanges, dailyLeadTimes, err := CompleteChangesPerDays(&changesPerDay, dates)
-	if err != nil {
-		return nil, fmt.Errorf("error completing changes per days: %w", err)
-	}
-
-	movingAverages, err := MovingAverages(dailyLeadTimes, window)
+	summary, err := summarizeLeadTimeForChanges(&changesPerDay, dates, window, granularity, statistic, dataflow.LeadTimePerCommit)
 	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.LeadTimeForChanges{
-		DataflowID:     dataflow.ID,
-		Dates:          (*dates)[offset:],
-		StartDate:      startDate,
-		EndDate:        endDate,
-		Window:         window,
-		DailyChanges:   (*dailyChanges)[offset:],
-		DailyLeadTimes: (*dailyLeadTimes)[offset:],
-		MovingAverages: *movingAverages,
+		DataflowID:        dataflow.ID,
+		Dates:             (*dates)[offset:],
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Window:            window,
+		Granularity:       granularity,
+		Timezone:          location.String(),
+		DailyChanges:      summary.DailyChanges,
+		DailyCommits:      summary.DailyCommits,
+		DailyLeadTimes:    summary.DailyLeadTimes,
+		MovingAverages:    summary.MovingAverages,
+		Statistic:         statistic,
+		MovingPercentiles: summary.MovingPercentiles,
 	}, nil
 }
 
 // GeneralLeadTimeForChanges calculates the general lead time for changes over all dataflows.
-func GeneralLeadTimeForChanges(ctx context.Context, startDate time.Time, endDate time.Time, window int) (*models.GeneralLeadTimeForChanges, error) {
+func GeneralLeadTimeForChanges(ctx context.Context, store *daos.Store, startDate time.Time, endDate time.Time, window int, statistic string, granularity string) (*models.GeneralLeadTimeForChanges, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -75,38 +91,51 @@ func GeneralLeadTimeForChanges(ctx context.Context, startDate time.Time, endDate
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	statistic, err := ParseStatistic(statistic)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity, err = ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	// the days of each Dataflow are aggregated in its own timezone, and dated in UTC
+	location := time.UTC
+
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var changesPerDay []models.ChangesPerDay
 	filter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err := daos.ListChangesPerDaysByFilter(ctx, filter, &changesPerDay)
+	err = store.ListChangesPerDaysByFilter(ctx, filter, &changesPerDay)
 	if err != nil {
 		return nil, fmt.Errorf("error listing changes per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyChanges, dailyLeadTimes, err := CompleteChangesPerDays(&changesPerDay, dates)
+	summary, err := summarizeLeadTimeForChanges(&changesPerDay, dates, window, granularity, statistic, false)
 	if err != nil {
-		return nil, fmt.Errorf("error completing changes per days: %w", err)
-	}
-
-	movingAverages, err := MovingAverages(dailyLeadTimes, window)
-	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.GeneralLeadTimeForChanges{
-		Dates:          (*dates)[offset:],
-		StartDate:      startDate,
-		EndDate:        endDate,
-		Window:         window,
-		DailyChanges:   (*dailyChanges)[offset:],
-		DailyLeadTimes: (*dailyLeadTimes)[offset:],
-		MovingAverages: *movingAverages,
+		Dates:             (*dates)[offset:],
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Window:            window,
+		Granularity:       granularity,
+		Timezone:          location.String(),
+		DailyChanges:      summary.DailyChanges,
+		DailyLeadTimes:    summary.DailyLeadTimes,
+		MovingAverages:    summary.MovingAverages,
+		Statistic:         statistic,
+		MovingPercentiles: summary.MovingPercentiles,
 	}, nil
 }
@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
@@ -66,7 +65,7 @@ var _ = Describe("services.metrics.lead_time_for_changes", func() {
 				Deployment: deployment,
 			}
 
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 
 			// create changes per days
@@ -122,7 +121,7 @@ var _ = Describe("services.metrics.lead_time_for_changes", func() {
 				},
 			}
 
-			err = daos.CreateChangesPerDays(ctx, dataflow.Repository.ID, dataflow.Pipeline.ID, &changesPerDays)
+			err = store.CreateChangesPerDays(ctx, dataflow.Repository.ID, dataflow.Pipeline.ID, &changesPerDays)
 			Expect(err).To(BeNil())
 
 			// calculate lead time for changes rate
@@ -130,7 +129,7 @@ var _ = Describe("services.metrics.lead_time_for_changes", func() {
 			endDate := time.Date(2022, 12, 29, 23, 59, 59, 0, time.UTC)
 			window := 3
 
-			leadTimeForChanges, err := metrics.LeadTimeForChanges(ctx, dataflow.ID, startDate, endDate, window)
+			leadTimeForChanges, err := metrics.LeadTimeForChanges(ctx, store, dataflow.ID, startDate, endDate, window, "", "", "")
 			Expect(err).To(BeNil())
 			Expect(leadTimeForChanges.DailyChanges).To(Equal([]int{2, 1, 0, 2}))
 			Expect(leadTimeForChanges.MovingAverages).To(Equal([]float64{700, 2500, 2600, 3000}))
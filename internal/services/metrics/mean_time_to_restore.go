@@ -13,7 +13,7 @@ import (
 )
 
 // MeanTimeToRestore calculates the mean time to restore for a specific dataflow.
-func MeanTimeToRestore(ctx context.Context, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int) (*models.MeanTimeToRestore, error) {
+func MeanTimeToRestore(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int, statistic string, granularity string, timezone string) (*models.MeanTimeToRestore, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -22,51 +22,66 @@ func MeanTimeToRestore(ctx context.Context, dataflowID primitive.ObjectID, start
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	statistic, err := ParseStatistic(statistic)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity, err = ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
 	var dataflow models.Dataflow
-	err := daos.GetDataflow(ctx, dataflowID, &dataflow)
+	err = store.GetDataflow(ctx, dataflowID, &dataflow)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := ParseTimezone(timezone, dataflow.Timezone)
 	if err != nil {
 		return nil, err
 	}
 
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var incidentsPerDays []models.IncidentsPerDay
 	filter := bson.M{"deployment_id": dataflow.Deployment.ID, "date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err = daos.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
+	err = store.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
 	if err != nil {
 		return nil, fmt.Errorf("error getting incidents per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyIncidents, dailyDurations, err := CompleteIncidentsPerDays(&incidentsPerDays, dates)
-	if err != nil {
-		return nil, fmt.Errorf("error completing incidents per days: %w", err)
-	}
-
-	movingAverages, err := MovingAverages(dailyDurations, window)
+	summary, err := summarizeMeanTimeToRestore(&incidentsPerDays, dates, window, granularity, statistic)
 	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.MeanTimeToRestore{
-		DataflowID:     dataflow.ID,
-		StartDate:      startDate,
-		EndDate:        endDate,
-		Window:         window,
-		Dates:          (*dates)[offset:],
-		DailyIncidents: (*dailyIncidents)[offset:],
-		DailyDurations: (*dailyDurations)[offset:],
-		MovingAverages: *movingAverages,
+		DataflowID:        dataflow.ID,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Window:            window,
+		Granularity:       granularity,
+		Timezone:          location.String(),
+		Dates:             (*dates)[offset:],
+		DailyIncidents:    summary.DailyIncidents,
+		DailyDurations:    summary.DailyDurations,
+		MovingAverages:    summary.MovingAverages,
+		Statistic:         statistic,
+		MovingPercentiles: summary.MovingPercentiles,
 	}, nil
 }
 
 // GeneralMeanTimeToRestore calculates the general mean time to restore over all dataflows.
-func GeneralMeanTimeToRestore(ctx context.Context, startDate time.Time, endDate time.Time, window int) (*models.GeneralMeanTimeToRestore, error) {
+func GeneralMeanTimeToRestore(ctx context.Context, store *daos.Store, startDate time.Time, endDate time.Time, window int, statistic string, granularity string) (*models.GeneralMeanTimeToRestore, error) {
 	if window < 1 {
 		return nil, fmt.Errorf("window must be greater than 0")
 	}
@@ -75,38 +90,51 @@ func GeneralMeanTimeToRestore(ctx context.Context, startDate time.Time, endDate
 		return nil, fmt.Errorf("start date must be before end date")
 	}
 
+	statistic, err := ParseStatistic(statistic)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity, err = ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	// the days of each Dataflow are aggregated in its own timezone, and dated in UTC
+	location := time.UTC
+
 	offset := window - 1
-	startDate = times.Date(startDate.AddDate(0, 0, -offset))
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
 
 	var incidentsPerDays []models.IncidentsPerDay
 	filter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
-	err := daos.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
+	err = store.ListIncidentsPerDaysByFilter(ctx, filter, &incidentsPerDays)
 	if err != nil {
 		return nil, fmt.Errorf("error getting incidents per days: %w", err)
 	}
 
-	dates, err := DatesBetween(startDate, endDate)
+	dates, err := DatesBetween(startDate, endDate, granularity)
 	if err != nil {
 		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
 	}
 
-	dailyIncidents, dailyDurations, err := CompleteIncidentsPerDays(&incidentsPerDays, dates)
+	summary, err := summarizeMeanTimeToRestore(&incidentsPerDays, dates, window, granularity, statistic)
 	if err != nil {
-		return nil, fmt.Errorf("error completing incidents per days: %w", err)
-	}
-
-	movingAverages, err := MovingAverages(dailyDurations, window)
-	if err != nil {
-		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+		return nil, err
 	}
 
 	return &models.GeneralMeanTimeToRestore{
-		StartDate:      startDate,
-		EndDate:        endDate,
-		Window:         window,
-		Dates:          (*dates)[offset:],
-		DailyIncidents: (*dailyIncidents)[offset:],
-		DailyDurations: (*dailyDurations)[offset:],
-		MovingAverages: *movingAverages,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Window:            window,
+		Granularity:       granularity,
+		Timezone:          location.String(),
+		Dates:             (*dates)[offset:],
+		DailyIncidents:    summary.DailyIncidents,
+		DailyDurations:    summary.DailyDurations,
+		MovingAverages:    summary.MovingAverages,
+		Statistic:         statistic,
+		MovingPercentiles: summary.MovingPercentiles,
 	}, nil
 }
@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
@@ -63,7 +62,7 @@ var _ = Describe("services.metrics.mean_time_to_restore", func() {
 				Pipeline:   pipeline,
 				Deployment: deployment,
 			}
-			err := daos.CreateDataflow(ctx, &dataflow)
+			err := store.CreateDataflow(ctx, &dataflow)
 			Expect(err).To(BeNil())
 
 			// create incidents per days
@@ -106,7 +105,7 @@ var _ = Describe("services.metrics.mean_time_to_restore", func() {
 				},
 			}
 
-			err = daos.CreateIncidentsPerDays(ctx, dataflow.Deployment.ID, &incidentsPerDays)
+			err = store.CreateIncidentsPerDays(ctx, dataflow.Deployment.ID, &incidentsPerDays)
 			Expect(err).To(BeNil())
 
 			// calculate mean time to restore
@@ -114,7 +113,7 @@ var _ = Describe("services.metrics.mean_time_to_restore", func() {
 			endDate := time.Date(2022, 12, 29, 23, 59, 59, 0, time.UTC)
 			window := 3
 
-			meanTimeToRestore, err := metrics.MeanTimeToRestore(ctx, dataflow.ID, startDate, endDate, window)
+			meanTimeToRestore, err := metrics.MeanTimeToRestore(ctx, store, dataflow.ID, startDate, endDate, window, "", "", "")
 			Expect(err).To(BeNil())
 			Expect(meanTimeToRestore.DailyIncidents).To(Equal([]int{2, 1, 0, 2}))
 			Expect(meanTimeToRestore.DailyDurations).To(Equal([]int{1200, 600, 0, 1200}))
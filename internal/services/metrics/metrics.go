@@ -34,6 +34,41 @@ func MovingAverages(totals *[]int, window int) (*[]float64, error) {
 	return &movingAverages, nil
 }
 
+// MovingPercentiles calculates the moving percentiles for a given slice of values per day.
+// A window without any values has a percentile of 0.
+func MovingPercentiles(values *[][]float64, window int, percentile float64) (*[]float64, error) {
+	if len(*values) == 0 {
+		return nil, fmt.Errorf("no data provided to calculate moving percentiles")
+	}
+
+	offset := window - 1
+	movingPercentiles := make([]float64, len(*values)-offset)
+
+	for index := offset; index < len(*values); index++ {
+		valuesInWindow := []float64{}
+		for _, dailyValues := range (*values)[index-offset : index+1] {
+			valuesInWindow = append(valuesInWindow, dailyValues...)
+		}
+		val := numeric.Percentile(valuesInWindow, percentile)
+		movingPercentiles[index-offset] = numeric.Round(val, 2)
+	}
+
+	return &movingPercentiles, nil
+}
+
+// ParseStatistic returns the statistic requested, or StatisticMean if none is requested.
+func ParseStatistic(statistic string) (string, error) {
+	if statistic == "" {
+		return models.StatisticMean, nil
+	}
+
+	_, ok := models.Percentiles[statistic]
+	if statistic != models.StatisticMean && !ok {
+		return "", fmt.Errorf("unknown statistic: %s", statistic)
+	}
+	return statistic, nil
+}
+
 // MovingAveragesRatio calculates the moving averages for a given two slices of totals.
 func MovingAveragesRatio(numerators *[]int, denominators *[]int, window int) (*[]float64, error) {
 	if len(*numerators) == 0 || len(*denominators) == 0 {
@@ -69,27 +104,87 @@ func MovingAveragesRatio(numerators *[]int, denominators *[]int, window int) (*[
 	return &movingAverages, nil
 }
 
-// DatesBetween returns a slice of dates between the start and end dates.
-func DatesBetween(startDate time.Time, endDate time.Time) (*[]time.Time, error) {
-	startDate = times.Date(startDate)
-	endDate = times.Date(endDate)
+// DatesBetween returns a slice of the first dates of all buckets between the start and end dates.
+func DatesBetween(startDate time.Time, endDate time.Time, granularity string) (*[]time.Time, error) {
+	startDate = Bucket(startDate, granularity)
+	endDate = Bucket(endDate, granularity)
 
 	if startDate.After(endDate) {
 		return nil, fmt.Errorf("start date is after end date")
 	}
 
 	dates := []time.Time{}
-	for date := startDate; date.Before(endDate); date = date.AddDate(0, 0, 1) {
-		dates = append(dates, times.Date(date))
+	for date := startDate; date.Before(endDate); date = AddBuckets(date, 1, granularity) {
+		dates = append(dates, date)
 	}
-	dates = append(dates, times.Date(endDate))
+	dates = append(dates, endDate)
 
 	return &dates, nil
 }
 
-// CompleteIncidentsPerDays returns a slice of the number of incidents per day,
+// ParseGranularity returns the granularity requested, or GranularityDay if none is requested.
+func ParseGranularity(granularity string) (string, error) {
+	switch granularity {
+	case "":
+		return models.GranularityDay, nil
+	case models.GranularityDay, models.GranularityWeek, models.GranularityMonth:
+		return granularity, nil
+	default:
+		return "", fmt.Errorf("unknown granularity: %s", granularity)
+	}
+}
+
+// ParseTimezone returns the location of the timezone the days were aggregated in, UTC if not set.
+// As the aggregates cannot be bucketed again per request, a different timezone requested is an error.
+func ParseTimezone(timezone string, aggregated string) (*time.Location, error) {
+	location, err := times.Location(aggregated)
+	if err != nil {
+		return nil, err
+	}
+
+	if timezone == "" {
+		return location, nil
+	}
+
+	requested, err := times.Location(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if requested.String() != location.String() {
+		return nil, fmt.Errorf("timezone %s differs from the timezone %s the days are aggregated in", requested.String(), location.String())
+	}
+	return location, nil
+}
+
+// Bucket returns the first date of the bucket of a date.
+// Dates are days already bucketed in their timezone, hence bucketed into weeks and months in UTC.
+func Bucket(date time.Time, granularity string) time.Time {
+	switch granularity {
+	case models.GranularityWeek:
+		return times.Week(date, time.UTC)
+	case models.GranularityMonth:
+		return times.Month(date, time.UTC)
+	default:
+		return times.Date(date, time.UTC)
+	}
+}
+
+// AddBuckets adds a number of buckets to the first date of a bucket.
+func AddBuckets(date time.Time, buckets int, granularity string) time.Time {
+	switch granularity {
+	case models.GranularityWeek:
+		return date.AddDate(0, 0, 7*buckets)
+	case models.GranularityMonth:
+		return date.AddDate(0, buckets, 0)
+	default:
+		return date.AddDate(0, 0, buckets)
+	}
+}
+
+// CompleteIncidentsPerDays returns a slice of the number of incidents per bucket,
 // since provided IncidentsPerDays only account for the dates that any incidents were found.
-func CompleteIncidentsPerDays(incidentsPerDays *[]models.IncidentsPerDay, dates *[]time.Time) (*[]int, *[]int, error) {
+func CompleteIncidentsPerDays(incidentsPerDays *[]models.IncidentsPerDay, dates *[]time.Time, granularity string) (*[]int, *[]int, error) {
 	if len(*dates) == 0 {
 		return nil, nil, fmt.Errorf("no dates provided")
 	}
@@ -103,7 +198,7 @@ func CompleteIncidentsPerDays(incidentsPerDays *[]models.IncidentsPerDay, dates
 		sumIncidents := 0
 		sumDuration := 0
 		for j := curr; j < len(*incidentsPerDays); j++ {
-			if (*incidentsPerDays)[j].Date == date {
+			if Bucket((*incidentsPerDays)[j].Date, granularity).Equal(date) {
 				sumIncidents += (*incidentsPerDays)[j].TotalIncidents
 				sumDuration += int((*incidentsPerDays)[j].TotalDuration)
 				curr++
@@ -119,9 +214,9 @@ func CompleteIncidentsPerDays(incidentsPerDays *[]models.IncidentsPerDay, dates
 	return &dailyIncidents, &dailyDurations, nil
 }
 
-// CompletePipelineRunsPerDays returns a slice of the number of pipeline runs per day,
+// CompletePipelineRunsPerDays returns a slice of the number of pipeline runs per bucket,
 // since provided PipelineRunsPerDays only account for the dates that any pipeline runs were found.
-func CompletePipelineRunsPerDays(pipelineRunsPerDays *[]models.PipelineRunsPerDay, dates *[]time.Time) (*[]int, error) {
+func CompletePipelineRunsPerDays(pipelineRunsPerDays *[]models.PipelineRunsPerDay, dates *[]time.Time, granularity string) (*[]int, error) {
 	if len(*dates) == 0 {
 		return nil, fmt.Errorf("no dates provided")
 	}
@@ -132,7 +227,7 @@ func CompletePipelineRunsPerDays(pipelineRunsPerDays *[]models.PipelineRunsPerDa
 	for i, date := range *dates {
 		sum := 0
 		for j := curr; j < len(*pipelineRunsPerDays); j++ {
-			if (*pipelineRunsPerDays)[j].Date == date {
+			if Bucket((*pipelineRunsPerDays)[j].Date, granularity).Equal(date) {
 				sum += (*pipelineRunsPerDays)[j].TotalPipelineRuns
 				curr++
 			} else {
@@ -146,9 +241,9 @@ func CompletePipelineRunsPerDays(pipelineRunsPerDays *[]models.PipelineRunsPerDa
 	return &dailyPipelineRuns, nil
 }
 
-// CompleteChangesPerDays returns a slice of the number of changes per day,
+// CompleteChangesPerDays returns a slice of the number of changes per bucket,
 // since provided ChangesPerDays only account for the dates that any changes were found.
-func CompleteChangesPerDays(changesPerDays *[]models.ChangesPerDay, dates *[]time.Time) (*[]int, *[]int, error) {
+func CompleteChangesPerDays(changesPerDays *[]models.ChangesPerDay, dates *[]time.Time, granularity string) (*[]int, *[]int, error) {
 	if len(*dates) == 0 {
 		return nil, nil, fmt.Errorf("no dates provided")
 	}
@@ -162,7 +257,7 @@ func CompleteChangesPerDays(changesPerDays *[]models.ChangesPerDay, dates *[]tim
 		sumChanges := 0
 		sumLeadTimes := 0.0
 		for j := curr; j < len(*changesPerDays); j++ {
-			if (*changesPerDays)[j].Date == date {
+			if Bucket((*changesPerDays)[j].Date, granularity).Equal(date) {
 				sumChanges += (*changesPerDays)[j].TotalChanges
 				sumLeadTimes += (*changesPerDays)[j].TotalLeadTime
 				curr++
@@ -177,3 +272,95 @@ func CompleteChangesPerDays(changesPerDays *[]models.ChangesPerDay, dates *[]tim
 
 	return &dailyChanges, &dailyLeadTimes, nil
 }
+
+// CompleteCommitsPerDays returns a slice of the number of commits deployed per bucket and their lead times,
+// since provided ChangesPerDays only account for the dates that any changes were found.
+func CompleteCommitsPerDays(changesPerDays *[]models.ChangesPerDay, dates *[]time.Time, granularity string) (*[]int, *[]int, error) {
+	if len(*dates) == 0 {
+		return nil, nil, fmt.Errorf("no dates provided")
+	}
+
+	dailyCommits := make([]int, len(*dates))
+	dailyLeadTimes := make([]int, len(*dates))
+
+	curr := 0
+
+	for i, date := range *dates {
+		sumCommits := 0
+		sumLeadTimes := 0.0
+		for j := curr; j < len(*changesPerDays); j++ {
+			if Bucket((*changesPerDays)[j].Date, granularity).Equal(date) {
+				sumCommits += (*changesPerDays)[j].TotalCommits
+				sumLeadTimes += (*changesPerDays)[j].TotalCommitLeadTime
+				curr++
+			} else {
+				break
+			}
+		}
+
+		dailyCommits[i] = sumCommits
+		dailyLeadTimes[i] = int(sumLeadTimes)
+	}
+
+	return &dailyCommits, &dailyLeadTimes, nil
+}
+
+// CompleteLeadTimesPerDays returns a slice of the lead times of the changes per bucket, or of their commits if perCommit is set,
+// since provided ChangesPerDays only account for the dates that any changes were found.
+func CompleteLeadTimesPerDays(changesPerDays *[]models.ChangesPerDay, dates *[]time.Time, granularity string, perCommit bool) (*[][]float64, error) {
+	if len(*dates) == 0 {
+		return nil, fmt.Errorf("no dates provided")
+	}
+
+	dailyLeadTimes := make([][]float64, len(*dates))
+
+	curr := 0
+
+	for i, date := range *dates {
+		leadTimes := []float64{}
+		for j := curr; j < len(*changesPerDays); j++ {
+			if Bucket((*changesPerDays)[j].Date, granularity).Equal(date) {
+				if perCommit {
+					leadTimes = append(leadTimes, (*changesPerDays)[j].CommitLeadTimes...)
+				} else {
+					leadTimes = append(leadTimes, (*changesPerDays)[j].LeadTimes...)
+				}
+				curr++
+			} else {
+				break
+			}
+		}
+
+		dailyLeadTimes[i] = leadTimes
+	}
+
+	return &dailyLeadTimes, nil
+}
+
+// CompleteDurationsPerDays returns a slice of the durations of the incidents per bucket,
+// since provided IncidentsPerDays only account for the dates that any incidents were found.
+func CompleteDurationsPerDays(incidentsPerDays *[]models.IncidentsPerDay, dates *[]time.Time, granularity string) (*[][]float64, error) {
+	if len(*dates) == 0 {
+		return nil, fmt.Errorf("no dates provided")
+	}
+
+	dailyDurations := make([][]float64, len(*dates))
+
+	curr := 0
+
+	for i, date := range *dates {
+		durations := []float64{}
+		for j := curr; j < len(*incidentsPerDays); j++ {
+			if Bucket((*incidentsPerDays)[j].Date, granularity).Equal(date) {
+				durations = append(durations, (*incidentsPerDays)[j].Durations...)
+				curr++
+			} else {
+				break
+			}
+		}
+
+		dailyDurations[i] = durations
+	}
+
+	return &dailyDurations, nil
+}
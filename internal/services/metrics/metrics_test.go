@@ -1,9 +1,12 @@
 package metrics_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/metrics"
 )
 
@@ -33,4 +36,156 @@ var _ = Describe("services.metrics.metrics", func() {
 			Expect((*movingAverages)).To(Equal([]float64{0.25, 0.25, 0.25}))
 		})
 	})
+
+	var _ = When("MovingPercentiles", func() {
+		It("returns a list of MovingPercentiles over the values of each window.", func() {
+			leadTimesPerDay := [][]float64{{600}, {}, {300, 900}, {86400}, {}}
+
+			movingPercentiles, err := metrics.MovingPercentiles(&leadTimesPerDay, 3, 50)
+			Expect(err).To(BeNil())
+			Expect(*movingPercentiles).To(Equal([]float64{600, 900, 900}))
+		})
+
+		It("returns 0 for windows without values.", func() {
+			durationsPerDay := [][]float64{{}, {}, {120}}
+
+			movingPercentiles, err := metrics.MovingPercentiles(&durationsPerDay, 2, 95)
+			Expect(err).To(BeNil())
+			Expect(*movingPercentiles).To(Equal([]float64{0, 120}))
+		})
+	})
+
+	var _ = When("Average", func() {
+		It("returns the ratio of two totals.", func() {
+			Expect(metrics.Average(3600, 3)).To(Equal(1200.0))
+		})
+
+		It("returns 0 without a denominator.", func() {
+			Expect(metrics.Average(0, 0)).To(Equal(0.0))
+		})
+	})
+
+	var _ = When("DatesBetween", func() {
+		It("returns the first date of each ISO week.", func() {
+			startDate := time.Date(2022, 12, 21, 13, 0, 0, 0, time.UTC)
+			endDate := time.Date(2023, 1, 3, 8, 0, 0, 0, time.UTC)
+
+			dates, err := metrics.DatesBetween(startDate, endDate, models.GranularityWeek)
+			Expect(err).To(BeNil())
+			Expect(*dates).To(Equal([]time.Time{
+				time.Date(2022, 12, 19, 0, 0, 0, 0, time.UTC),
+				time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			}))
+		})
+
+		It("returns the first date of each month.", func() {
+			startDate := time.Date(2022, 11, 30, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+
+			dates, err := metrics.DatesBetween(startDate, endDate, models.GranularityMonth)
+			Expect(err).To(BeNil())
+			Expect(*dates).To(Equal([]time.Time{
+				time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			}))
+		})
+	})
+
+	var _ = When("CompletePipelineRunsPerDays", func() {
+		It("sums the pipeline runs of all days within a week.", func() {
+			pipelineRunsPerDays := []models.PipelineRunsPerDay{
+				{Date: time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC), TotalPipelineRuns: 1},
+				{Date: time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC), TotalPipelineRuns: 2},
+				{Date: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), TotalPipelineRuns: 4},
+			}
+			dates, err := metrics.DatesBetween(time.Date(2022, 12, 19, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC), models.GranularityWeek)
+			Expect(err).To(BeNil())
+
+			dailyPipelineRuns, err := metrics.CompletePipelineRunsPerDays(&pipelineRunsPerDays, dates, models.GranularityWeek)
+			Expect(err).To(BeNil())
+			Expect(*dailyPipelineRuns).To(Equal([]int{3, 0, 4}))
+		})
+	})
+
+	var _ = When("ParseGranularity", func() {
+		It("defaults to days.", func() {
+			granularity, err := metrics.ParseGranularity("")
+			Expect(err).To(BeNil())
+			Expect(granularity).To(Equal(models.GranularityDay))
+		})
+
+		It("rejects unknown granularities.", func() {
+			_, err := metrics.ParseGranularity("year")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	var _ = When("ParseStatistic", func() {
+		It("defaults to the mean.", func() {
+			statistic, err := metrics.ParseStatistic("")
+			Expect(err).To(BeNil())
+			Expect(statistic).To(Equal(models.StatisticMean))
+		})
+
+		It("rejects unknown statistics.", func() {
+			_, err := metrics.ParseStatistic("p99")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	var _ = When("ParseTimezone", func() {
+		It("returns the timezone the days are aggregated in, UTC if not set.", func() {
+			location, err := metrics.ParseTimezone("", "Europe/Zurich")
+			Expect(err).To(BeNil())
+			Expect(location.String()).To(Equal("Europe/Zurich"))
+
+			location, err = metrics.ParseTimezone("", "")
+			Expect(err).To(BeNil())
+			Expect(location).To(Equal(time.UTC))
+		})
+
+		It("accepts the timezone the days are aggregated in.", func() {
+			location, err := metrics.ParseTimezone("Europe/Zurich", "Europe/Zurich")
+			Expect(err).To(BeNil())
+			Expect(location.String()).To(Equal("Europe/Zurich"))
+		})
+
+		It("returns an error for a different timezone, as the days cannot be bucketed again.", func() {
+			_, err := metrics.ParseTimezone("America/New_York", "Europe/Zurich")
+			Expect(err).ToNot(BeNil())
+
+			_, err = metrics.ParseTimezone("Europe/Zurich", "")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	var _ = When("CompleteCommitsPerDays", func() {
+		It("returns the commits and their lead times for every date.", func() {
+			changesPerDays := []models.ChangesPerDay{
+				{
+					Date:                time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC),
+					TotalChanges:        2,
+					TotalLeadTime:       4500,
+					TotalCommits:        4,
+					TotalCommitLeadTime: 6900,
+				},
+				{
+					Date:                time.Date(2022, 12, 29, 0, 0, 0, 0, time.UTC),
+					TotalChanges:        1,
+					TotalLeadTime:       1200,
+					TotalCommits:        1,
+					TotalCommitLeadTime: 1200,
+				},
+			}
+			dates, err := metrics.DatesBetween(time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC), time.Date(2022, 12, 29, 0, 0, 0, 0, time.UTC), models.GranularityDay)
+			Expect(err).To(BeNil())
+
+			dailyCommits, dailyLeadTimes, err := metrics.CompleteCommitsPerDays(&changesPerDays, dates, models.GranularityDay)
+			Expect(err).To(BeNil())
+			Expect(*dailyCommits).To(Equal([]int{4, 0, 1}))
+			Expect(*dailyLeadTimes).To(Equal([]int{6900, 0, 1200}))
+		})
+	})
 })
@@ -1,13 +1,32 @@
 package metrics_test
 
 import (
+	"context"
+	"os"
 	"testing"
 
+	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/daos"
 )
 
 func TestServices(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "services.metrics Suite")
 }
+
+// store is connected to the test database before each spec
+var store *daos.Store
+
+var _ = BeforeEach(func() {
+	_ = godotenv.Load("./../../../test/.env")
+
+	var err error
+	store, err = daos.NewStore(context.Background(), os.Getenv("MONGODB_DATABASE"))
+	Expect(err).To(BeNil())
+})
+
+var _ = AfterEach(func() {
+	store.Close(context.Background())
+})
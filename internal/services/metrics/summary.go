@@ -0,0 +1,287 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/utils/numeric"
+	"github.com/unnmdnwb3/dora/internal/utils/times"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Summary calculates all four metrics for a specific dataflow, or over all dataflows if no dataflowID is given,
+// loading the daily aggregates only once.
+func Summary(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, startDate time.Time, endDate time.Time, window int, statistic string, granularity string, timezone string) (*models.Summary, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("window must be greater than 0")
+	}
+
+	if startDate.After(endDate) {
+		return nil, fmt.Errorf("start date must be before end date")
+	}
+
+	statistic, err := ParseStatistic(statistic)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity, err = ParseGranularity(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataflow models.Dataflow
+	if !dataflowID.IsZero() {
+		err = store.GetDataflow(ctx, dataflowID, &dataflow)
+		if err != nil {
+			return nil, fmt.Errorf("error getting dataflow: %w", err)
+		}
+	}
+
+	location, err := ParseTimezone(timezone, dataflow.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := window - 1
+	startDate = AddBuckets(Bucket(times.Date(startDate, location), granularity), -offset, granularity)
+	endDate = times.Date(endDate, location)
+
+	pipelineRunsFilter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
+	changesFilter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
+	incidentsFilter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
+
+	if !dataflowID.IsZero() {
+		pipelineRunsFilter["pipeline_id"] = dataflow.Pipeline.ID
+		changesFilter["repository_id"] = dataflow.Repository.ID
+		incidentsFilter["deployment_id"] = dataflow.Deployment.ID
+	}
+
+	var pipelineRunsPerDays []models.PipelineRunsPerDay
+	err = store.ListPipelineRunsPerDaysByFilter(ctx, pipelineRunsFilter, &pipelineRunsPerDays)
+	if err != nil {
+		return nil, fmt.Errorf("error listing pipeline runs per days: %w", err)
+	}
+
+	var changesPerDays []models.ChangesPerDay
+	err = store.ListChangesPerDaysByFilter(ctx, changesFilter, &changesPerDays)
+	if err != nil {
+		return nil, fmt.Errorf("error listing changes per days: %w", err)
+	}
+
+	var incidentsPerDays []models.IncidentsPerDay
+	err = store.ListIncidentsPerDaysByFilter(ctx, incidentsFilter, &incidentsPerDays)
+	if err != nil {
+		return nil, fmt.Errorf("error listing incidents per days: %w", err)
+	}
+
+	dates, err := DatesBetween(startDate, endDate, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("error getting dates between %s and %s: %w", startDate, endDate, err)
+	}
+
+	deploymentFrequency, err := summarizeDeploymentFrequency(&pipelineRunsPerDays, dates, window, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	leadTimeForChanges, err := summarizeLeadTimeForChanges(&changesPerDays, dates, window, granularity, statistic, dataflow.LeadTimePerCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	meanTimeToRestore, err := summarizeMeanTimeToRestore(&incidentsPerDays, dates, window, granularity, statistic)
+	if err != nil {
+		return nil, err
+	}
+
+	changeFailureRate, err := summarizeChangeFailureRate(&incidentsPerDays, &pipelineRunsPerDays, dates, window, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Summary{
+		DataflowID:          dataflow.ID,
+		StartDate:           startDate,
+		EndDate:             endDate,
+		Window:              window,
+		Granularity:         granularity,
+		Statistic:           statistic,
+		Timezone:            location.String(),
+		Dates:               (*dates)[offset:],
+		DeploymentFrequency: *deploymentFrequency,
+		LeadTimeForChanges:  *leadTimeForChanges,
+		MeanTimeToRestore:   *meanTimeToRestore,
+		ChangeFailureRate:   *changeFailureRate,
+	}, nil
+}
+
+// Sum returns the sum of all totals.
+func Sum(totals []int) int {
+	sum := 0
+	for _, total := range totals {
+		sum += total
+	}
+	return sum
+}
+
+// Average returns the ratio of two totals, or 0 if the denominator is 0.
+func Average(numerator int, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return numeric.Round(float64(numerator)/float64(denominator), 2)
+}
+
+// summarizeDeploymentFrequency summarizes the deployment frequency of loaded PipelineRunsPerDays.
+func summarizeDeploymentFrequency(pipelineRunsPerDays *[]models.PipelineRunsPerDay, dates *[]time.Time, window int, granularity string) (*models.DeploymentFrequencySummary, error) {
+	offset := window - 1
+
+	dailyPipelineRuns, err := CompletePipelineRunsPerDays(pipelineRunsPerDays, dates, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("error completing pipeline runs per days: %w", err)
+	}
+
+	movingAverages, err := MovingAverages(dailyPipelineRuns, window)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+	}
+
+	totalPipelineRuns := Sum((*dailyPipelineRuns)[offset:])
+	return &models.DeploymentFrequencySummary{
+		TotalPipelineRuns: totalPipelineRuns,
+		Average:           Average(totalPipelineRuns, len(*dates)-offset),
+		DailyPipelineRuns: (*dailyPipelineRuns)[offset:],
+		MovingAverages:    *movingAverages,
+	}, nil
+}
+
+// summarizeLeadTimeForChanges summarizes the lead time for changes of loaded ChangesPerDays,
+// counting every commit deployed if perCommit is set.
+func summarizeLeadTimeForChanges(changesPerDays *[]models.ChangesPerDay, dates *[]time.Time, window int, granularity string, statistic string, perCommit bool) (*models.LeadTimeForChangesSummary, error) {
+	offset := window - 1
+
+	dailyChanges, dailyLeadTimes, err := CompleteChangesPerDays(changesPerDays, dates, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("error completing changes per days: %w", err)
+	}
+
+	// every commit deployed counts with its own lead time, hence large batches weigh more
+	var dailyCommits *[]int
+	if perCommit {
+		dailyCommits, dailyLeadTimes, err = CompleteCommitsPerDays(changesPerDays, dates, granularity)
+		if err != nil {
+			return nil, fmt.Errorf("error completing commits per days: %w", err)
+		}
+	}
+
+	movingAverages, err := MovingAverages(dailyLeadTimes, window)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+	}
+
+	summary := models.LeadTimeForChangesSummary{
+		TotalChanges:   Sum((*dailyChanges)[offset:]),
+		TotalLeadTime:  Sum((*dailyLeadTimes)[offset:]),
+		DailyChanges:   (*dailyChanges)[offset:],
+		DailyLeadTimes: (*dailyLeadTimes)[offset:],
+		MovingAverages: *movingAverages,
+	}
+	summary.Average = Average(summary.TotalLeadTime, summary.TotalChanges)
+	if dailyCommits != nil {
+		summary.DailyCommits = (*dailyCommits)[offset:]
+		summary.TotalCommits = Sum(summary.DailyCommits)
+		summary.Average = Average(summary.TotalLeadTime, summary.TotalCommits)
+	}
+
+	percentile, ok := models.Percentiles[statistic]
+	if ok {
+		dailyLeadTimesPerChange, err := CompleteLeadTimesPerDays(changesPerDays, dates, granularity, perCommit)
+		if err != nil {
+			return nil, fmt.Errorf("error completing lead times per days: %w", err)
+		}
+
+		movingPercentiles, err := MovingPercentiles(dailyLeadTimesPerChange, window, percentile)
+		if err != nil {
+			return nil, fmt.Errorf("error calculating moving percentiles: %w", err)
+		}
+		summary.MovingPercentiles = *movingPercentiles
+	}
+
+	return &summary, nil
+}
+
+// summarizeMeanTimeToRestore summarizes the mean time to restore of loaded IncidentsPerDays.
+func summarizeMeanTimeToRestore(incidentsPerDays *[]models.IncidentsPerDay, dates *[]time.Time, window int, granularity string, statistic string) (*models.MeanTimeToRestoreSummary, error) {
+	offset := window - 1
+
+	dailyIncidents, dailyDurations, err := CompleteIncidentsPerDays(incidentsPerDays, dates, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("error completing incidents per days: %w", err)
+	}
+
+	movingAverages, err := MovingAverages(dailyDurations, window)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+	}
+
+	summary := models.MeanTimeToRestoreSummary{
+		TotalIncidents: Sum((*dailyIncidents)[offset:]),
+		TotalDuration:  Sum((*dailyDurations)[offset:]),
+		DailyIncidents: (*dailyIncidents)[offset:],
+		DailyDurations: (*dailyDurations)[offset:],
+		MovingAverages: *movingAverages,
+	}
+	summary.Average = Average(summary.TotalDuration, summary.TotalIncidents)
+
+	percentile, ok := models.Percentiles[statistic]
+	if ok {
+		dailyDurationsPerIncident, err := CompleteDurationsPerDays(incidentsPerDays, dates, granularity)
+		if err != nil {
+			return nil, fmt.Errorf("error completing durations per days: %w", err)
+		}
+
+		movingPercentiles, err := MovingPercentiles(dailyDurationsPerIncident, window, percentile)
+		if err != nil {
+			return nil, fmt.Errorf("error calculating moving percentiles: %w", err)
+		}
+		summary.MovingPercentiles = *movingPercentiles
+	}
+
+	return &summary, nil
+}
+
+// summarizeChangeFailureRate summarizes the change failure rate of loaded IncidentsPerDays and PipelineRunsPerDays.
+func summarizeChangeFailureRate(incidentsPerDays *[]models.IncidentsPerDay, pipelineRunsPerDays *[]models.PipelineRunsPerDay, dates *[]time.Time, window int, granularity string) (*models.ChangeFailureRateSummary, error) {
+	offset := window - 1
+
+	dailyIncidents, _, err := CompleteIncidentsPerDays(incidentsPerDays, dates, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("error completing incidents per days: %w", err)
+	}
+
+	dailyDeployments, err := CompletePipelineRunsPerDays(pipelineRunsPerDays, dates, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("error completing pipeline runs per days: %w", err)
+	}
+
+	movingAverages, err := MovingAveragesRatio(dailyIncidents, dailyDeployments, window)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating moving averages: %w", err)
+	}
+
+	summary := models.ChangeFailureRateSummary{
+		TotalIncidents:   Sum((*dailyIncidents)[offset:]),
+		TotalDeployments: Sum((*dailyDeployments)[offset:]),
+		DailyIncidents:   (*dailyIncidents)[offset:],
+		DailyDeployments: (*dailyDeployments)[offset:],
+		MovingAverages:   *movingAverages,
+	}
+	summary.Average = Average(summary.TotalIncidents, summary.TotalDeployments)
+
+	return &summary, nil
+}
@@ -0,0 +1,141 @@
+package metrics_test
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/metrics"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("services.metrics.summary", func() {
+	ctx := context.Background()
+
+	var _ = BeforeEach(func() {
+		_ = godotenv.Load("./../../../test/.env")
+	})
+
+	var _ = AfterEach(func() {
+		ctx := context.Background()
+		service := mongodb.NewService()
+		service.Connect(ctx, os.Getenv("MONGODB_DATABASE"))
+		service.DB.Drop(ctx)
+		defer service.Disconnect(ctx)
+
+		os.Remove("MONGODB_URI")
+		os.Remove("MONGODB_PORT")
+		os.Remove("MONGODB_USER")
+		os.Remove("MONGODB_PASSWORD")
+	})
+
+	var _ = When("Summary", func() {
+		It("calculates all metrics of a dataflow at once.", func() {
+			// create a new dataflow
+			dataflow := models.Dataflow{
+				Repository: models.Repository{
+					IntegrationID:  primitive.NewObjectID(),
+					ExternalID:     40649465,
+					NamespacedName: "foobar/foobar",
+					DefaultBranch:  "main",
+				},
+				Pipeline: models.Pipeline{
+					IntegrationID:  primitive.NewObjectID(),
+					ExternalID:     40649465,
+					NamespacedName: "foobar/foobar",
+					DefaultBranch:  "main",
+				},
+				Deployment: models.Deployment{
+					IntegrationID: primitive.NewObjectID(),
+					Query:         "job:http_total_requests:internal_server_error_percentage",
+					Step:          300,
+					Relation:      "gt",
+					Threshold:     0.2,
+				},
+			}
+
+			err := store.CreateDataflow(ctx, &dataflow)
+			Expect(err).To(BeNil())
+
+			// create the daily aggregates
+			pipelineRunsPerDays := []models.PipelineRunsPerDay{
+				{Date: time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC), TotalPipelineRuns: 2},
+				{Date: time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC), TotalPipelineRuns: 4},
+			}
+			err = store.CreatePipelineRunsPerDays(ctx, dataflow.Pipeline.ID, &pipelineRunsPerDays)
+			Expect(err).To(BeNil())
+
+			changesPerDays := []models.ChangesPerDay{
+				{Date: time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC), TotalChanges: 1, TotalLeadTime: 600, LeadTimes: []float64{600}},
+				{Date: time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC), TotalChanges: 2, TotalLeadTime: 3000, LeadTimes: []float64{1200, 1800}},
+			}
+			err = store.CreateChangesPerDays(ctx, dataflow.Repository.ID, dataflow.Pipeline.ID, &changesPerDays)
+			Expect(err).To(BeNil())
+
+			incidentsPerDays := []models.IncidentsPerDay{
+				{Date: time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC), TotalIncidents: 1, TotalDuration: 900, Durations: []float64{900}},
+			}
+			err = store.CreateIncidentsPerDays(ctx, dataflow.Deployment.ID, &incidentsPerDays)
+			Expect(err).To(BeNil())
+
+			// summarize all metrics
+			startDate := time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2022, 12, 27, 23, 59, 59, 0, time.UTC)
+
+			summary, err := metrics.Summary(ctx, store, dataflow.ID, startDate, endDate, 1, models.StatisticP50, models.GranularityDay, "")
+			Expect(err).To(BeNil())
+			Expect(summary.DeploymentFrequency.TotalPipelineRuns).To(Equal(6))
+			Expect(summary.DeploymentFrequency.Average).To(Equal(3.0))
+			Expect(summary.DeploymentFrequency.MovingAverages).To(Equal([]float64{2, 4}))
+			Expect(summary.LeadTimeForChanges.TotalChanges).To(Equal(3))
+			Expect(summary.LeadTimeForChanges.Average).To(Equal(1200.0))
+			Expect(summary.LeadTimeForChanges.MovingPercentiles).To(Equal([]float64{600, 1500}))
+			Expect(summary.MeanTimeToRestore.TotalIncidents).To(Equal(1))
+			Expect(summary.MeanTimeToRestore.Average).To(Equal(900.0))
+			Expect(summary.ChangeFailureRate.TotalDeployments).To(Equal(6))
+			Expect(summary.ChangeFailureRate.MovingAverages).To(Equal([]float64{0, 0.25}))
+		})
+	})
+
+	var _ = When("CompareSummary", func() {
+		It("compares the summary of a dataflow not aggregated in UTC with the preceding period.", func() {
+			dataflow := models.Dataflow{
+				Pipeline: models.Pipeline{
+					IntegrationID:  primitive.NewObjectID(),
+					ExternalID:     40649465,
+					NamespacedName: "foobar/foobar",
+					DefaultBranch:  "main",
+				},
+				Timezone: "America/New_York",
+			}
+
+			err := store.CreateDataflow(ctx, &dataflow)
+			Expect(err).To(BeNil())
+
+			pipelineRunsPerDays := []models.PipelineRunsPerDay{
+				{Date: time.Date(2022, 12, 25, 0, 0, 0, 0, time.UTC), TotalPipelineRuns: 2},
+				{Date: time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC), TotalPipelineRuns: 4},
+			}
+			err = store.CreatePipelineRunsPerDays(ctx, dataflow.Pipeline.ID, &pipelineRunsPerDays)
+			Expect(err).To(BeNil())
+
+			newYork, err := time.LoadLocation("America/New_York")
+			Expect(err).To(BeNil())
+			startDate := time.Date(2022, 12, 26, 0, 0, 0, 0, newYork)
+
+			summary, err := metrics.Summary(ctx, store, dataflow.ID, startDate, startDate, 1, models.StatisticMean, models.GranularityDay, "")
+			Expect(err).To(BeNil())
+
+			err = metrics.CompareSummary(ctx, store, dataflow.ID, summary)
+			Expect(err).To(BeNil())
+			Expect(summary.DeploymentFrequency.Comparison.PreviousStartDate).To(Equal(time.Date(2022, 12, 25, 0, 0, 0, 0, time.UTC)))
+			Expect(summary.DeploymentFrequency.Comparison.PreviousAverage).To(Equal(2.0))
+			Expect(summary.DeploymentFrequency.Comparison.Delta).To(Equal(2.0))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/trigger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// DefaultTick is the time between two checks for Dataflows due to be synced.
+	DefaultTick = time.Minute
+	// DefaultJitter is the maximum delay of a run, as a fraction of the sync interval of its Dataflow.
+	DefaultJitter = 0.1
+)
+
+// Scheduler periodically syncs all Dataflows, each according to its own sync interval.
+type Scheduler struct {
+	Tick   time.Duration
+	Jitter float64
+
+	store   *daos.Store
+	mutex   sync.Mutex
+	pending map[primitive.ObjectID]bool // Dataflows with a run scheduled or running
+}
+
+// NewScheduler creates a new Scheduler reading and syncing the Dataflows of a Store.
+func NewScheduler(store *daos.Store) *Scheduler {
+	return &Scheduler{
+		store:   store,
+		Tick:    DefaultTick,
+		Jitter:  DefaultJitter,
+		pending: map[primitive.ObjectID]bool{},
+	}
+}
+
+// Start checks for Dataflows due to be synced on every tick, until the context is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.Tick)
+	go func() {
+		defer ticker.Stop()
+		for {
+			err := s.Schedule(ctx)
+			if err != nil {
+				log.Printf("Could not schedule dataflows: %s", err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Schedule starts a jittered run for each Dataflow due to be synced, which has no run scheduled or running yet.
+func (s *Scheduler) Schedule(ctx context.Context) error {
+	var dataflows []models.Dataflow
+	err := s.store.ListDataflows(ctx, &dataflows)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, dataflow := range dataflows {
+		// a Dataflow without a SyncState is still being onboarded by its Job
+		var syncState models.SyncState
+		err := s.store.GetSyncState(ctx, dataflow.ID, &syncState)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !Due(&dataflow, &syncState, now) || !s.reserve(dataflow.ID) {
+			continue
+		}
+
+		go s.run(ctx, dataflow, s.delay(&dataflow))
+	}
+
+	return nil
+}
+
+// Due returns true if the last run of a Dataflow is at least its sync interval ago.
+func Due(dataflow *models.Dataflow, syncState *models.SyncState, now time.Time) bool {
+	return !now.Before(syncState.LastRunAt.Add(Interval(dataflow)))
+}
+
+// Interval returns the sync interval of a Dataflow.
+func Interval(dataflow *models.Dataflow) time.Duration {
+	syncInterval := dataflow.SyncInterval
+	if syncInterval < 1 {
+		syncInterval = models.DefaultSyncInterval
+	}
+
+	return time.Duration(syncInterval) * time.Minute
+}
+
+// delay returns a random delay of up to the jitter of the sync interval of a Dataflow,
+// so that Dataflows created at the same time do not hit the providers at the same time.
+func (s *Scheduler) delay(dataflow *models.Dataflow) time.Duration {
+	maxDelay := int64(float64(Interval(dataflow)) * s.Jitter)
+	if maxDelay < 1 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(maxDelay))
+}
+
+// run syncs a Dataflow after a delay.
+func (s *Scheduler) run(ctx context.Context, dataflow models.Dataflow, delay time.Duration) {
+	defer s.release(dataflow.ID)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(delay):
+	}
+
+	err := trigger.OnSync(ctx, s.store, &dataflow)
+	if err != nil {
+		log.Printf("Could not sync dataflow %s: %s", dataflow.ID.Hex(), err.Error())
+		return
+	}
+
+	log.Printf("Synced dataflow %s", dataflow.ID.Hex())
+}
+
+// reserve marks a Dataflow as pending, and returns false if it already is.
+func (s *Scheduler) reserve(dataflowID primitive.ObjectID) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pending[dataflowID] {
+		return false
+	}
+	s.pending[dataflowID] = true
+	return true
+}
+
+// release marks a Dataflow as not pending anymore.
+func (s *Scheduler) release(dataflowID primitive.ObjectID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.pending, dataflowID)
+}
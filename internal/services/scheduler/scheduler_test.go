@@ -0,0 +1,52 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/scheduler"
+)
+
+func TestScheduler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "services.scheduler Suite")
+}
+
+var _ = Describe("services.scheduler", func() {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var _ = When("Interval", func() {
+		It("uses the sync interval of a Dataflow.", func() {
+			dataflow := models.Dataflow{SyncInterval: 15}
+			Expect(scheduler.Interval(&dataflow)).To(Equal(15 * time.Minute))
+		})
+
+		It("falls back to the default sync interval.", func() {
+			dataflow := models.Dataflow{}
+			Expect(scheduler.Interval(&dataflow)).To(Equal(models.DefaultSyncInterval * time.Minute))
+		})
+	})
+
+	var _ = When("Due", func() {
+		It("is due if the Dataflow has never run.", func() {
+			dataflow := models.Dataflow{SyncInterval: 15}
+			syncState := models.SyncState{}
+			Expect(scheduler.Due(&dataflow, &syncState, now)).To(BeTrue())
+		})
+
+		It("is due once the sync interval has passed.", func() {
+			dataflow := models.Dataflow{SyncInterval: 15}
+			syncState := models.SyncState{LastRunAt: now.Add(-15 * time.Minute)}
+			Expect(scheduler.Due(&dataflow, &syncState, now)).To(BeTrue())
+		})
+
+		It("is not due within the sync interval.", func() {
+			dataflow := models.Dataflow{SyncInterval: 15}
+			syncState := models.SyncState{LastRunAt: now.Add(-14 * time.Minute)}
+			Expect(scheduler.Due(&dataflow, &syncState, now)).To(BeFalse())
+		})
+	})
+})
@@ -2,58 +2,49 @@ package aggregate
 
 import (
 	"context"
+	"sort"
 	"time"
 
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/utils/times"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// CreateChangesPerDays creates changes per days from commits and pipeline runs.
-func CreateChangesPerDays(ctx context.Context, channel chan error, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID) {
-	changes := []models.Change{}
-	err := daos.ListChanges(ctx, repositoryID, &changes)
-	if err != nil {
-		channel <- err
-		return
-	}
-
-	if len(changes) == 0 {
-		channel <- nil
-		return
-	}
-
-	changesPerDays, err := CalculateChangesPerDays(ctx, &changes)
-	if err != nil {
-		channel <- err
-		return
-	}
-
-	err = daos.CreateChangesPerDays(ctx, repositoryID, pipelineID, changesPerDays)
-	channel <- err
-	return
-}
-
 // CalculateChangesPerDays calculates the changes per day.
+// Days are bucketed in the location given.
 // If no change is found for a date, no aggregate will be created for that date!
-func CalculateChangesPerDays(ctx context.Context, changes *[]models.Change) (*[]models.ChangesPerDay, error) {
+func CalculateChangesPerDays(ctx context.Context, changes *[]models.Change, location *time.Location) (*[]models.ChangesPerDay, error) {
 	changesPerDays := []models.ChangesPerDay{}
 
+	// the changes of a day are summed up consecutively, hence they need to be ordered by their deployment date
+	sorted := make([]models.Change, len(*changes))
+	copy(sorted, *changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DeploymentDate.Before(sorted[j].DeploymentDate)
+	})
+	changes = &sorted
+
 	date := (*changes)[0].DeploymentDate
 	var countPerDay int
 	var durationPerDay time.Duration
+	var commitsPerDay int
+	var commitLeadTimePerDay float64
+	var leadTimesPerDay []float64
+	var commitLeadTimesPerDay []float64
 
 	for index := 0; index < len(*changes); index++ {
 		newDate := (*changes)[index].DeploymentDate
 
-		if !times.SameDay(date, newDate) {
-			dayDate := times.Date(date)
+		if !times.SameDay(date, newDate, location) {
+			dayDate := times.Date(date, location)
 			changesPerDay := models.ChangesPerDay{
-				RepositoryID:  (*changes)[index].RepositoryID,
-				Date:          dayDate,
-				TotalChanges:  countPerDay,
-				TotalLeadTime: durationPerDay.Seconds(),
+				RepositoryID:        (*changes)[index].RepositoryID,
+				Date:                dayDate,
+				TotalChanges:        countPerDay,
+				TotalLeadTime:       durationPerDay.Seconds(),
+				TotalCommits:        commitsPerDay,
+				TotalCommitLeadTime: commitLeadTimePerDay,
+				LeadTimes:           leadTimesPerDay,
+				CommitLeadTimes:     commitLeadTimesPerDay,
 			}
 
 			changesPerDays = append(changesPerDays, changesPerDay)
@@ -61,18 +52,34 @@ func CalculateChangesPerDays(ctx context.Context, changes *[]models.Change) (*[]
 			date = (*changes)[index].DeploymentDate
 			countPerDay = 0
 			durationPerDay = 0
+			commitsPerDay = 0
+			commitLeadTimePerDay = 0
+			leadTimesPerDay = nil
+			commitLeadTimesPerDay = nil
 		}
 
 		countPerDay++
 		start := (*changes)[index].FirstCommitDate
 		end := (*changes)[index].DeploymentDate
 		durationPerDay += end.Sub(start)
+		leadTimesPerDay = append(leadTimesPerDay, end.Sub(start).Seconds())
+
+		// only set if the lead time of every commit is recorded
+		for _, commitLeadTime := range (*changes)[index].CommitLeadTimes {
+			commitsPerDay++
+			commitLeadTimePerDay += commitLeadTime
+			commitLeadTimesPerDay = append(commitLeadTimesPerDay, commitLeadTime)
+		}
 	}
 
 	changesPerDays = append(changesPerDays, models.ChangesPerDay{
-		Date:          times.Date(date),
-		TotalChanges:  countPerDay,
-		TotalLeadTime: durationPerDay.Seconds(),
+		Date:                times.Date(date, location),
+		TotalChanges:        countPerDay,
+		TotalLeadTime:       durationPerDay.Seconds(),
+		TotalCommits:        commitsPerDay,
+		TotalCommitLeadTime: commitLeadTimePerDay,
+		LeadTimes:           leadTimesPerDay,
+		CommitLeadTimes:     commitLeadTimesPerDay,
 	})
 
 	return &changesPerDays, nil
@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/aggregate"
@@ -62,7 +61,7 @@ var _ = Describe("services.trigger.aggregate.changes_per_days", func() {
 				},
 			}
 
-			changesPerDays, err := aggregate.CalculateChangesPerDays(ctx, &changes)
+			changesPerDays, err := aggregate.CalculateChangesPerDays(ctx, &changes, time.UTC)
 			Expect(err).To(BeNil())
 			Expect(len(*changesPerDays)).To(Equal(2))
 			Expect((*changesPerDays)[0].Date).To(Equal(time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC)))
@@ -71,10 +70,81 @@ var _ = Describe("services.trigger.aggregate.changes_per_days", func() {
 			Expect((*changesPerDays)[1].Date).To(Equal(time.Date(2022, 12, 29, 0, 0, 0, 0, time.UTC)))
 			Expect((*changesPerDays)[1].TotalChanges).To(Equal(1))
 			Expect((*changesPerDays)[1].TotalLeadTime).To(Equal(float64(1200)))
+			Expect((*changesPerDays)[0].LeadTimes).To(Equal([]float64{3600, 900}))
+			Expect((*changesPerDays)[1].LeadTimes).To(Equal([]float64{1200}))
+		})
+
+		It("sums the lead times of every commit if they are recorded.", func() {
+			repositoryID := primitive.NewObjectID()
+			pipelineID := primitive.NewObjectID()
+			changes := []models.Change{
+				{
+					RepositoryID:    repositoryID,
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 27, 14, 16, 42, 0, time.UTC),
+					LeadTime:        3600,
+					CommitLeadTimes: []float64{3600, 1800, 600},
+				},
+				{
+					RepositoryID:    repositoryID,
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 27, 17, 39, 21, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 27, 17, 54, 21, 0, time.UTC),
+					LeadTime:        900,
+					CommitLeadTimes: []float64{900},
+				},
+			}
+
+			changesPerDays, err := aggregate.CalculateChangesPerDays(ctx, &changes, time.UTC)
+			Expect(err).To(BeNil())
+			Expect(len(*changesPerDays)).To(Equal(1))
+			Expect((*changesPerDays)[0].TotalChanges).To(Equal(2))
+			Expect((*changesPerDays)[0].TotalLeadTime).To(Equal(float64(4500)))
+			Expect((*changesPerDays)[0].TotalCommits).To(Equal(4))
+			Expect((*changesPerDays)[0].TotalCommitLeadTime).To(Equal(float64(6900)))
+			Expect((*changesPerDays)[0].CommitLeadTimes).To(Equal([]float64{3600, 1800, 600, 900}))
+		})
+
+		It("sums up the Changes of a day, even if they are not ordered by their deployment date.", func() {
+			repositoryID := primitive.NewObjectID()
+			pipelineID := primitive.NewObjectID()
+			changes := []models.Change{
+				{
+					RepositoryID:    repositoryID,
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 25, 13, 16, 42, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 27, 14, 16, 42, 0, time.UTC),
+					LeadTime:        176400,
+				},
+				{
+					RepositoryID:    repositoryID,
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 26, 17, 39, 21, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 26, 17, 54, 21, 0, time.UTC),
+					LeadTime:        900,
+				},
+				{
+					RepositoryID:    repositoryID,
+					PipelineID:      pipelineID,
+					FirstCommitDate: time.Date(2022, 12, 27, 12, 16, 42, 0, time.UTC),
+					DeploymentDate:  time.Date(2022, 12, 27, 13, 16, 42, 0, time.UTC),
+					LeadTime:        3600,
+				},
+			}
+
+			changesPerDays, err := aggregate.CalculateChangesPerDays(ctx, &changes, time.UTC)
+			Expect(err).To(BeNil())
+			Expect(len(*changesPerDays)).To(Equal(2))
+			Expect((*changesPerDays)[0].Date).To(Equal(time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC)))
+			Expect((*changesPerDays)[0].TotalChanges).To(Equal(1))
+			Expect((*changesPerDays)[1].Date).To(Equal(time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC)))
+			Expect((*changesPerDays)[1].TotalChanges).To(Equal(2))
+			Expect((*changesPerDays)[1].LeadTimes).To(Equal([]float64{3600, 176400}))
 		})
 	})
 
-	var _ = When("CreateChangesPerDays", func() {
+	var _ = When("UpdateChangesPerDays of every day", func() {
 		It("creates ChangesPerDays based on Changes.", func() {
 			repositoryID := primitive.NewObjectID()
 			pipelineID := primitive.NewObjectID()
@@ -100,18 +170,18 @@ var _ = Describe("services.trigger.aggregate.changes_per_days", func() {
 			}
 
 			changes := []models.Change{change1, change2, change3}
-			err := daos.CreateChanges(ctx, repositoryID, &changes)
+			err := store.CreateChanges(ctx, repositoryID, &changes)
 			Expect(err).To(BeNil())
 
 			channel := make(chan error)
 			defer close(channel)
 
-			go aggregate.CreateChangesPerDays(ctx, channel, repositoryID, pipelineID)
+			go aggregate.UpdateChangesPerDays(ctx, store, channel, repositoryID, pipelineID, time.Time{}, time.UTC)
 			err = <-channel
 			Expect(err).To(BeNil())
 
 			var changesPerDays []models.ChangesPerDay
-			err = daos.ListChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
+			err = store.ListChangesPerDays(ctx, repositoryID, pipelineID, &changesPerDays)
 			Expect(err).To(BeNil())
 			Expect(len(changesPerDays)).To(Equal(2))
 			Expect(changesPerDays[0].Date).To(Equal(time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC)))
@@ -4,56 +4,32 @@ import (
 	"context"
 	"time"
 
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/utils/times"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// CreateIncidentsPerDays calculates and creates the incidents for each day.
-func CreateIncidentsPerDays(ctx context.Context, channel chan error, deploymentID primitive.ObjectID) {
-	var incidents []models.Incident
-	err := daos.ListIncidents(ctx, deploymentID, &incidents)
-	if err != nil {
-		channel <- err
-		return
-	}
-
-	if len(incidents) == 0 {
-		channel <- nil
-		return
-	}
-
-	incidentsPerDays, err := CalculateIncidentsPerDays(ctx, &incidents)
-	if err != nil {
-		channel <- err
-		return
-	}
-
-	err = daos.CreateIncidentsPerDays(ctx, deploymentID, incidentsPerDays)
-	channel <- err
-	return
-}
-
 // CalculateIncidentsPerDays calculates the incidents per day.
+// Days are bucketed in the location given.
 // If no incident is found for a date, no aggregate will be created for that date!
-func CalculateIncidentsPerDays(ctx context.Context, incidents *[]models.Incident) (*[]models.IncidentsPerDay, error) {
+func CalculateIncidentsPerDays(ctx context.Context, incidents *[]models.Incident, location *time.Location) (*[]models.IncidentsPerDay, error) {
 	incidentsPerDays := []models.IncidentsPerDay{}
 
 	date := (*incidents)[0].StartDate
 	var countPerDay int
 	var durationPerDay time.Duration
+	var durationsPerDay []float64
 
 	for index := 0; index < len(*incidents); index++ {
 		newDate := (*incidents)[index].StartDate
 
-		if !times.SameDay(date, newDate) {
-			dayDate := times.Date(date)
+		if !times.SameDay(date, newDate, location) {
+			dayDate := times.Date(date, location)
 			incidentsPerDay := models.IncidentsPerDay{
 				DeploymentID:   (*incidents)[index].DeploymentID,
 				Date:           dayDate,
 				TotalIncidents: countPerDay,
 				TotalDuration:  durationPerDay.Seconds(),
+				Durations:      durationsPerDay,
 			}
 
 			incidentsPerDays = append(incidentsPerDays, incidentsPerDay)
@@ -61,18 +37,21 @@ func CalculateIncidentsPerDays(ctx context.Context, incidents *[]models.Incident
 			date = (*incidents)[index].StartDate
 			countPerDay = 0
 			durationPerDay = 0
+			durationsPerDay = nil
 		}
 
 		countPerDay++
 		start := (*incidents)[index].StartDate
 		end := (*incidents)[index].EndDate
 		durationPerDay += end.Sub(start)
+		durationsPerDay = append(durationsPerDay, end.Sub(start).Seconds())
 	}
 
 	incidentsPerDays = append(incidentsPerDays, models.IncidentsPerDay{
-		Date:           times.Date(date),
+		Date:           times.Date(date, location),
 		TotalIncidents: countPerDay,
 		TotalDuration:  durationPerDay.Seconds(),
+		Durations:      durationsPerDay,
 	})
 
 	return &incidentsPerDays, nil
@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/aggregate"
@@ -55,20 +54,22 @@ var _ = Describe("services.trigger.aggregate", func() {
 				},
 			}
 
-			err := daos.CreateIncidents(ctx, &incidents)
+			err := store.CreateIncidents(ctx, &incidents)
 			Expect(err).To(BeNil())
 
-			incidentsPerDays, err := aggregate.CalculateIncidentsPerDays(ctx, &incidents)
+			incidentsPerDays, err := aggregate.CalculateIncidentsPerDays(ctx, &incidents, time.UTC)
 			Expect(err).To(BeNil())
 			Expect(len(*incidentsPerDays)).To(Equal(2))
 			Expect((*incidentsPerDays)[0].TotalIncidents).To(Equal(2))
 			Expect((*incidentsPerDays)[0].TotalDuration).To(Equal(float64(4500)))
 			Expect((*incidentsPerDays)[1].TotalIncidents).To(Equal(1))
 			Expect((*incidentsPerDays)[1].TotalDuration).To(Equal(float64(1200)))
+			Expect((*incidentsPerDays)[0].Durations).To(Equal([]float64{3600, 900}))
+			Expect((*incidentsPerDays)[1].Durations).To(Equal([]float64{1200}))
 		})
 	})
 
-	var _ = When("CreateIncidentsPerDays", func() {
+	var _ = When("UpdateIncidentsPerDays of every day", func() {
 		It("creates IncidentsPerDays based on Incidents.", func() {
 			deploymentID := primitive.NewObjectID()
 			incidents := []models.Incident{
@@ -89,18 +90,18 @@ var _ = Describe("services.trigger.aggregate", func() {
 				},
 			}
 
-			err := daos.CreateIncidents(ctx, &incidents)
+			err := store.CreateIncidents(ctx, &incidents)
 			Expect(err).To(BeNil())
 
 			channel := make(chan error)
 			defer close(channel)
 
-			go aggregate.CreateIncidentsPerDays(ctx, channel, deploymentID)
+			go aggregate.UpdateIncidentsPerDays(ctx, store, channel, deploymentID, time.Time{}, time.UTC)
 			err = <-channel
 			Expect(err).To(BeNil())
 
 			var incidentsPerDays []models.IncidentsPerDay
-			err = daos.ListIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
+			err = store.ListIncidentsPerDays(ctx, deploymentID, &incidentsPerDays)
 			Expect(err).To(BeNil())
 			Expect(incidentsPerDays).To(HaveLen(2))
 			Expect(incidentsPerDays[0].TotalIncidents).To(Equal(2))
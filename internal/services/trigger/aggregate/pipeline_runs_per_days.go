@@ -2,49 +2,33 @@ package aggregate
 
 import (
 	"context"
+	"sort"
+	"time"
 
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/utils/times"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// CreatePipelineRunsPerDays calculates and creates the pipeline runs for each day.
-func CreatePipelineRunsPerDays(ctx context.Context, channel chan error, pipelineID primitive.ObjectID) {
-	var pipelineRuns []models.PipelineRun
-	err := daos.ListPipelineRuns(ctx, pipelineID, &pipelineRuns)
-	if err != nil {
-		channel <- err
-		return
-	}
-
-	if len(pipelineRuns) == 0 {
-		channel <- nil
-		return
-	}
-
-	pipelineRunsPerDays, err := CalculatePipelineRunsPerDays(ctx, &pipelineRuns)
-	if err != nil {
-		channel <- err
-		return
-	}
-
-	err = daos.CreatePipelineRunsPerDays(ctx, pipelineID, pipelineRunsPerDays)
-	channel <- err
-	return
-}
-
 // CalculatePipelineRunsPerDays calculates the pipeline runs per day.
+// Days are bucketed in the location given.
 // If no pipeline run is found for a date, no aggregate will be created for that date!
-func CalculatePipelineRunsPerDays(ctx context.Context, pipelineRuns *[]models.PipelineRun) (*[]models.PipelineRunsPerDay, error) {
+func CalculatePipelineRunsPerDays(ctx context.Context, pipelineRuns *[]models.PipelineRun, location *time.Location) (*[]models.PipelineRunsPerDay, error) {
 	pipelineRunsPerDays := []models.PipelineRunsPerDay{}
 
+	// the pipeline runs of a day are counted consecutively, hence they need to be ordered by the date they finished
+	sorted := make([]models.PipelineRun, len(*pipelineRuns))
+	copy(sorted, *pipelineRuns)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].UpdatedAt.Before(sorted[j].UpdatedAt)
+	})
+	pipelineRuns = &sorted
+
 	date := (*pipelineRuns)[0].UpdatedAt
 	countPerDay := 0
 
 	for index := 0; index < len(*pipelineRuns); index++ {
-		if !times.SameDay(date, (*pipelineRuns)[index].UpdatedAt) {
-			dayDate := times.Date(date)
+		if !times.SameDay(date, (*pipelineRuns)[index].UpdatedAt, location) {
+			dayDate := times.Date(date, location)
 			pipelineRunsPerDay := models.PipelineRunsPerDay{
 				PipelineID:        (*pipelineRuns)[index].PipelineID,
 				Date:              dayDate,
@@ -61,7 +45,7 @@ func CalculatePipelineRunsPerDays(ctx context.Context, pipelineRuns *[]models.Pi
 	}
 
 	pipelineRunsPerDays = append(pipelineRunsPerDays, models.PipelineRunsPerDay{
-		Date:              times.Date(date),
+		Date:              times.Date(date, location),
 		TotalPipelineRuns: countPerDay,
 	})
 
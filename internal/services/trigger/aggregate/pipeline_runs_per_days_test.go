@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/aggregate"
@@ -73,15 +72,67 @@ var _ = Describe("services.trigger.aggregate", func() {
 				},
 			}
 
-			pipelineRunsPerDay, err := aggregate.CalculatePipelineRunsPerDays(ctx, &pipelineRuns)
+			pipelineRunsPerDay, err := aggregate.CalculatePipelineRunsPerDays(ctx, &pipelineRuns, time.UTC)
 			Expect(err).To(BeNil())
 			Expect(len(*pipelineRunsPerDay)).To(Equal(2))
 			Expect((*pipelineRunsPerDay)[0].TotalPipelineRuns).To(Equal(1))
 			Expect((*pipelineRunsPerDay)[1].TotalPipelineRuns).To(Equal(2))
 		})
+
+		It("buckets the pipeline runs into the days of a timezone, regardless of their offsets.", func() {
+			zurich, err := time.LoadLocation("Europe/Zurich")
+			Expect(err).To(BeNil())
+
+			pipelineID := primitive.NewObjectID()
+			pipelineRuns := []models.PipelineRun{
+				{
+					PipelineID: pipelineID,
+					UpdatedAt:  time.Date(2022, 12, 27, 16, 30, 0, 0, time.FixedZone("", -5*60*60)),
+				},
+				{
+					PipelineID: pipelineID,
+					UpdatedAt:  time.Date(2022, 12, 27, 23, 30, 0, 0, time.UTC),
+				},
+			}
+
+			pipelineRunsPerDay, err := aggregate.CalculatePipelineRunsPerDays(ctx, &pipelineRuns, zurich)
+			Expect(err).To(BeNil())
+			Expect(len(*pipelineRunsPerDay)).To(Equal(2))
+			Expect((*pipelineRunsPerDay)[0].Date).To(Equal(time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC)))
+			Expect((*pipelineRunsPerDay)[1].Date).To(Equal(time.Date(2022, 12, 28, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("counts the pipeline runs of a day, even if they are not ordered by the date they finished.", func() {
+			pipelineID := primitive.NewObjectID()
+			pipelineRuns := []models.PipelineRun{
+				{
+					PipelineID: pipelineID,
+					CreatedAt:  time.Date(2022, 12, 26, 23, 0, 0, 0, time.UTC),
+					UpdatedAt:  time.Date(2022, 12, 27, 1, 0, 0, 0, time.UTC),
+				},
+				{
+					PipelineID: pipelineID,
+					CreatedAt:  time.Date(2022, 12, 26, 23, 30, 0, 0, time.UTC),
+					UpdatedAt:  time.Date(2022, 12, 26, 23, 45, 0, 0, time.UTC),
+				},
+				{
+					PipelineID: pipelineID,
+					CreatedAt:  time.Date(2022, 12, 27, 9, 0, 0, 0, time.UTC),
+					UpdatedAt:  time.Date(2022, 12, 27, 9, 30, 0, 0, time.UTC),
+				},
+			}
+
+			pipelineRunsPerDay, err := aggregate.CalculatePipelineRunsPerDays(ctx, &pipelineRuns, time.UTC)
+			Expect(err).To(BeNil())
+			Expect(len(*pipelineRunsPerDay)).To(Equal(2))
+			Expect((*pipelineRunsPerDay)[0].Date).To(Equal(time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC)))
+			Expect((*pipelineRunsPerDay)[0].TotalPipelineRuns).To(Equal(1))
+			Expect((*pipelineRunsPerDay)[1].Date).To(Equal(time.Date(2022, 12, 27, 0, 0, 0, 0, time.UTC)))
+			Expect((*pipelineRunsPerDay)[1].TotalPipelineRuns).To(Equal(2))
+		})
 	})
 
-	var _ = When("CreatePipelineRunsPerDays", func() {
+	var _ = When("UpdatePipelineRunsPerDays of every day", func() {
 		It("calculates and creates the pipeline runs for each day.", func() {
 			pipelineID := primitive.NewObjectID()
 			pipelineRuns := []models.PipelineRun{
@@ -120,18 +171,18 @@ var _ = Describe("services.trigger.aggregate", func() {
 				},
 			}
 
-			err := daos.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
 			Expect(err).To(BeNil())
 
 			channel := make(chan error)
 			defer close(channel)
 
-			go aggregate.CreatePipelineRunsPerDays(ctx, channel, pipelineID)
+			go aggregate.UpdatePipelineRunsPerDays(ctx, store, channel, pipelineID, time.Time{}, time.UTC)
 			err = <-channel
 			Expect(err).To(BeNil())
 
 			var pipelineRunsPerDays []models.PipelineRunsPerDay
-			err = daos.ListPipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
+			err = store.ListPipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
 			Expect(err).To(BeNil())
 			Expect(pipelineRunsPerDays).To(HaveLen(2))
 		})
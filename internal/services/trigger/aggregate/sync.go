@@ -0,0 +1,160 @@
+package aggregate
+
+import (
+	"context"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
+	"github.com/unnmdnwb3/dora/internal/utils/times"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Update recomputes the aggregates per day of a Dataflow for all days affected by a sync.
+func Update(ctx context.Context, store *daos.Store, dataflow *models.Dataflow, affected *ingest.Affected) error {
+	location, err := times.Location(dataflow.Timezone)
+	if err != nil {
+		return err
+	}
+
+	cpdChannel := make(chan error)
+	defer close(cpdChannel)
+	go UpdateChangesPerDays(ctx, store, cpdChannel, dataflow.Repository.ID, dataflow.Pipeline.ID, affected.Changes, location)
+
+	ipdChannel := make(chan error)
+	defer close(ipdChannel)
+	go UpdateIncidentsPerDays(ctx, store, ipdChannel, dataflow.Deployment.ID, affected.Incidents, location)
+
+	prpdChannel := make(chan error)
+	defer close(prpdChannel)
+	go UpdatePipelineRunsPerDays(ctx, store, prpdChannel, dataflow.Pipeline.ID, affected.PipelineRuns, location)
+
+	// need to wait for each channel get a message, otherwise send on a closed channel will panic
+	cpdErr := <-cpdChannel
+	ipdErr := <-ipdChannel
+	prpdErr := <-prpdChannel
+
+	if cpdErr != nil {
+		return cpdErr
+	}
+
+	if ipdErr != nil {
+		return ipdErr
+	}
+
+	if prpdErr != nil {
+		return prpdErr
+	}
+
+	return nil
+}
+
+// UpdateChangesPerDays recreates the changes per days from the day of a given date on, bucketing days in a location.
+func UpdateChangesPerDays(ctx context.Context, store *daos.Store, channel chan error, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID, since time.Time, location *time.Location) {
+	date := times.Date(since, location)
+	start := times.Midnight(date, location)
+
+	filter := bson.M{"repository_id": repositoryID, "pipeline_id": pipelineID, "date": bson.M{"$gte": date}}
+	err := store.DeleteChangesPerDaysByFilter(ctx, filter)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	changes := []models.Change{}
+	filter = bson.M{"repository_id": repositoryID, "pipeline_id": pipelineID, "deployment_date": bson.M{"$gte": start}}
+	err = store.ListChangesByFilter(ctx, filter, &changes)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	if len(changes) == 0 {
+		channel <- nil
+		return
+	}
+
+	changesPerDays, err := CalculateChangesPerDays(ctx, &changes, location)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	err = store.CreateChangesPerDays(ctx, repositoryID, pipelineID, changesPerDays)
+	channel <- err
+	return
+}
+
+// UpdateIncidentsPerDays recreates the incidents per days from the day of a given date on, bucketing days in a location.
+func UpdateIncidentsPerDays(ctx context.Context, store *daos.Store, channel chan error, deploymentID primitive.ObjectID, since time.Time, location *time.Location) {
+	date := times.Date(since, location)
+	start := times.Midnight(date, location)
+
+	filter := bson.M{"deployment_id": deploymentID, "date": bson.M{"$gte": date}}
+	err := store.DeleteIncidentsPerDaysByFilter(ctx, filter)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	var incidents []models.Incident
+	filter = bson.M{"deployment_id": deploymentID, "start_date": bson.M{"$gte": start}}
+	err = store.ListIncidentsByFilter(ctx, filter, &incidents)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	if len(incidents) == 0 {
+		channel <- nil
+		return
+	}
+
+	incidentsPerDays, err := CalculateIncidentsPerDays(ctx, &incidents, location)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	err = store.CreateIncidentsPerDays(ctx, deploymentID, incidentsPerDays)
+	channel <- err
+	return
+}
+
+// UpdatePipelineRunsPerDays recreates the pipeline runs per days from the day of a given date on, bucketing days in a location.
+func UpdatePipelineRunsPerDays(ctx context.Context, store *daos.Store, channel chan error, pipelineID primitive.ObjectID, since time.Time, location *time.Location) {
+	date := times.Date(since, location)
+	start := times.Midnight(date, location)
+
+	filter := bson.M{"pipeline_id": pipelineID, "date": bson.M{"$gte": date}}
+	err := store.DeletePipelineRunsPerDaysByFilter(ctx, filter)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	var pipelineRuns []models.PipelineRun
+	filter = bson.M{"pipeline_id": pipelineID, "updated_at": bson.M{"$gte": start}}
+	err = store.ListPipelineRunsByFilter(ctx, filter, &pipelineRuns)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	if len(pipelineRuns) == 0 {
+		channel <- nil
+		return
+	}
+
+	pipelineRunsPerDays, err := CalculatePipelineRunsPerDays(ctx, &pipelineRuns, location)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	err = store.CreatePipelineRunsPerDays(ctx, pipelineID, pipelineRunsPerDays)
+	channel <- err
+	return
+}
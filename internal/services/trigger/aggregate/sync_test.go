@@ -0,0 +1,86 @@
+package aggregate_test
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/trigger/aggregate"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("services.trigger.aggregate.sync", func() {
+	ctx := context.Background()
+
+	var _ = BeforeEach(func() {
+		_ = godotenv.Load("./../../../../test/.env")
+	})
+
+	var _ = AfterEach(func() {
+		service := mongodb.NewService()
+		service.Connect(ctx, os.Getenv("MONGODB_DATABASE"))
+		service.DB.Drop(ctx)
+		defer service.Disconnect(ctx)
+
+		os.Remove("MONGODB_URI")
+		os.Remove("MONGODB_PORT")
+		os.Remove("MONGODB_USER")
+		os.Remove("MONGODB_PASSWORD")
+	})
+
+	var _ = When("UpdatePipelineRunsPerDays", func() {
+		It("recreates only the pipeline runs per day of the days affected.", func() {
+			pipelineID := primitive.NewObjectID()
+			pipelineRuns := []models.PipelineRun{
+				{
+					ExternalID: 713437220,
+					CreatedAt:  time.Date(2019, 10, 9, 9, 11, 20, 0, time.UTC),
+					UpdatedAt:  time.Date(2019, 10, 9, 9, 12, 20, 0, time.UTC),
+				},
+				{
+					ExternalID: 713437221,
+					CreatedAt:  time.Date(2019, 10, 11, 9, 11, 20, 0, time.UTC),
+					UpdatedAt:  time.Date(2019, 10, 11, 9, 12, 20, 0, time.UTC),
+				},
+			}
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			Expect(err).To(BeNil())
+
+			channel := make(chan error)
+			defer close(channel)
+			go aggregate.UpdatePipelineRunsPerDays(ctx, store, channel, pipelineID, time.Time{}, time.UTC)
+			err = <-channel
+			Expect(err).To(BeNil())
+
+			var oldPipelineRunsPerDays []models.PipelineRunsPerDay
+			err = store.ListPipelineRunsPerDays(ctx, pipelineID, &oldPipelineRunsPerDays)
+			Expect(err).To(BeNil())
+
+			newPipelineRuns := []models.PipelineRun{
+				{
+					ExternalID: 713437222,
+					CreatedAt:  time.Date(2019, 10, 11, 9, 13, 20, 0, time.UTC),
+					UpdatedAt:  time.Date(2019, 10, 11, 9, 14, 20, 0, time.UTC),
+				},
+			}
+			err = store.CreatePipelineRuns(ctx, pipelineID, &newPipelineRuns)
+			Expect(err).To(BeNil())
+
+			go aggregate.UpdatePipelineRunsPerDays(ctx, store, channel, pipelineID, time.Date(2019, 10, 11, 9, 12, 20, 0, time.UTC), time.UTC)
+			err = <-channel
+			Expect(err).To(BeNil())
+
+			var pipelineRunsPerDays []models.PipelineRunsPerDay
+			err = store.ListPipelineRunsPerDays(ctx, pipelineID, &pipelineRunsPerDays)
+			Expect(err).To(BeNil())
+			Expect(len(pipelineRunsPerDays)).To(Equal(2))
+			Expect(pipelineRunsPerDays[0].ID).To(Equal(oldPipelineRunsPerDays[0].ID))
+			Expect(pipelineRunsPerDays[1].TotalPipelineRuns).To(Equal(2))
+		})
+	})
+})
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"time"
 
 	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
@@ -11,112 +13,156 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// ImportChanges gets and persists advanced historical data based on raw data for each defined source in a Dataflow.
-func ImportChanges(ctx context.Context, channel chan error, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID) {
-	err := CreateChanges(ctx, repositoryID, pipelineID)
-	channel <- err
-	return
-}
+// CreateChangesOfPipelineRuns creates the changes deployed by specific pipeline runs,
+// recording the lead time of every commit deployed if perCommit is set. It returns the number of changes created.
+func CreateChangesOfPipelineRuns(ctx context.Context, store *daos.Store, repositoryID primitive.ObjectID, pipelineRuns *[]models.PipelineRun, perCommit bool) (int, error) {
+	if len(*pipelineRuns) == 0 {
+		return 0, nil
+	}
 
-// CreateChanges creates changes from commits and pipeline runs.
-func CreateChanges(ctx context.Context, repositoryID primitive.ObjectID, pipelineID primitive.ObjectID) error {
-	var pipelineRuns []models.PipelineRun
-	err := daos.ListPipelineRuns(ctx, pipelineID, &pipelineRuns)
+	newCommits, err := GetNewCommits(ctx, store, repositoryID, pipelineRuns)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	log.Println(fmt.Sprintf("Found %d pipeline runs for pipelineID %s", len(pipelineRuns), pipelineID.Hex()))
-	if len(pipelineRuns) == 0 {
-		return nil
+	// only pipeline runs deploying new commits deploy a change
+	var firstCommits []models.Commit
+	var deployingPipelineRuns []models.PipelineRun
+	var deployedCommits [][]models.Commit
+	for index, commits := range newCommits {
+		firstCommit, ok := Oldest(commits)
+		if !ok {
+			continue
+		}
+		firstCommits = append(firstCommits, firstCommit)
+		deployingPipelineRuns = append(deployingPipelineRuns, (*pipelineRuns)[index])
+		deployedCommits = append(deployedCommits, commits)
 	}
 
-	firstCommits, err := GetFirstCommits(ctx, repositoryID, &pipelineRuns)
-	if err != nil {
-		return err
-	}
+	log.Println(fmt.Sprintf("Found %d first commits for repositoryID %s", len(firstCommits), repositoryID.Hex()))
 
-	log.Println(fmt.Sprintf("Found %d first commits for repositoryID %s", len(*firstCommits), repositoryID.Hex()))
+	if len(firstCommits) == 0 {
+		return 0, nil
+	}
 
-	changes, err := CalculateChanges(ctx, firstCommits, &pipelineRuns)
+	changes, err := CalculateChanges(ctx, &firstCommits, &deployingPipelineRuns)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if perCommit {
+		for index := range *changes {
+			(*changes)[index].CommitLeadTimes = CommitLeadTimes(deployedCommits[index], deployingPipelineRuns[index])
+		}
 	}
 
-	err = daos.CreateChanges(ctx, repositoryID, changes)
+	err = store.CreateChanges(ctx, repositoryID, changes)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return len(*changes), nil
 }
 
-// GetFirstCommits returns the first commit of a change.
-func GetFirstCommits(ctx context.Context, repositoryID primitive.ObjectID, pipelineRuns *[]models.PipelineRun) (*[]models.Commit, error) {
-	// get the merge commits
-	mergeCommitShas := []string{}
-	for _, pipelineRun := range *pipelineRuns {
-		mergeCommitShas = append(mergeCommitShas, pipelineRun.Sha)
+// GetFirstCommits returns the first commit of the change deployed by each pipeline run, in the same order.
+// A pipeline run deploying no new commits, or a sha which has not been imported, gets an empty Commit.
+func GetFirstCommits(ctx context.Context, store *daos.Store, repositoryID primitive.ObjectID, pipelineRuns *[]models.PipelineRun) (*[]models.Commit, error) {
+	newCommits, err := GetNewCommits(ctx, store, repositoryID, pipelineRuns)
+	if err != nil {
+		return nil, err
 	}
 
-	var mergeCommits []models.Commit
-	filter := bson.M{
-		"repository_id": repositoryID,
-		"sha":           bson.M{"$in": mergeCommitShas},
+	firstCommits := make([]models.Commit, len(*pipelineRuns))
+	for index := range *pipelineRuns {
+		firstCommit, ok := Oldest(newCommits[index])
+		if ok {
+			firstCommits[index] = firstCommit
+		}
 	}
 
-	err := daos.ListCommitsByFilter(ctx, filter, &mergeCommits)
+	return &firstCommits, nil
+}
+
+// GetNewCommits returns the commits deployed for the first time by each pipeline run, in the same order.
+// These are all commits reachable from the sha of a pipeline run, but not from the sha deployed before,
+// hence merge, squash, rebase and fast-forward merges are handled alike. Without an earlier deployment,
+// all commits reachable from the first parent count as deployed before.
+func GetNewCommits(ctx context.Context, store *daos.Store, repositoryID primitive.ObjectID, pipelineRuns *[]models.PipelineRun) ([][]models.Commit, error) {
+	var commits []models.Commit
+	err := store.ListCommits(ctx, repositoryID, &commits)
 	if err != nil {
 		return nil, err
 	}
+	graph := NewCommitGraph(&commits)
 
-	// get the first-parent commits
-	firstParentShas := []string{}
-	for _, mergeCommit := range mergeCommits {
-		firstParentShas = append(firstParentShas, mergeCommit.ParentShas[0])
+	previousShas, err := GetPreviousShas(ctx, store, pipelineRuns)
+	if err != nil {
+		return nil, err
 	}
 
-	// get all commits between the first second-parent commit and the last merge commit
-	firstSha := firstParentShas[0]
-	lastSha := mergeCommitShas[len(mergeCommitShas)-1]
+	newCommits := make([][]models.Commit, len(*pipelineRuns))
+	for index, pipelineRun := range *pipelineRuns {
+		commit, ok := graph.Commit(pipelineRun.Sha)
+		if !ok {
+			continue
+		}
 
-	var boundaryCommits []models.Commit
-	filter = bson.M{
-		"repository_id": repositoryID,
-		"sha":           bson.M{"$in": []string{firstSha, lastSha}},
+		excluded := map[string]bool{}
+		if previousShas[index] != "" {
+			excluded = graph.Ancestors(previousShas[index])
+		} else if len(commit.ParentShas) > 0 {
+			excluded = graph.Ancestors(commit.ParentShas[0])
+		}
+
+		newCommits[index] = graph.NewCommits(pipelineRun.Sha, excluded)
 	}
-	err = daos.ListCommitsByFilter(ctx, filter, &boundaryCommits)
-	if err != nil {
-		return nil, err
+
+	return newCommits, nil
+}
+
+// GetPreviousShas returns the sha deployed before each pipeline run, in the same order.
+// The first pipeline run gets the sha of the latest run of its pipeline before, or an empty sha if there is none.
+func GetPreviousShas(ctx context.Context, store *daos.Store, pipelineRuns *[]models.PipelineRun) ([]string, error) {
+	previousShas := make([]string, len(*pipelineRuns))
+	if len(*pipelineRuns) == 0 {
+		return previousShas, nil
 	}
 
-	firstCommit, lastCommit := boundaryCommits[0], boundaryCommits[1]
+	// pipeline runs deploy at their end
+	order := make([]int, len(*pipelineRuns))
+	for index := range order {
+		order[index] = index
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return (*pipelineRuns)[order[i]].UpdatedAt.Before((*pipelineRuns)[order[j]].UpdatedAt)
+	})
 
-	var commits []models.Commit
-	filter = bson.M{
-		"repository_id": repositoryID,
-		"created_at":    bson.M{"$gte": firstCommit.CreatedAt, "$lte": lastCommit.CreatedAt},
+	first := (*pipelineRuns)[order[0]]
+	var earlierPipelineRuns []models.PipelineRun
+	filter := bson.M{
+		"pipeline_id": first.PipelineID,
+		"updated_at":  bson.M{"$lt": first.UpdatedAt},
 	}
-	err = daos.ListCommitsByFilter(ctx, filter, &commits)
+	err := store.ListPipelineRunsByFilter(ctx, filter, &earlierPipelineRuns)
 	if err != nil {
 		return nil, err
 	}
 
-	// get the first commit of each pipeline run
-	firstCommits := []models.Commit{}
-	for index := 0; index < len(firstParentShas); index++ {
-		pointer := 0
-		for pointer < len(commits) {
-			if commits[pointer].Sha == firstParentShas[index] {
-				firstCommits = append(firstCommits, commits[pointer+1])
-				pointer = pointer + 2
-				break
-			}
-			pointer++
+	previousSha := ""
+	latest := time.Time{}
+	for _, pipelineRun := range earlierPipelineRuns {
+		if pipelineRun.UpdatedAt.After(latest) {
+			previousSha = pipelineRun.Sha
+			latest = pipelineRun.UpdatedAt
 		}
 	}
 
-	return &firstCommits, nil
+	for _, index := range order {
+		previousShas[index] = previousSha
+		previousSha = (*pipelineRuns)[index].Sha
+	}
+
+	return previousShas, nil
 }
 
 // CalculateChanges calculates the changes from commits and pipeline runs.
@@ -151,3 +197,12 @@ func CalculateChanges(ctx context.Context, commits *[]models.Commit, pipelineRun
 
 	return &changes, nil
 }
+
+// CommitLeadTimes calculates the lead time of each commit deployed by a pipeline run.
+func CommitLeadTimes(commits []models.Commit, pipelineRun models.PipelineRun) []float64 {
+	leadTimes := make([]float64, len(commits))
+	for index, commit := range commits {
+		leadTimes[index] = pipelineRun.UpdatedAt.Sub(commit.CreatedAt).Seconds()
+	}
+	return leadTimes
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
@@ -62,7 +61,7 @@ var _ = Describe("services.trigger.ingest.changes", func() {
 				},
 			}
 
-			err := daos.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
 			Expect(err).To(BeNil())
 
 			repositoryID := primitive.NewObjectID()
@@ -156,10 +155,10 @@ var _ = Describe("services.trigger.ingest.changes", func() {
 				},
 			}
 
-			err = daos.CreateCommits(ctx, repositoryID, &commits)
+			err = store.CreateCommits(ctx, repositoryID, &commits)
 			Expect(err).To(BeNil())
 
-			firstCommits, err := ingest.GetFirstCommits(ctx, repositoryID, &pipelineRuns)
+			firstCommits, err := ingest.GetFirstCommits(ctx, store, repositoryID, &pipelineRuns)
 			Expect(err).To(BeNil())
 			Expect(len(*firstCommits)).To(Equal(2))
 			Expect((*firstCommits)[0].Sha).To(Equal("b9b48bcf26ab79c77e4aa4dcf28ca466bdc3b9fa"))
@@ -241,10 +240,10 @@ var _ = Describe("services.trigger.ingest.changes", func() {
 				},
 			}
 
-			err := daos.CreateCommits(ctx, repositoryID, &commits)
+			err := store.CreateCommits(ctx, repositoryID, &commits)
 			Expect(err).To(BeNil())
 
-			firstCommits, err := ingest.GetFirstCommits(ctx, repositoryID, &pipelineRuns)
+			firstCommits, err := ingest.GetFirstCommits(ctx, store, repositoryID, &pipelineRuns)
 
 			changes, err := ingest.CalculateChanges(ctx, firstCommits, &pipelineRuns)
 			Expect(err).To(BeNil())
@@ -254,7 +253,29 @@ var _ = Describe("services.trigger.ingest.changes", func() {
 		})
 	})
 
-	var _ = When("CreateChanges", func() {
+	var _ = When("CommitLeadTimes", func() {
+		It("calculates the lead time of every commit deployed.", func() {
+			pipelineRun := models.PipelineRun{
+				Sha:       "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+				UpdatedAt: time.Date(2022, 12, 28, 13, 21, 11, 0, time.UTC),
+			}
+			commits := []models.Commit{
+				{
+					CreatedAt: time.Date(2022, 12, 28, 12, 46, 21, 0, time.UTC),
+					Sha:       "487d6aedb92ab76bdc03957aceece75db906796e",
+				},
+				{
+					CreatedAt: time.Date(2022, 12, 28, 13, 01, 11, 0, time.UTC),
+					Sha:       "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+				},
+			}
+
+			leadTimes := ingest.CommitLeadTimes(commits, pipelineRun)
+			Expect(leadTimes).To(Equal([]float64{2090, 1200}))
+		})
+	})
+
+	var _ = When("CreateChangesOfPipelineRuns", func() {
 		It("creates changes from pipeline runs and commits.", func() {
 			pipelineID := primitive.NewObjectID()
 			pipelineRuns := []models.PipelineRun{
@@ -282,7 +303,7 @@ var _ = Describe("services.trigger.ingest.changes", func() {
 				},
 			}
 
-			err := daos.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
 			Expect(err).To(BeNil())
 
 			repositoryID := primitive.NewObjectID()
@@ -331,14 +352,15 @@ var _ = Describe("services.trigger.ingest.changes", func() {
 				},
 			}
 
-			err = daos.CreateCommits(ctx, repositoryID, &commits)
+			err = store.CreateCommits(ctx, repositoryID, &commits)
 			Expect(err).To(BeNil())
 
-			err = ingest.CreateChanges(ctx, repositoryID, pipelineID)
+			created, err := ingest.CreateChangesOfPipelineRuns(ctx, store, repositoryID, &pipelineRuns, false)
 			Expect(err).To(BeNil())
+			Expect(created).To(Equal(2))
 
 			var changes []models.Change
-			err = daos.ListChanges(ctx, repositoryID, &changes)
+			err = store.ListChanges(ctx, repositoryID, &changes)
 
 			Expect(len(changes)).To(Equal(2))
 			Expect(changes[0].LeadTime).To(Equal(float64(218)))
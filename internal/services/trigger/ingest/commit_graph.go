@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"sort"
+
+	"github.com/unnmdnwb3/dora/internal/models"
+)
+
+// CommitGraph links the commits of a repository to their parents.
+// Parents which have not been imported, e.g. because they are older than the backfill window, are left out.
+type CommitGraph struct {
+	commits map[string]models.Commit
+}
+
+// NewCommitGraph creates a new CommitGraph from commits.
+func NewCommitGraph(commits *[]models.Commit) *CommitGraph {
+	graph := CommitGraph{commits: map[string]models.Commit{}}
+	for _, commit := range *commits {
+		graph.commits[commit.Sha] = commit
+	}
+	return &graph
+}
+
+// Commit returns the commit with a sha, and false if it is not part of the CommitGraph.
+func (g *CommitGraph) Commit(sha string) (models.Commit, bool) {
+	commit, ok := g.commits[sha]
+	return commit, ok
+}
+
+// Ancestors returns the shas of all commits reachable from a sha, including the sha itself.
+func (g *CommitGraph) Ancestors(sha string) map[string]bool {
+	return g.walk(sha, map[string]bool{})
+}
+
+// NewCommits returns all commits reachable from a sha, but not from any of the shas excluded, oldest first.
+func (g *CommitGraph) NewCommits(sha string, excluded map[string]bool) []models.Commit {
+	commits := []models.Commit{}
+	for newSha := range g.walk(sha, excluded) {
+		commits = append(commits, g.commits[newSha])
+	}
+
+	sort.Slice(commits, func(i, j int) bool {
+		return olderThan(commits[i], commits[j])
+	})
+	return commits
+}
+
+// Oldest returns the commit created first, or false if there are no commits.
+// Commits created at the same time are ordered by sha, so that the result does not depend on their order.
+func Oldest(commits []models.Commit) (models.Commit, bool) {
+	if len(commits) == 0 {
+		return models.Commit{}, false
+	}
+
+	oldest := commits[0]
+	for _, commit := range commits[1:] {
+		if olderThan(commit, oldest) {
+			oldest = commit
+		}
+	}
+	return oldest, true
+}
+
+// olderThan returns true if a commit has been created before another one, or at the same time with a lower sha.
+func olderThan(commit models.Commit, other models.Commit) bool {
+	if commit.CreatedAt.Equal(other.CreatedAt) {
+		return commit.Sha < other.Sha
+	}
+	return commit.CreatedAt.Before(other.CreatedAt)
+}
+
+// walk returns the shas of all commits reachable from a sha without passing a sha excluded.
+func (g *CommitGraph) walk(sha string, excluded map[string]bool) map[string]bool {
+	reached := map[string]bool{}
+	stack := []string{sha}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		commit, ok := g.commits[current]
+		if !ok || reached[current] || excluded[current] {
+			continue
+		}
+		reached[current] = true
+
+		stack = append(stack, commit.ParentShas...)
+	}
+	return reached
+}
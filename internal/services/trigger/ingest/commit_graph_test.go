@@ -0,0 +1,67 @@
+package ingest_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
+)
+
+var _ = Describe("services.trigger.ingest.commit_graph", func() {
+	// main: a - b ----- e (merge of d) - f (squash of g)
+	//            \- c - d
+	commits := []models.Commit{
+		{Sha: "a", CreatedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Sha: "b", CreatedAt: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC), ParentShas: []string{"a"}},
+		{Sha: "c", CreatedAt: time.Date(2023, 1, 3, 10, 0, 0, 0, time.UTC), ParentShas: []string{"b"}},
+		{Sha: "d", CreatedAt: time.Date(2023, 1, 4, 10, 0, 0, 0, time.UTC), ParentShas: []string{"c"}},
+		{Sha: "e", CreatedAt: time.Date(2023, 1, 5, 10, 0, 0, 0, time.UTC), ParentShas: []string{"b", "d"}},
+		{Sha: "f", CreatedAt: time.Date(2023, 1, 7, 10, 0, 0, 0, time.UTC), ParentShas: []string{"e"}},
+	}
+
+	var _ = When("NewCommits", func() {
+		It("finds the commits of a merged branch.", func() {
+			graph := ingest.NewCommitGraph(&commits)
+			newCommits := graph.NewCommits("e", graph.Ancestors("b"))
+			Expect(newCommits).To(HaveLen(3))
+
+			oldest, ok := ingest.Oldest(newCommits)
+			Expect(ok).To(BeTrue())
+			Expect(oldest.Sha).To(Equal("c"))
+		})
+
+		It("finds the commits of a fast-forward merge.", func() {
+			graph := ingest.NewCommitGraph(&commits)
+			newCommits := graph.NewCommits("d", graph.Ancestors("b"))
+			Expect(newCommits).To(HaveLen(2))
+
+			oldest, ok := ingest.Oldest(newCommits)
+			Expect(ok).To(BeTrue())
+			Expect(oldest.Sha).To(Equal("c"))
+		})
+
+		It("finds the single commit of a squash merge.", func() {
+			graph := ingest.NewCommitGraph(&commits)
+			newCommits := graph.NewCommits("f", graph.Ancestors("e"))
+			Expect(newCommits).To(HaveLen(1))
+			Expect(newCommits[0].Sha).To(Equal("f"))
+		})
+
+		It("finds no commits if the same sha is deployed again.", func() {
+			graph := ingest.NewCommitGraph(&commits)
+			newCommits := graph.NewCommits("e", graph.Ancestors("e"))
+			Expect(newCommits).To(BeEmpty())
+
+			_, ok := ingest.Oldest(newCommits)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("stops at parents which have not been imported.", func() {
+			graph := ingest.NewCommitGraph(&commits)
+			newCommits := graph.NewCommits("b", map[string]bool{})
+			Expect(newCommits).To(HaveLen(2))
+		})
+	})
+})
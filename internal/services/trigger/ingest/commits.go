@@ -4,39 +4,52 @@ import (
 	"context"
 	"log"
 
-	"github.com/unnmdnwb3/dora/internal/connectors/gitlab"
+	"github.com/unnmdnwb3/dora/internal/connectors"
 	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 )
 
-// ImportCommits gets and persists historical data for each commit in a repository.
-func ImportCommits(ctx context.Context, channel chan error, repository *models.Repository) {
+// SyncCommits gets and upserts all commits of a repository newer than the high-water mark.
+func SyncCommits(ctx context.Context, store *daos.Store, channel chan error, repository *models.Repository, syncState *models.SyncState, progress *models.JobProgress) {
 	var integration models.Integration
-	err := daos.GetIntegration(ctx, repository.IntegrationID, &integration)
+	err := store.GetIntegration(ctx, repository.IntegrationID, &integration)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	client := gitlab.NewClient(integration.URI, integration.BearerToken)
+	sourceControl, err := connectors.NewSourceControl(&integration)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	commits, err := client.GetCommits(repository.ExternalID, repository.DefaultBranch)
+	// only the first import reaches back before the backfill window, later syncs overlap the previous one
+	marginDays := CommitsOverlapDays
+	if syncState.SyncedAt.IsZero() {
+		marginDays = CommitsMarginDays
+	}
+	since := syncState.LastCommitDate.AddDate(0, 0, -marginDays)
+	commits, err := sourceControl.GetCommits(repository, since)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	err = daos.CreateCommits(ctx, repository.ID, commits)
+	err = store.CreateCommits(ctx, repository.ID, commits)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	log.Printf("Created %d commits for repository %s", len(*commits), repository.NamespacedName)
+	for _, commit := range *commits {
+		if commit.CreatedAt.After(syncState.LastCommitDate) {
+			syncState.LastCommitDate = commit.CreatedAt
+		}
+	}
+
+	progress.Commits = len(*commits)
+	log.Printf("Synced %d commits for repository %s", len(*commits), repository.NamespacedName)
 
 	channel <- nil
 }
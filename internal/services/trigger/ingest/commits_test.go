@@ -6,11 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
@@ -22,6 +22,7 @@ var _ = Describe("services.ingest.commits", func() {
 	var (
 		ctx                  = context.Background()
 		gitlabRepositoryMock *httptest.Server
+		githubRepositoryMock *httptest.Server
 	)
 
 	var _ = BeforeEach(func() {
@@ -34,6 +35,14 @@ var _ = Describe("services.ingest.commits", func() {
 			json, _ := json.Marshal(commits)
 			w.Write(json)
 		}))
+
+		var githubFixture any
+		_ = test.UnmarshalFixture("./../../../../test/data/github/commits.json", &githubFixture)
+		githubRepositoryMock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			json, _ := json.Marshal(githubFixture)
+			w.Write(json)
+		}))
 	})
 
 	var _ = AfterEach(func() {
@@ -45,6 +54,7 @@ var _ = Describe("services.ingest.commits", func() {
 		defer service.Disconnect(ctx)
 
 		defer gitlabRepositoryMock.Close()
+		defer githubRepositoryMock.Close()
 
 		os.Remove("MONGODB_URI")
 		os.Remove("MONGODB_PORT")
@@ -52,7 +62,7 @@ var _ = Describe("services.ingest.commits", func() {
 		os.Remove("MONGODB_PASSWORD")
 	})
 
-	var _ = When("ImportCommits", func() {
+	var _ = When("SyncCommits", func() {
 		It("gets all Commits of a Repository and persists them.", func() {
 			integration := models.Integration{
 				ID:          primitive.NewObjectID(),
@@ -61,7 +71,7 @@ var _ = Describe("services.ingest.commits", func() {
 				URI:         gitlabRepositoryMock.URL,
 				BearerToken: "bearertoken",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 
 			repository := models.Repository{
@@ -72,17 +82,108 @@ var _ = Describe("services.ingest.commits", func() {
 				DefaultBranch:  "main",
 			}
 
+			syncState := models.SyncState{
+				LastCommitDate: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			var progress models.JobProgress
 			channel := make(chan error)
 			defer close(channel)
 
-			go ingest.ImportCommits(ctx, channel, &repository)
+			go ingest.SyncCommits(ctx, store, channel, &repository, &syncState, &progress)
 			err = <-channel
 			Expect(err).To(BeNil())
 
 			var commits []models.Commit
-			err = daos.ListCommits(ctx, repository.ID, &commits)
+			err = store.ListCommits(ctx, repository.ID, &commits)
 			Expect(len(commits)).To(Equal(10))
 			Expect(err).To(BeNil())
 		})
+
+		It("reaches back before the high-water mark only by the margin on the first import, and the overlap afterwards.", func() {
+			var requestedSince []string
+			mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestedSince = append(requestedSince, r.URL.Query().Get("since"))
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("[]"))
+			}))
+			defer mock.Close()
+
+			integration := models.Integration{
+				ID:          primitive.NewObjectID(),
+				Provider:    "gitlab",
+				Type:        "vc",
+				URI:         mock.URL,
+				BearerToken: "bearertoken",
+			}
+			err := store.CreateIntegration(ctx, &integration)
+			Expect(err).To(BeNil())
+
+			repository := models.Repository{
+				ID:             primitive.NewObjectID(),
+				IntegrationID:  integration.ID,
+				ExternalID:     15392086,
+				NamespacedName: "foobar/foobar",
+				DefaultBranch:  "main",
+			}
+
+			syncState := models.SyncState{
+				LastCommitDate: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			var progress models.JobProgress
+			channel := make(chan error)
+			defer close(channel)
+
+			go ingest.SyncCommits(ctx, store, channel, &repository, &syncState, &progress)
+			err = <-channel
+			Expect(err).To(BeNil())
+
+			syncState.SyncedAt = time.Now()
+			go ingest.SyncCommits(ctx, store, channel, &repository, &syncState, &progress)
+			err = <-channel
+			Expect(err).To(BeNil())
+
+			Expect(requestedSince).To(Equal([]string{"2022-10-02T00:00:00Z", "2022-11-24T00:00:00Z"}))
+		})
+	})
+
+	var _ = When("SyncCommits from GitHub", func() {
+		It("gets all Commits of a GitHub Repository and persists them.", func() {
+			integration := models.Integration{
+				ID:          primitive.NewObjectID(),
+				Provider:    "github",
+				Type:        "vc",
+				URI:         githubRepositoryMock.URL,
+				BearerToken: "bearertoken",
+			}
+			err := store.CreateIntegration(ctx, &integration)
+			Expect(err).To(BeNil())
+
+			repository := models.Repository{
+				ID:             primitive.NewObjectID(),
+				IntegrationID:  integration.ID,
+				ExternalID:     40649465,
+				NamespacedName: "foobar/foobar",
+				DefaultBranch:  "main",
+			}
+
+			syncState := models.SyncState{
+				LastCommitDate: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			var progress models.JobProgress
+			channel := make(chan error)
+			defer close(channel)
+
+			go ingest.SyncCommits(ctx, store, channel, &repository, &syncState, &progress)
+			err = <-channel
+			Expect(err).To(BeNil())
+
+			var commits []models.Commit
+			err = store.ListCommits(ctx, repository.ID, &commits)
+			Expect(len(commits)).To(Equal(3))
+			Expect(err).To(BeNil())
+		})
 	})
 })
@@ -2,60 +2,137 @@ package ingest
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
 	"time"
 
-	"github.com/unnmdnwb3/dora/internal/connectors/prometheus"
+	"github.com/unnmdnwb3/dora/internal/connectors"
 	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/utils/labels"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// ImportIncidents gets and persists historical data for each incident of a deployment.
-// However, this functions does not persist the raw alerts, but rather aggregates them already to incidents.
-// This is because the raw alerts are not relevant for the user, but the incidents are.
-func ImportIncidents(ctx context.Context, channel chan error, deployment *models.Deployment) {
-	alerts, err := ImportAlerts(ctx, deployment)
+// ImportAlerts gets the historical raw alert data since a given time.
+func ImportAlerts(ctx context.Context, store *daos.Store, deployment *models.Deployment, since time.Time) (*[]models.Alert, error) {
+	incidentSource, err := newIncidentSource(ctx, store, deployment)
 	if err != nil {
-		channel <- err
-		return
+		return nil, err
+	}
+
+	alerts, err := incidentSource.GetAlerts(deployment, since)
+	if err != nil {
+		return nil, fmt.Errorf("error getting alerts of query %s: %w", deployment.Query, err)
 	}
 
-	err = CreateIncidents(ctx, deployment, alerts)
-	channel <- err
-	return
+	log.Printf("Imported %d alerts", len(*alerts))
+
+	return alerts, nil
 }
 
-// ImportAlerts gets the historical raw alert data.
-func ImportAlerts(ctx context.Context, deployment *models.Deployment) (*[]models.Alert, error) {
-	var integration models.Integration
-	err := daos.GetIntegration(ctx, deployment.IntegrationID, &integration)
+// ImportProvidedIncidents gets the incidents of a deployment started since a given time from an IncidentProvider,
+// and merges them into the incidents persisted. It returns the incidents provided.
+// Incidents overlapping each other, e.g. of several alerts firing at once, are merged.
+// A provider like Alertmanager only holds the alerts still firing or resolved recently, hence incidents are only
+// added or extended, and an incident not provided anymore keeps the end it was last provided with.
+func ImportProvidedIncidents(ctx context.Context, store *daos.Store, incidentProvider connectors.IncidentProvider, deployment *models.Deployment, since time.Time) (*[]models.Incident, error) {
+	incidents, err := incidentProvider.GetIncidents(deployment, since)
+	if err != nil {
+		return nil, fmt.Errorf("error getting incidents matching %v: %w", deployment.Matchers, err)
+	}
+
+	log.Printf("Imported %d incidents", len(*incidents))
+
+	var persisted []models.Incident
+	filter := bson.M{
+		"deployment_id": deployment.ID,
+		"end_date":      bson.M{"$gte": since},
+	}
+	err = store.ListIncidentsByFilter(ctx, filter, &persisted)
 	if err != nil {
 		return nil, err
 	}
 
-	client := prometheus.NewClient(integration.URI, integration.BearerToken, deployment.Query)
-	alerts, err := client.GetAlerts()
+	all := append(append([]models.Incident{}, persisted...), *incidents...)
+	merged := MergeIncidents(&all)
+	if len(*merged) == 0 {
+		return incidents, nil
+	}
+
+	starts := map[int64]bool{}
+	for index := range *merged {
+		(*merged)[index].ID = primitive.NilObjectID
+		(*merged)[index].DeploymentID = deployment.ID
+		starts[(*merged)[index].StartDate.UnixNano()] = true
+	}
+
+	// a persisted incident extended to start earlier is replaced by the merged incident
+	for _, incident := range persisted {
+		if starts[incident.StartDate.UnixNano()] {
+			continue
+		}
+
+		err = store.DeleteIncident(ctx, incident.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = store.CreateIncidents(ctx, merged)
+	return incidents, err
+}
+
+// newIncidentSource creates the IncidentSource of the integration of a deployment.
+func newIncidentSource(ctx context.Context, store *daos.Store, deployment *models.Deployment) (connectors.IncidentSource, error) {
+	var integration models.Integration
+	err := store.GetIntegration(ctx, deployment.IntegrationID, &integration)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("Imported %d alerts", len(*alerts))
+	return connectors.NewIncidentSource(&integration)
+}
 
-	return alerts, nil
+// MergeIncidents merges all incidents overlapping each other into one, ordered by their start.
+// A merged incident keeps the series of all incidents it consists of.
+func MergeIncidents(incidents *[]models.Incident) *[]models.Incident {
+	sorted := make([]models.Incident, len(*incidents))
+	copy(sorted, *incidents)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartDate.Before(sorted[j].StartDate)
+	})
+
+	merged := []models.Incident{}
+	for _, incident := range sorted {
+		last := len(merged) - 1
+		if last >= 0 && !incident.StartDate.After(merged[last].EndDate) {
+			if incident.EndDate.After(merged[last].EndDate) {
+				merged[last].EndDate = incident.EndDate
+			}
+			merged[last].Series = labels.Union(merged[last].Series, incident.Series)
+			continue
+		}
+		merged = append(merged, incident)
+	}
+
+	return &merged
 }
 
 // CreateIncidents calculates and creates the incidents for a given deployment.
-func CreateIncidents(ctx context.Context, deployment *models.Deployment, alerts *[]models.Alert) error {
+func CreateIncidents(ctx context.Context, store *daos.Store, deployment *models.Deployment, alerts *[]models.Alert) error {
 	incidents, err := CalculateIncidents(ctx, deployment, alerts)
 	if err != nil {
 		return err
 	}
 
-	if incidents == nil {
+	// all incidents could have been dropped as flapping
+	if incidents == nil || len(*incidents) == 0 {
 		return nil
 	}
 
-	err = daos.CreateIncidents(ctx, incidents)
+	err = store.CreateIncidents(ctx, incidents)
 	if err != nil {
 		return err
 	}
@@ -65,40 +142,94 @@ func CreateIncidents(ctx context.Context, deployment *models.Deployment, alerts
 	return nil
 }
 
+// Grouping returns the gap between two alerts splitting an incident and the time an incident lasts after its last alert,
+// derived from the step of a deployment if not set.
+func Grouping(deployment *models.Deployment) (time.Duration, time.Duration) {
+	step := deployment.Step
+	if step < 1 {
+		step = models.DefaultStep
+	}
+
+	gap := time.Duration(deployment.IncidentGap) * time.Second
+	if deployment.IncidentGap < 1 {
+		gap = time.Duration(2*step) * time.Second
+	}
+
+	tailPadding := time.Duration(deployment.TailPadding) * time.Second
+	if deployment.TailPadding < 1 {
+		tailPadding = time.Duration(step) * time.Second / 2
+	}
+
+	return gap, tailPadding
+}
+
 // CalculateIncidents calculates the incidents for a given deployment.
+// Incidents are calculated for each series of alerts on its own, then overlapping incidents of different series are merged.
+// Incidents with fewer alerts or a shorter duration than the minimum of the deployment are dropped as flapping.
 func CalculateIncidents(ctx context.Context, deployment *models.Deployment, alerts *[]models.Alert) (*[]models.Incident, error) {
 	if len(*alerts) == 0 {
 		return nil, nil
 	}
 
+	alertsPerSeries := map[string][]models.Alert{}
+	keys := []string{}
+	for _, alert := range *alerts {
+		key := labels.Key(alert.Labels)
+		if _, ok := alertsPerSeries[key]; !ok {
+			keys = append(keys, key)
+		}
+		alertsPerSeries[key] = append(alertsPerSeries[key], alert)
+	}
+
+	// the series are ordered by their labels to merge them in a stable order
+	sort.Strings(keys)
+
+	incidents := []models.Incident{}
+	for _, key := range keys {
+		incidents = append(incidents, calculateSeriesIncidents(deployment, alertsPerSeries[key])...)
+	}
+
+	return MergeIncidents(&incidents), nil
+}
+
+// calculateSeriesIncidents calculates the incidents of the alerts of a single series.
+func calculateSeriesIncidents(deployment *models.Deployment, alerts []models.Alert) []models.Incident {
+	gap, tailPadding := Grouping(deployment)
+	minDuration := time.Duration(deployment.MinIncidentDuration) * time.Second
+
+	// the samples of a series are not necessarily returned in order
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.Before(alerts[j].CreatedAt)
+	})
+
+	// alerts without labels stem from a single series, which does not need to be recorded
+	var series []map[string]string
+	if len(alerts[0].Labels) > 0 {
+		series = []map[string]string{alerts[0].Labels}
+	}
+
 	incidents := []models.Incident{}
 
 	// we assume each incidents to have at least one alert
-	// if the next alert is more than 120 seconds away, we assume a new incident
-
-	start := (*alerts)[0]
-	for i := 1; i < len(*alerts); i++ {
-		prev := (*alerts)[i-1]
-		curr := (*alerts)[i]
-
-		diff := curr.CreatedAt.Sub(prev.CreatedAt).Seconds()
-		if diff > 120 {
-			incidents = append(incidents, models.Incident{
-				DeploymentID: deployment.ID,
-				StartDate:    start.CreatedAt,
-				EndDate:      prev.CreatedAt.Add(30 * time.Second), // add 30 seconds
-			})
-
-			start = curr
+	// if the next alert is more than the gap away, we assume a new incident
+	start := 0
+	for i := 1; i <= len(alerts); i++ {
+		if i < len(alerts) && alerts[i].CreatedAt.Sub(alerts[i-1].CreatedAt) <= gap {
+			continue
 		}
-	}
 
-	// add the last incident
-	incidents = append(incidents, models.Incident{
-		DeploymentID: deployment.ID,
-		StartDate:    start.CreatedAt,
-		EndDate:      (*alerts)[len(*alerts)-1].CreatedAt.Add(60 * time.Second), // add 30 seconds
-	})
+		incident := models.Incident{
+			DeploymentID: deployment.ID,
+			StartDate:    alerts[start].CreatedAt,
+			EndDate:      alerts[i-1].CreatedAt.Add(tailPadding),
+			Series:       series,
+		}
+
+		if i-start >= deployment.MinAlerts && incident.EndDate.Sub(incident.StartDate) >= minDuration {
+			incidents = append(incidents, incident)
+		}
+		start = i
+	}
 
-	return &incidents, nil
+	return incidents
 }
@@ -12,7 +12,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/unnmdnwb3/dora/internal/connectors/prometheus"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
@@ -21,6 +20,17 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// incidentProvider provides the same incidents for every deployment.
+type incidentProvider struct {
+	incidents []models.Incident
+}
+
+// GetIncidents returns a copy of the incidents provided.
+func (p *incidentProvider) GetIncidents(deployment *models.Deployment, since time.Time) (*[]models.Incident, error) {
+	incidents := append([]models.Incident{}, p.incidents...)
+	return &incidents, nil
+}
+
 var _ = Describe("services.trigger.import.incidents", func() {
 	var (
 		ctx            = context.Background()
@@ -34,7 +44,12 @@ var _ = Describe("services.trigger.import.incidents", func() {
 
 		_ = test.UnmarshalFixture("./../../../../test/data/prometheus/query.json", &queryRangeResponse)
 		prometheusMock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// only the range starting at the first sample returns any samples
 			w.WriteHeader(http.StatusOK)
+			if r.URL.Query().Get("start") != "1674486526" {
+				w.Write([]byte(`{"data": {"result": []}}`))
+				return
+			}
 			json, _ := json.Marshal(queryRangeResponse)
 			w.Write(json)
 		}))
@@ -63,25 +78,56 @@ var _ = Describe("services.trigger.import.incidents", func() {
 				URI:         prometheusMock.URL,
 				BearerToken: "bearertoken",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 
 			deployment := models.Deployment{
 				IntegrationID: integration.ID,
-				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}[6w]",
+				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}",
+				Step:          60 * 60,
 			}
 
-			alerts, err := ingest.ImportAlerts(ctx, &deployment)
+			alerts, err := ingest.ImportAlerts(ctx, store, &deployment, time.Unix(1674486526, 0))
 			Expect(err).To(BeNil())
 			Expect(len(*alerts)).To(Equal(62))
 		})
 	})
 
+	var _ = When("MergeIncidents", func() {
+		It("merges all Incidents overlapping each other.", func() {
+			incidents := []models.Incident{
+				{StartDate: time.Unix(1674551806, 0), EndDate: time.Unix(1674552106, 0)},
+				{StartDate: time.Unix(1674486526, 0), EndDate: time.Unix(1674486826, 0)},
+				{StartDate: time.Unix(1674551906, 0), EndDate: time.Unix(1674552406, 0)},
+				{StartDate: time.Unix(1674551956, 0), EndDate: time.Unix(1674552006, 0)},
+			}
+
+			merged := ingest.MergeIncidents(&incidents)
+			Expect(*merged).To(Equal([]models.Incident{
+				{StartDate: time.Unix(1674486526, 0), EndDate: time.Unix(1674486826, 0)},
+				{StartDate: time.Unix(1674551806, 0), EndDate: time.Unix(1674552406, 0)},
+			}))
+		})
+
+		It("keeps the series of all merged Incidents.", func() {
+			incidents := []models.Incident{
+				{StartDate: time.Unix(1674551806, 0), EndDate: time.Unix(1674552106, 0), Series: []map[string]string{{"pod": "api-1"}}},
+				{StartDate: time.Unix(1674551906, 0), EndDate: time.Unix(1674552406, 0), Series: []map[string]string{{"pod": "api-2"}}},
+				{StartDate: time.Unix(1674552306, 0), EndDate: time.Unix(1674552506, 0), Series: []map[string]string{{"pod": "api-1"}}},
+			}
+
+			merged := ingest.MergeIncidents(&incidents)
+			Expect(*merged).To(HaveLen(1))
+			Expect((*merged)[0].Series).To(Equal([]map[string]string{{"pod": "api-1"}, {"pod": "api-2"}}))
+		})
+	})
+
 	var _ = When("CalculateIncidents", func() {
 		It("calculates Incidents based on Alerts.", func() {
 			deployment := models.Deployment{
 				IntegrationID: primitive.NewObjectID(),
-				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}[6w]",
+				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}",
+				Step:          60,
 			}
 
 			alerts := []models.Alert{
@@ -110,6 +156,103 @@ var _ = Describe("services.trigger.import.incidents", func() {
 			Expect(len(*incidents)).To(Equal(3))
 			Expect((*incidents)[1].StartDate).To(Equal(time.Unix(1674551806, 0)))
 			Expect((*incidents)[1].EndDate).To(Equal(time.Unix(1674551956, 0)))
+			Expect((*incidents)[2].EndDate).To(Equal(time.Unix(1674728236, 0)))
+		})
+
+		It("groups Alerts with the gap and tail padding of the Deployment.", func() {
+			deployment := models.Deployment{
+				Step:        60,
+				IncidentGap: 24 * 60 * 60,
+				TailPadding: 300,
+			}
+
+			alerts := []models.Alert{
+				{CreatedAt: time.Unix(1674486526, 0)},
+				{CreatedAt: time.Unix(1674551806, 0)},
+				{CreatedAt: time.Unix(1674728206, 0)},
+			}
+
+			incidents, err := ingest.CalculateIncidents(ctx, &deployment, &alerts)
+			Expect(err).To(BeNil())
+			Expect(len(*incidents)).To(Equal(2))
+			Expect((*incidents)[0].EndDate).To(Equal(time.Unix(1674552106, 0)))
+		})
+
+		It("calculates Incidents for each series, then merges them.", func() {
+			deployment := models.Deployment{
+				IntegrationID: primitive.NewObjectID(),
+				Query:         "sum by (pod) (rate(http_requests_total{code=~'5..'}[5m]))",
+				Step:          60,
+			}
+
+			api1 := map[string]string{"pod": "api-1"}
+			api2 := map[string]string{"pod": "api-2"}
+			api3 := map[string]string{"pod": "api-3"}
+
+			// the samples of both series are interleaved and out of order, but each series on its own is continuous
+			alerts := []models.Alert{
+				{CreatedAt: time.Unix(1674486646, 0), Labels: api1},
+				{CreatedAt: time.Unix(1674486526, 0), Labels: api2},
+				{CreatedAt: time.Unix(1674486526, 0), Labels: api1},
+				{CreatedAt: time.Unix(1674486706, 0), Labels: api2},
+				{CreatedAt: time.Unix(1674486586, 0), Labels: api1},
+				{CreatedAt: time.Unix(1674486646, 0), Labels: api2},
+				{CreatedAt: time.Unix(1674486586, 0), Labels: api2},
+				{CreatedAt: time.Unix(1674551806, 0), Labels: api3},
+			}
+
+			incidents, err := ingest.CalculateIncidents(ctx, &deployment, &alerts)
+			Expect(err).To(BeNil())
+			Expect(*incidents).To(Equal([]models.Incident{
+				{
+					DeploymentID: deployment.ID,
+					StartDate:    time.Unix(1674486526, 0),
+					EndDate:      time.Unix(1674486736, 0),
+					Series:       []map[string]string{api1, api2},
+				},
+				{
+					DeploymentID: deployment.ID,
+					StartDate:    time.Unix(1674551806, 0),
+					EndDate:      time.Unix(1674551836, 0),
+					Series:       []map[string]string{api3},
+				},
+			}))
+		})
+
+		It("drops flapping Incidents with too few Alerts or too short a duration.", func() {
+			deployment := models.Deployment{
+				Step:                60,
+				MinAlerts:           2,
+				MinIncidentDuration: 120,
+			}
+
+			alerts := []models.Alert{
+				{CreatedAt: time.Unix(1674486526, 0)},
+				{CreatedAt: time.Unix(1674486586, 0)},
+				{CreatedAt: time.Unix(1674551806, 0)},
+				{CreatedAt: time.Unix(1674551866, 0)},
+				{CreatedAt: time.Unix(1674551926, 0)},
+				{CreatedAt: time.Unix(1674728206, 0)},
+			}
+
+			incidents, err := ingest.CalculateIncidents(ctx, &deployment, &alerts)
+			Expect(err).To(BeNil())
+			Expect(len(*incidents)).To(Equal(1))
+			Expect((*incidents)[0].StartDate).To(Equal(time.Unix(1674551806, 0)))
+		})
+	})
+
+	var _ = When("Grouping", func() {
+		It("derives the gap and tail padding from the step.", func() {
+			gap, tailPadding := ingest.Grouping(&models.Deployment{Step: 300})
+			Expect(gap).To(Equal(10 * time.Minute))
+			Expect(tailPadding).To(Equal(150 * time.Second))
+		})
+
+		It("falls back to the default step.", func() {
+			gap, tailPadding := ingest.Grouping(&models.Deployment{})
+			Expect(gap).To(Equal(120 * time.Second))
+			Expect(tailPadding).To(Equal(30 * time.Second))
 		})
 	})
 
@@ -117,7 +260,8 @@ var _ = Describe("services.trigger.import.incidents", func() {
 		It("creates Incidents based on Alerts.", func() {
 			deployment := models.Deployment{
 				IntegrationID: primitive.NewObjectID(),
-				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}[6w]",
+				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}",
+				Step:          60,
 			}
 
 			alerts := []models.Alert{
@@ -141,18 +285,18 @@ var _ = Describe("services.trigger.import.incidents", func() {
 				},
 			}
 
-			err := ingest.CreateIncidents(ctx, &deployment, &alerts)
+			err := ingest.CreateIncidents(ctx, store, &deployment, &alerts)
 			Expect(err).To(BeNil())
 
 			var incidents []models.Incident
-			err = daos.ListIncidentsByFilter(ctx, bson.M{"deployment_id": deployment.ID}, &incidents)
+			err = store.ListIncidentsByFilter(ctx, bson.M{"deployment_id": deployment.ID}, &incidents)
 			Expect(incidents).To(HaveLen(3))
 			Expect(incidents[1].StartDate).To(Equal(time.Unix(1674551806, 0).UTC()))
 			Expect(incidents[1].EndDate).To(Equal(time.Unix(1674551956, 0).UTC()))
 		})
 	})
 
-	var _ = When("ImportIncidents", func() {
+	var _ = When("SyncIncidents", func() {
 		It("gets all Incidents of a Deployment and persists them.", func() {
 			integration := models.Integration{
 				ID:          primitive.NewObjectID(),
@@ -161,25 +305,83 @@ var _ = Describe("services.trigger.import.incidents", func() {
 				URI:         prometheusMock.URL,
 				BearerToken: "bearertoken",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 
 			deployment := models.Deployment{
 				IntegrationID: integration.ID,
-				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}[6w]",
+				Query:         "ALERTS{alertname='TargetDown', job='ak-core/log-processor-service'}",
+				Step:          60 * 60,
 			}
 
-			channel := make(chan error)
-			defer close(channel)
+			syncState := models.SyncState{
+				LastAlertDate: time.Unix(1674486526, 0),
+			}
 
-			go ingest.ImportIncidents(ctx, channel, &deployment)
-			err = <-channel
+			var progress models.JobProgress
+			_, err = ingest.SyncIncidents(ctx, store, &deployment, &syncState, &progress)
 			Expect(err).To(BeNil())
 
 			var incidents []models.Incident
-			err = daos.ListIncidents(ctx, deployment.ID, &incidents)
+			err = store.ListIncidents(ctx, deployment.ID, &incidents)
 			Expect(err).To(BeNil())
 			Expect(len(incidents)).To(Equal(23))
 		})
 	})
+
+	var _ = When("ImportProvidedIncidents", func() {
+		It("keeps the Incidents not provided anymore, and adds the Incidents provided.", func() {
+			deployment := models.Deployment{ID: primitive.NewObjectID()}
+			since := time.Date(2023, 1, 23, 0, 0, 0, 0, time.UTC)
+
+			// the first Incident started before, and the second one is not provided anymore
+			incidents := []models.Incident{
+				{DeploymentID: deployment.ID, StartDate: since.Add(-time.Hour), EndDate: since.Add(-time.Minute)},
+				{DeploymentID: deployment.ID, StartDate: since.Add(time.Hour), EndDate: since.Add(2 * time.Hour)},
+			}
+			err := store.CreateIncidents(ctx, &incidents)
+			Expect(err).To(BeNil())
+
+			provider := incidentProvider{incidents: []models.Incident{
+				{StartDate: since.Add(3 * time.Hour), EndDate: since.Add(4 * time.Hour)},
+			}}
+			_, err = ingest.ImportProvidedIncidents(ctx, store, &provider, &deployment, since)
+			Expect(err).To(BeNil())
+
+			var findIncidents []models.Incident
+			err = store.ListIncidents(ctx, deployment.ID, &findIncidents)
+			Expect(err).To(BeNil())
+			Expect(findIncidents).To(HaveLen(3))
+			Expect(findIncidents[0].StartDate).To(Equal(incidents[0].StartDate))
+			Expect(findIncidents[1].StartDate).To(Equal(incidents[1].StartDate))
+			Expect(findIncidents[1].EndDate).To(Equal(incidents[1].EndDate))
+			Expect(findIncidents[2].StartDate).To(Equal(since.Add(3 * time.Hour)))
+		})
+
+		It("extends the Incidents persisted by the Incidents provided overlapping them.", func() {
+			deployment := models.Deployment{ID: primitive.NewObjectID()}
+			since := time.Date(2023, 1, 23, 0, 0, 0, 0, time.UTC)
+
+			// the Incident was still ongoing at the last sync
+			incidents := []models.Incident{
+				{DeploymentID: deployment.ID, StartDate: since.Add(time.Hour), EndDate: since.Add(2 * time.Hour)},
+			}
+			err := store.CreateIncidents(ctx, &incidents)
+			Expect(err).To(BeNil())
+
+			provider := incidentProvider{incidents: []models.Incident{
+				{StartDate: since.Add(30 * time.Minute), EndDate: since.Add(90 * time.Minute)},
+				{StartDate: since.Add(time.Hour), EndDate: since.Add(3 * time.Hour)},
+			}}
+			_, err = ingest.ImportProvidedIncidents(ctx, store, &provider, &deployment, since)
+			Expect(err).To(BeNil())
+
+			var findIncidents []models.Incident
+			err = store.ListIncidents(ctx, deployment.ID, &findIncidents)
+			Expect(err).To(BeNil())
+			Expect(findIncidents).To(HaveLen(1))
+			Expect(findIncidents[0].StartDate).To(Equal(since.Add(30 * time.Minute)))
+			Expect(findIncidents[0].EndDate).To(Equal(since.Add(3 * time.Hour)))
+		})
+	})
 })
@@ -1,59 +1,33 @@
 package ingest
 
 import (
-	"context"
+	"time"
 
 	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/utils/times"
 )
 
-// All gets and persists historical data for each defined source in a Dataflow.
-func All(ctx context.Context, dataflow *models.Dataflow) error {
-	err := Raw(ctx, dataflow)
-	if err != nil {
-		return err
-	}
-
-	err = Advanced(ctx, dataflow)
-	return err
-}
+// CommitsMarginDays is the number of days commits are imported before the backfill window,
+// because a pipeline run could depend on commits older than the window itself.
+const CommitsMarginDays = 60
 
-// Raw gets and persists raw historical data for each defined source in a Dataflow.
-func Raw(ctx context.Context, dataflow *models.Dataflow) error {
-	commitsChannel := make(chan error)
-	defer close(commitsChannel)
-	go ImportCommits(ctx, commitsChannel, &dataflow.Repository)
+// CommitsOverlapDays is the number of days commits are fetched again before the high-water mark,
+// because commits are filtered by their commit date, which can be older than the date they were pushed.
+const CommitsOverlapDays = 7
 
-	pipelineRunsChannel := make(chan error)
-	defer close(pipelineRunsChannel)
-	go ImportPipelineRuns(ctx, pipelineRunsChannel, &dataflow.Pipeline)
-
-	// need to wait for each channel get a message, otherwise send on a closed channel will panic
-	commitsErr := <-commitsChannel
-	pipelineRunsErr := <-pipelineRunsChannel
-
-	if commitsErr != nil {
-		return commitsErr
+// Since returns the start of the backfill window of a Dataflow.
+func Since(dataflow *models.Dataflow) time.Time {
+	backfillDays := dataflow.BackfillDays
+	if backfillDays < 1 {
+		backfillDays = models.DefaultBackfillDays
 	}
 
-	return pipelineRunsErr
-}
-
-// Advanced gets and persists advanced historical data based on raw data for each defined source in a Dataflow.
-func Advanced(ctx context.Context, dataflow *models.Dataflow) error {
-	changesChannel := make(chan error)
-	defer close(changesChannel)
-	go ImportChanges(ctx, changesChannel, dataflow.Repository.ID, dataflow.Pipeline.ID)
-
-	incidentsChannel := make(chan error)
-	defer close(incidentsChannel)
-	go ImportIncidents(ctx, incidentsChannel, &dataflow.Deployment)
-
-	// need to wait for each channel get a message, otherwise send on a closed channel will panic
-	changesErr := <-changesChannel
-	incidentsErr := <-incidentsChannel
-
-	if changesErr != nil {
-		return changesErr
+	// the timezone is validated when the Dataflow is created, otherwise fall back to UTC
+	location, err := times.Location(dataflow.Timezone)
+	if err != nil {
+		location = time.UTC
 	}
-	return incidentsErr
+
+	date := times.Date(time.Now().AddDate(0, 0, -backfillDays), location)
+	return times.Midnight(date, location)
 }
@@ -0,0 +1,167 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/unnmdnwb3/dora/internal/connectors"
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SyncPullRequests gets and upserts all merged pull requests of a repository newer than the high-water mark,
+// together with the date of their first commit.
+func SyncPullRequests(ctx context.Context, store *daos.Store, channel chan error, repository *models.Repository, syncState *models.SyncState, progress *models.JobProgress) {
+	var integration models.Integration
+	err := store.GetIntegration(ctx, repository.IntegrationID, &integration)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	sourceControl, err := connectors.NewSourceControl(&integration)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	pullRequests, err := sourceControl.GetPullRequests(repository, syncState.LastPullRequestDate)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	for index := range *pullRequests {
+		pullRequest := &(*pullRequests)[index]
+
+		commits, err := sourceControl.GetPullRequestCommits(repository, pullRequest)
+		if err != nil {
+			channel <- err
+			return
+		}
+
+		// a pull request without commits starts when it is opened
+		pullRequest.FirstCommitDate = pullRequest.CreatedAt
+		firstCommit, ok := Oldest(*commits)
+		if ok {
+			pullRequest.FirstCommitDate = firstCommit.CreatedAt
+		}
+	}
+
+	err = store.CreatePullRequests(ctx, repository.ID, pullRequests)
+	if err != nil {
+		channel <- err
+		return
+	}
+
+	for _, pullRequest := range *pullRequests {
+		if pullRequest.UpdatedAt.After(syncState.LastPullRequestDate) {
+			syncState.LastPullRequestDate = pullRequest.UpdatedAt
+		}
+	}
+
+	progress.PullRequests = len(*pullRequests)
+	log.Printf("Synced %d pull requests for repository %s", len(*pullRequests), repository.NamespacedName)
+
+	channel <- nil
+}
+
+// CreateChangesOfMergeRequests creates the changes deployed by specific pipeline runs, each starting at the
+// first commit of the merge requests whose merge commit it deploys for the first time. A change without
+// merge requests, e.g. a direct push, starts at its oldest new commit like in CreateChangesOfPipelineRuns.
+// The lead time of every commit deployed is recorded if perCommit is set. It returns the number of changes created.
+func CreateChangesOfMergeRequests(ctx context.Context, store *daos.Store, repositoryID primitive.ObjectID, pipelineRuns *[]models.PipelineRun, perCommit bool) (int, error) {
+	if len(*pipelineRuns) == 0 {
+		return 0, nil
+	}
+
+	newCommits, err := GetNewCommits(ctx, store, repositoryID, pipelineRuns)
+	if err != nil {
+		return 0, err
+	}
+
+	newShas := []string{}
+	for _, commits := range newCommits {
+		for _, commit := range commits {
+			newShas = append(newShas, commit.Sha)
+		}
+	}
+
+	var pullRequests []models.PullRequest
+	filter := bson.M{
+		"repository_id":    repositoryID,
+		"merge_commit_sha": bson.M{"$in": newShas},
+	}
+	err = store.ListPullRequestsByFilter(ctx, filter, &pullRequests)
+	if err != nil {
+		return 0, err
+	}
+
+	pullRequestsBySha := map[string][]models.PullRequest{}
+	for _, pullRequest := range pullRequests {
+		pullRequestsBySha[pullRequest.MergeCommitSha] = append(pullRequestsBySha[pullRequest.MergeCommitSha], pullRequest)
+	}
+
+	changes := []models.Change{}
+	for index, pipelineRun := range *pipelineRuns {
+		firstCommit, ok := Oldest(newCommits[index])
+		if !ok {
+			continue
+		}
+
+		change := CalculateChangeOfMergeRequests(firstCommit, pipelineRun, newCommits[index], pullRequestsBySha)
+		change.RepositoryID = repositoryID
+		if perCommit {
+			change.CommitLeadTimes = CommitLeadTimes(newCommits[index], pipelineRun)
+		}
+		changes = append(changes, change)
+	}
+
+	log.Println(fmt.Sprintf("Found %d changes for repositoryID %s", len(changes), repositoryID.Hex()))
+
+	if len(changes) == 0 {
+		return 0, nil
+	}
+
+	err = store.CreateChanges(ctx, repositoryID, &changes)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(changes), nil
+}
+
+// CalculateChangeOfMergeRequests calculates the change deployed by a pipeline run from the merge requests
+// of its new commits, or from its oldest new commit if none of them is the merge commit of a merge request.
+func CalculateChangeOfMergeRequests(firstCommit models.Commit, pipelineRun models.PipelineRun, newCommits []models.Commit, pullRequestsBySha map[string][]models.PullRequest) models.Change {
+	start := firstCommit.CreatedAt
+	mergeRequests := []models.MergeRequestReference{}
+	for _, commit := range newCommits {
+		for _, pullRequest := range pullRequestsBySha[commit.Sha] {
+			if len(mergeRequests) == 0 || pullRequest.FirstCommitDate.Before(start) {
+				start = pullRequest.FirstCommitDate
+			}
+			mergeRequests = append(mergeRequests, models.MergeRequestReference{
+				IID:       pullRequest.IID,
+				Reference: pullRequest.Reference,
+				WebURL:    pullRequest.WebURL,
+			})
+		}
+	}
+
+	end := pipelineRun.UpdatedAt
+	change := models.Change{
+		PipelineID:      pipelineRun.PipelineID,
+		FirstCommitDate: start,
+		DeploymentDate:  end,
+		LeadTime:        end.Sub(start).Seconds(),
+	}
+	if len(mergeRequests) > 0 {
+		change.MergeRequests = mergeRequests
+	}
+
+	return change
+}
@@ -0,0 +1,120 @@
+package ingest_test
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("services.trigger.ingest.merge_requests", func() {
+	ctx := context.Background()
+
+	var _ = When("CalculateChangeOfMergeRequests", func() {
+		squashCommit := models.Commit{
+			Sha:        "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+			CreatedAt:  time.Date(2022, 12, 28, 13, 1, 11, 0, time.UTC),
+			ParentShas: []string{"3d95fe3bf954501d3832e50fdd803c5f9eae3f94"},
+		}
+		pipelineRun := models.PipelineRun{
+			Sha:       squashCommit.Sha,
+			UpdatedAt: time.Date(2022, 12, 28, 13, 5, 11, 0, time.UTC),
+		}
+
+		It("starts a change at the first commit of its merge request.", func() {
+			pullRequestsBySha := map[string][]models.PullRequest{
+				squashCommit.Sha: {
+					{
+						IID:             2,
+						Reference:       "!2",
+						MergeCommitSha:  squashCommit.Sha,
+						FirstCommitDate: time.Date(2022, 12, 28, 12, 5, 11, 0, time.UTC),
+					},
+				},
+			}
+
+			change := ingest.CalculateChangeOfMergeRequests(squashCommit, pipelineRun, []models.Commit{squashCommit}, pullRequestsBySha)
+			Expect(change.LeadTime).To(Equal(float64(3600)))
+			Expect(change.MergeRequests).To(HaveLen(1))
+			Expect(change.MergeRequests[0].Reference).To(Equal("!2"))
+		})
+
+		It("starts a change without merge requests at its first commit.", func() {
+			change := ingest.CalculateChangeOfMergeRequests(squashCommit, pipelineRun, []models.Commit{squashCommit}, map[string][]models.PullRequest{})
+			Expect(change.LeadTime).To(Equal(float64(240)))
+			Expect(change.MergeRequests).To(BeEmpty())
+		})
+	})
+
+	var _ = When("CreateChangesOfMergeRequests", func() {
+		var _ = BeforeEach(func() {
+			_ = godotenv.Load("./../../../../test/.env")
+		})
+
+		var _ = AfterEach(func() {
+			service := mongodb.NewService()
+			service.Connect(ctx, os.Getenv("MONGODB_DATABASE"))
+			service.DB.Drop(ctx)
+			defer service.Disconnect(ctx)
+		})
+
+		It("creates changes from pipeline runs and merge requests.", func() {
+			pipelineID := primitive.NewObjectID()
+			pipelineRuns := []models.PipelineRun{
+				{
+					PipelineID: pipelineID,
+					ExternalID: 713437229,
+					Sha:        "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+					UpdatedAt:  time.Date(2022, 12, 28, 13, 5, 11, 0, time.UTC),
+				},
+			}
+			err := store.CreatePipelineRuns(ctx, pipelineID, &pipelineRuns)
+			Expect(err).To(BeNil())
+
+			repositoryID := primitive.NewObjectID()
+			commits := []models.Commit{
+				{
+					CreatedAt: time.Date(2022, 12, 28, 12, 21, 5, 0, time.UTC),
+					Sha:       "3d95fe3bf954501d3832e50fdd803c5f9eae3f94",
+				},
+				{
+					CreatedAt:  time.Date(2022, 12, 28, 13, 1, 11, 0, time.UTC),
+					Sha:        "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+					ParentShas: []string{"3d95fe3bf954501d3832e50fdd803c5f9eae3f94"},
+				},
+			}
+			err = store.CreateCommits(ctx, repositoryID, &commits)
+			Expect(err).To(BeNil())
+
+			pullRequests := []models.PullRequest{
+				{
+					ID:              118601409,
+					IID:             2,
+					Reference:       "!2",
+					MergeCommitSha:  "1db209656ad1ab0e14aaa4e2fe79b6caf8b2a9e7",
+					FirstCommitDate: time.Date(2022, 12, 28, 12, 5, 11, 0, time.UTC),
+				},
+			}
+			err = store.CreatePullRequests(ctx, repositoryID, &pullRequests)
+			Expect(err).To(BeNil())
+
+			created, err := ingest.CreateChangesOfMergeRequests(ctx, store, repositoryID, &pipelineRuns, false)
+			Expect(err).To(BeNil())
+			Expect(created).To(Equal(1))
+
+			var changes []models.Change
+			err = store.ListChanges(ctx, repositoryID, &changes)
+			Expect(err).To(BeNil())
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].LeadTime).To(Equal(float64(3600)))
+			Expect(changes[0].MergeRequests[0].IID).To(Equal(2))
+		})
+	})
+})
@@ -4,40 +4,46 @@ import (
 	"context"
 	"log"
 
-	"github.com/unnmdnwb3/dora/internal/connectors/gitlab"
+	"github.com/unnmdnwb3/dora/internal/connectors"
 	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 )
 
-// ImportPipelineRuns gets and persists historical data for each run of a pipeline.
-func ImportPipelineRuns(ctx context.Context, channel chan error, pipeline *models.Pipeline) {
+// SyncPipelineRuns gets and upserts all runs of a pipeline newer than the high-water mark.
+func SyncPipelineRuns(ctx context.Context, store *daos.Store, channel chan error, pipeline *models.Pipeline, syncState *models.SyncState, progress *models.JobProgress) {
 	var integration models.Integration
-	err := daos.GetIntegration(ctx, pipeline.IntegrationID, &integration)
+	err := store.GetIntegration(ctx, pipeline.IntegrationID, &integration)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	client := gitlab.NewClient(integration.URI, integration.BearerToken)
+	ciProvider, err := connectors.NewCIProvider(&integration)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	pipelineRuns, err := client.GetPipelineRuns(pipeline.ExternalID, pipeline.DefaultBranch)
+	pipelineRuns, err := ciProvider.GetPipelineRuns(pipeline, syncState.LastPipelineRunDate)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	err = daos.CreatePipelineRuns(ctx, pipeline.ID, pipelineRuns)
+	err = store.CreatePipelineRuns(ctx, pipeline.ID, pipelineRuns)
 	if err != nil {
 		channel <- err
 		return
 	}
 
-	log.Printf("Created %d pipeline runs for pipeline %s", len(*pipelineRuns), pipeline.NamespacedName)
+	for _, pipelineRun := range *pipelineRuns {
+		if pipelineRun.UpdatedAt.After(syncState.LastPipelineRunDate) {
+			syncState.LastPipelineRunDate = pipelineRun.UpdatedAt
+		}
+	}
+
+	progress.PipelineRuns = len(*pipelineRuns)
+	log.Printf("Synced %d pipeline runs for pipeline %s", len(*pipelineRuns), pipeline.NamespacedName)
 
 	channel <- nil
-	return
 }
@@ -6,11 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/database/mongodb"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
@@ -22,6 +22,7 @@ var _ = Describe("services.ingest.pipeline_runs", func() {
 	var (
 		ctx                = context.Background()
 		gitlabPipelineMock *httptest.Server
+		githubPipelineMock *httptest.Server
 	)
 
 	var _ = BeforeEach(func() {
@@ -34,6 +35,14 @@ var _ = Describe("services.ingest.pipeline_runs", func() {
 			json, _ := json.Marshal(pipelineRuns)
 			w.Write(json)
 		}))
+
+		var githubFixture any
+		_ = test.UnmarshalFixture("./../../../../test/data/github/pipeline_runs.json", &githubFixture)
+		githubPipelineMock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			json, _ := json.Marshal(githubFixture)
+			w.Write(json)
+		}))
 	})
 
 	var _ = AfterEach(func() {
@@ -45,6 +54,7 @@ var _ = Describe("services.ingest.pipeline_runs", func() {
 		defer service.Disconnect(ctx)
 
 		defer gitlabPipelineMock.Close()
+		defer githubPipelineMock.Close()
 
 		os.Remove("MONGODB_URI")
 		os.Remove("MONGODB_PORT")
@@ -52,7 +62,7 @@ var _ = Describe("services.ingest.pipeline_runs", func() {
 		os.Remove("MONGODB_PASSWORD")
 	})
 
-	var _ = When("ImportPipelineRuns", func() {
+	var _ = When("SyncPipelineRuns", func() {
 		It("gets all PipelineRuns of a Pipeline and persists them.", func() {
 			integration := models.Integration{
 				ID:          primitive.NewObjectID(),
@@ -61,7 +71,7 @@ var _ = Describe("services.ingest.pipeline_runs", func() {
 				URI:         gitlabPipelineMock.URL,
 				BearerToken: "bearertoken",
 			}
-			err := daos.CreateIntegration(ctx, &integration)
+			err := store.CreateIntegration(ctx, &integration)
 			Expect(err).To(BeNil())
 
 			pipeline := models.Pipeline{
@@ -72,17 +82,61 @@ var _ = Describe("services.ingest.pipeline_runs", func() {
 				DefaultBranch:  "main",
 			}
 
+			syncState := models.SyncState{
+				LastPipelineRunDate: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			var progress models.JobProgress
 			channel := make(chan error)
 			defer close(channel)
 
-			go ingest.ImportPipelineRuns(ctx, channel, &pipeline)
+			go ingest.SyncPipelineRuns(ctx, store, channel, &pipeline, &syncState, &progress)
 			err = <-channel
 			Expect(err).To(BeNil())
 
 			var pipelineRuns []models.PipelineRun
-			err = daos.ListPipelineRuns(ctx, pipeline.ID, &pipelineRuns)
+			err = store.ListPipelineRuns(ctx, pipeline.ID, &pipelineRuns)
 			Expect(len(pipelineRuns)).To(Equal(4))
 			Expect(err).To(BeNil())
 		})
 	})
+
+	var _ = When("SyncPipelineRuns from GitHub", func() {
+		It("gets all workflow runs of a GitHub Repository and persists them.", func() {
+			integration := models.Integration{
+				ID:          primitive.NewObjectID(),
+				Provider:    "github",
+				Type:        "cicd",
+				URI:         githubPipelineMock.URL,
+				BearerToken: "bearertoken",
+			}
+			err := store.CreateIntegration(ctx, &integration)
+			Expect(err).To(BeNil())
+
+			pipeline := models.Pipeline{
+				ID:             primitive.NewObjectID(),
+				IntegrationID:  integration.ID,
+				ExternalID:     40649465,
+				NamespacedName: "foobar/foobar",
+				DefaultBranch:  "main",
+			}
+
+			syncState := models.SyncState{
+				LastPipelineRunDate: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			var progress models.JobProgress
+			channel := make(chan error)
+			defer close(channel)
+
+			go ingest.SyncPipelineRuns(ctx, store, channel, &pipeline, &syncState, &progress)
+			err = <-channel
+			Expect(err).To(BeNil())
+
+			var pipelineRuns []models.PipelineRun
+			err = store.ListPipelineRuns(ctx, pipeline.ID, &pipelineRuns)
+			Expect(len(pipelineRuns)).To(Equal(3))
+			Expect(err).To(BeNil())
+		})
+	})
 })
@@ -0,0 +1,196 @@
+package ingest
+
+import (
+	"context"
+	"time"
+
+	"github.com/unnmdnwb3/dora/internal/connectors"
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Affected holds the earliest date of each kind of data touched by a sync,
+// from which on the aggregates need to be recomputed.
+type Affected struct {
+	Changes      time.Time
+	PipelineRuns time.Time
+	Incidents    time.Time
+}
+
+// NewSyncState creates the SyncState of a Dataflow which has never been synced,
+// so that the first sync imports the whole backfill window.
+func NewSyncState(dataflow *models.Dataflow) *models.SyncState {
+	since := Since(dataflow)
+
+	return &models.SyncState{
+		DataflowID:          dataflow.ID,
+		LastCommitDate:      since,
+		LastPipelineRunDate: since,
+		LastAlertDate:       since,
+		LastPullRequestDate: since,
+	}
+}
+
+// SyncRaw gets and persists the raw data of a Dataflow newer than the high-water marks of its SyncState.
+func SyncRaw(ctx context.Context, store *daos.Store, dataflow *models.Dataflow, syncState *models.SyncState, progress *models.JobProgress) error {
+	commitsChannel := make(chan error)
+	defer close(commitsChannel)
+	go SyncCommits(ctx, store, commitsChannel, &dataflow.Repository, syncState, progress)
+
+	pipelineRunsChannel := make(chan error)
+	defer close(pipelineRunsChannel)
+	go SyncPipelineRuns(ctx, store, pipelineRunsChannel, &dataflow.Pipeline, syncState, progress)
+
+	// pull requests are only needed to find the start of a change by its merge requests
+	var pullRequestsChannel chan error
+	if dataflow.LeadTimeStrategy == models.LeadTimeMergeRequests {
+		// a Dataflow switched to merge requests has no high-water mark for them yet
+		if syncState.LastPullRequestDate.IsZero() {
+			syncState.LastPullRequestDate = Since(dataflow)
+		}
+
+		pullRequestsChannel = make(chan error)
+		defer close(pullRequestsChannel)
+		go SyncPullRequests(ctx, store, pullRequestsChannel, &dataflow.Repository, syncState, progress)
+	}
+
+	// need to wait for each channel get a message, otherwise send on a closed channel will panic
+	commitsErr := <-commitsChannel
+	pipelineRunsErr := <-pipelineRunsChannel
+	var pullRequestsErr error
+	if pullRequestsChannel != nil {
+		pullRequestsErr = <-pullRequestsChannel
+	}
+
+	if commitsErr != nil {
+		return commitsErr
+	}
+
+	if pipelineRunsErr != nil {
+		return pipelineRunsErr
+	}
+
+	return pullRequestsErr
+}
+
+// SyncAdvanced gets and persists the advanced data of a Dataflow based on the raw data
+// of all pipeline runs updated after a given date, which is the high-water mark before SyncRaw.
+func SyncAdvanced(ctx context.Context, store *daos.Store, dataflow *models.Dataflow, syncState *models.SyncState, lastPipelineRunDate time.Time, progress *models.JobProgress) (*Affected, error) {
+	affected := Affected{
+		Changes:      lastPipelineRunDate,
+		PipelineRuns: lastPipelineRunDate,
+	}
+
+	// only pipeline runs newer than the previous high-water mark deploy new changes
+	var pipelineRuns []models.PipelineRun
+	filter := bson.M{
+		"pipeline_id": dataflow.Pipeline.ID,
+		"updated_at":  bson.M{"$gt": lastPipelineRunDate},
+	}
+	err := store.ListPipelineRunsByFilter(ctx, filter, &pipelineRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	// a change is deployed at the end of its pipeline run, hence changes of a failed sync can be replaced
+	filter = bson.M{
+		"pipeline_id":     dataflow.Pipeline.ID,
+		"deployment_date": bson.M{"$gt": lastPipelineRunDate},
+	}
+	err = store.DeleteChangesByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes int
+	switch dataflow.LeadTimeStrategy {
+	case models.LeadTimeMergeRequests:
+		changes, err = CreateChangesOfMergeRequests(ctx, store, dataflow.Repository.ID, &pipelineRuns, dataflow.LeadTimePerCommit)
+	default:
+		changes, err = CreateChangesOfPipelineRuns(ctx, store, dataflow.Repository.ID, &pipelineRuns, dataflow.LeadTimePerCommit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	progress.Changes = changes
+
+	incidentsSince, err := SyncIncidents(ctx, store, &dataflow.Deployment, syncState, progress)
+	if err != nil {
+		return nil, err
+	}
+	affected.Incidents = incidentsSince
+
+	return &affected, nil
+}
+
+// SyncIncidents gets all alerts of a deployment newer than the high-water mark and recreates the incidents affected.
+// An incident still ongoing at the high-water mark could be continued by newer alerts,
+// hence the alerts are fetched again from the start of that incident. It returns the earliest date affected.
+func SyncIncidents(ctx context.Context, store *daos.Store, deployment *models.Deployment, syncState *models.SyncState, progress *models.JobProgress) (time.Time, error) {
+	since := syncState.LastAlertDate
+
+	var incidents []models.Incident
+	filter := bson.M{
+		"deployment_id": deployment.ID,
+		"end_date":      bson.M{"$gte": since},
+	}
+	err := store.ListIncidentsByFilter(ctx, filter, &incidents)
+	if err != nil {
+		return since, err
+	}
+
+	if len(incidents) > 0 && incidents[0].StartDate.Before(since) {
+		since = incidents[0].StartDate
+	}
+
+	incidentSource, err := newIncidentSource(ctx, store, deployment)
+	if err != nil {
+		return since, err
+	}
+
+	// provided incidents are merged into the incidents persisted, as the provider could not hold them anymore
+	incidentProvider, ok := incidentSource.(connectors.IncidentProvider)
+	if ok {
+		providedIncidents, err := ImportProvidedIncidents(ctx, store, incidentProvider, deployment, since)
+		if err != nil {
+			return since, err
+		}
+		progress.Alerts = len(*providedIncidents)
+
+		for _, incident := range *providedIncidents {
+			if incident.EndDate.After(syncState.LastAlertDate) {
+				syncState.LastAlertDate = incident.EndDate
+			}
+		}
+		return since, nil
+	}
+
+	alerts, err := ImportAlerts(ctx, store, deployment, since)
+	if err != nil {
+		return since, err
+	}
+	progress.Alerts = len(*alerts)
+
+	filter = bson.M{
+		"deployment_id": deployment.ID,
+		"start_date":    bson.M{"$gte": since},
+	}
+	err = store.DeleteIncidentsByFilter(ctx, filter)
+	if err != nil {
+		return since, err
+	}
+
+	err = CreateIncidents(ctx, store, deployment, alerts)
+	if err != nil {
+		return since, err
+	}
+
+	for _, alert := range *alerts {
+		if alert.CreatedAt.After(syncState.LastAlertDate) {
+			syncState.LastAlertDate = alert.CreatedAt
+		}
+	}
+
+	return since, nil
+}
@@ -0,0 +1,111 @@
+package ingest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/models"
+	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
+	"github.com/unnmdnwb3/dora/test"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("services.ingest.sync", func() {
+	var (
+		ctx          = context.Background()
+		pipelineMock *httptest.Server
+	)
+
+	var _ = BeforeEach(func() {
+		_ = godotenv.Load("./../../../../test/.env")
+
+		var pipelineRuns []models.PipelineRun
+		_ = test.UnmarshalFixture("./../../../../test/data/gitlab/pipeline_runs.json", &pipelineRuns)
+		pipelineMock = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			json, _ := json.Marshal(pipelineRuns)
+			w.Write(json)
+		}))
+	})
+
+	var _ = AfterEach(func() {
+		ctx := context.Background()
+
+		service := mongodb.NewService()
+		service.Connect(ctx, os.Getenv("MONGODB_DATABASE"))
+		service.DB.Drop(ctx)
+		defer service.Disconnect(ctx)
+
+		defer pipelineMock.Close()
+
+		os.Remove("MONGODB_URI")
+		os.Remove("MONGODB_PORT")
+		os.Remove("MONGODB_USER")
+		os.Remove("MONGODB_PASSWORD")
+	})
+
+	var _ = When("NewSyncState", func() {
+		It("starts at the backfill window of a Dataflow.", func() {
+			dataflow := models.Dataflow{
+				ID:           primitive.NewObjectID(),
+				BackfillDays: 180,
+			}
+
+			syncState := ingest.NewSyncState(&dataflow)
+			Expect(syncState.DataflowID).To(Equal(dataflow.ID))
+			Expect(syncState.LastPipelineRunDate).To(Equal(ingest.Since(&dataflow)))
+			Expect(syncState.LastPipelineRunDate.Before(time.Now().AddDate(0, 0, -179))).To(BeTrue())
+		})
+	})
+
+	var _ = When("SyncPipelineRuns", func() {
+		It("upserts the PipelineRuns and advances the high-water mark.", func() {
+			integration := models.Integration{
+				ID:          primitive.NewObjectID(),
+				Provider:    "gitlab",
+				Type:        "cicd",
+				URI:         pipelineMock.URL,
+				BearerToken: "bearertoken",
+			}
+			err := store.CreateIntegration(ctx, &integration)
+			Expect(err).To(BeNil())
+
+			pipeline := models.Pipeline{
+				ID:             primitive.NewObjectID(),
+				IntegrationID:  integration.ID,
+				ExternalID:     15392086,
+				NamespacedName: "foobar/foobar",
+				DefaultBranch:  "main",
+			}
+			syncState := models.SyncState{
+				LastPipelineRunDate: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			var progress models.JobProgress
+			channel := make(chan error)
+			defer close(channel)
+
+			// syncing twice must not duplicate any PipelineRun
+			for i := 0; i < 2; i++ {
+				go ingest.SyncPipelineRuns(ctx, store, channel, &pipeline, &syncState, &progress)
+				err = <-channel
+				Expect(err).To(BeNil())
+			}
+
+			var pipelineRuns []models.PipelineRun
+			err = store.ListPipelineRuns(ctx, pipeline.ID, &pipelineRuns)
+			Expect(err).To(BeNil())
+			Expect(len(pipelineRuns)).To(Equal(4))
+			Expect(syncState.LastPipelineRunDate).To(Equal(time.Date(2022, 10, 20, 21, 18, 49, 621000000, time.UTC)))
+			Expect(progress.PipelineRuns).To(Equal(4))
+		})
+	})
+})
@@ -2,20 +2,162 @@ package trigger
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/unnmdnwb3/dora/internal/daos"
 	"github.com/unnmdnwb3/dora/internal/models"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/aggregate"
 	"github.com/unnmdnwb3/dora/internal/services/trigger/ingest"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// OnNewDataflow gets the historical data, creates the necessary aggregates
-// and sets up webhooks for new events from the provided sources
-func OnNewDataflow(ctx context.Context, dataflow *models.Dataflow) error {
-	err := ingest.All(ctx, dataflow)
+// ErrSyncRunning is returned if a Dataflow is already being synced.
+var ErrSyncRunning = fmt.Errorf("dataflow is already being synced")
+
+// running holds the Dataflows currently being synced, so that a Dataflow never has two runs in parallel
+var running = struct {
+	sync.Mutex
+	dataflowIDs map[primitive.ObjectID]bool
+}{dataflowIDs: map[primitive.ObjectID]bool{}}
+
+// lock marks a Dataflow as being synced, and returns false if it already is.
+func lock(dataflowID primitive.ObjectID) bool {
+	running.Lock()
+	defer running.Unlock()
+
+	if running.dataflowIDs[dataflowID] {
+		return false
+	}
+	running.dataflowIDs[dataflowID] = true
+	return true
+}
+
+// unlock marks a Dataflow as not being synced anymore.
+func unlock(dataflowID primitive.ObjectID) {
+	running.Lock()
+	defer running.Unlock()
+
+	delete(running.dataflowIDs, dataflowID)
+}
+
+// OnNewDataflow gets the historical data and creates the necessary aggregates for the provided sources,
+// recording the phase and progress in a Job. As a new Dataflow has no SyncState yet,
+// this is the first sync over the whole backfill window. A Job syncing a Dataflow on request
+// runs it as well, and only gets the data newer than the high-water marks.
+func OnNewDataflow(ctx context.Context, store *daos.Store, dataflow *models.Dataflow, job *models.Job) error {
+	return onSync(ctx, store, dataflow, job)
+}
+
+// OnSync gets all data of a Dataflow newer than its high-water marks
+// and recomputes only the aggregates of the days affected.
+// The time and error of the run are recorded in the SyncState of the Dataflow.
+func OnSync(ctx context.Context, store *daos.Store, dataflow *models.Dataflow) error {
+	return onSync(ctx, store, dataflow, &models.Job{})
+}
+
+// OnUpdate updates a Dataflow, and recomputes all of its aggregates if its timezone has changed,
+// as the days are bucketed in it. A Dataflow being synced is not updated.
+func OnUpdate(ctx context.Context, store *daos.Store, dataflowID primitive.ObjectID, dataflow *models.Dataflow) error {
+	if !lock(dataflowID) {
+		return ErrSyncRunning
+	}
+	defer unlock(dataflowID)
+
+	var previous models.Dataflow
+	err := store.GetDataflow(ctx, dataflowID, &previous)
 	if err != nil {
 		return err
 	}
 
-	err = aggregate.All(ctx, dataflow)
+	err = store.UpdateDataflow(ctx, dataflowID, dataflow)
+	if err != nil {
+		return err
+	}
+
+	if dataflow.Timezone == previous.Timezone {
+		return nil
+	}
+
+	// without any affected date, the aggregates of every day are recomputed
+	return aggregate.Update(ctx, store, dataflow, &ingest.Affected{})
+}
+
+// onSync syncs a Dataflow and records the phase and progress in a Job, if the Job has been persisted.
+func onSync(ctx context.Context, store *daos.Store, dataflow *models.Dataflow, job *models.Job) error {
+	if !lock(dataflow.ID) {
+		return ErrSyncRunning
+	}
+	defer unlock(dataflow.ID)
+
+	var syncState models.SyncState
+	err := store.GetSyncState(ctx, dataflow.ID, &syncState)
+	if err == mongo.ErrNoDocuments {
+		syncState = *ingest.NewSyncState(dataflow)
+	} else if err != nil {
+		return err
+	}
+
+	// the high-water marks only advance if the whole run succeeds
+	previous := syncState
+	err = syncDataflow(ctx, store, dataflow, &syncState, job)
+	if err != nil {
+		syncState = previous
+		syncState.LastError = err.Error()
+	} else {
+		syncState.SyncedAt = time.Now()
+		syncState.LastError = ""
+	}
+	syncState.LastRunAt = time.Now()
+
+	upsertErr := store.UpsertSyncState(ctx, dataflow.ID, &syncState)
+	if err != nil {
+		return err
+	}
+	return upsertErr
+}
+
+// syncDataflow ingests all data newer than the high-water marks of a SyncState and updates the aggregates affected.
+func syncDataflow(ctx context.Context, store *daos.Store, dataflow *models.Dataflow, syncState *models.SyncState, job *models.Job) error {
+	lastPipelineRunDate := syncState.LastPipelineRunDate
+
+	err := enterPhase(ctx, store, job, models.JobPhaseRaw)
+	if err != nil {
+		return err
+	}
+
+	err = ingest.SyncRaw(ctx, store, dataflow, syncState, &job.Progress)
+	if err != nil {
+		return err
+	}
+
+	err = enterPhase(ctx, store, job, models.JobPhaseAdvanced)
+	if err != nil {
+		return err
+	}
+
+	affected, err := ingest.SyncAdvanced(ctx, store, dataflow, syncState, lastPipelineRunDate, &job.Progress)
+	if err != nil {
+		return err
+	}
+
+	err = enterPhase(ctx, store, job, models.JobPhaseAggregate)
+	if err != nil {
+		return err
+	}
+
+	err = aggregate.Update(ctx, store, dataflow, affected)
 	return err
 }
+
+// enterPhase records the next phase and the progress so far in a Job, if the Job has been persisted.
+func enterPhase(ctx context.Context, store *daos.Store, job *models.Job, phase string) error {
+	job.Phase = phase
+	if job.ID.IsZero() {
+		return nil
+	}
+
+	return store.UpdateJob(ctx, job.ID, job)
+}
@@ -0,0 +1,33 @@
+package labels
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Key returns a key identifying a series by its labels, independent of their order.
+func Key(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Union returns the label sets of two lists of series, each label set only once.
+// Without any label sets, the union is nil.
+func Union(series []map[string]string, other []map[string]string) []map[string]string {
+	keys := map[string]bool{}
+	var union []map[string]string
+	for _, labels := range append(append([]map[string]string{}, series...), other...) {
+		key := Key(labels)
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+		union = append(union, labels)
+	}
+	return union
+}
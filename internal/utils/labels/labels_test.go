@@ -0,0 +1,43 @@
+package labels_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/unnmdnwb3/dora/internal/utils/labels"
+)
+
+func TestLabels(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "labels Suite")
+}
+
+var _ = Describe("utils.labels", func() {
+	var _ = When("Key", func() {
+		It("identifies a series by its labels.", func() {
+			key := labels.Key(map[string]string{"pod": "api-1", "region": "eu"})
+			Expect(key).To(Equal(`pod="api-1",region="eu"`))
+			Expect(labels.Key(map[string]string{"region": "eu", "pod": "api-1"})).To(Equal(key))
+			Expect(labels.Key(map[string]string{"pod": "api-2", "region": "eu"})).ToNot(Equal(key))
+		})
+
+		It("returns an empty key without labels.", func() {
+			Expect(labels.Key(nil)).To(Equal(""))
+		})
+	})
+
+	var _ = When("Union", func() {
+		It("contains each label set only once.", func() {
+			series := []map[string]string{{"pod": "api-1"}, {"pod": "api-2"}}
+			other := []map[string]string{{"pod": "api-2"}, {"pod": "api-3"}}
+			union := labels.Union(series, other)
+			Expect(union).To(Equal([]map[string]string{{"pod": "api-1"}, {"pod": "api-2"}, {"pod": "api-3"}}))
+			Expect(len(series)).To(Equal(2))
+		})
+
+		It("returns nil without label sets.", func() {
+			Expect(labels.Union(nil, nil)).To(BeNil())
+		})
+	})
+})
@@ -1,9 +1,56 @@
 package numeric
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // Round rounds a float64 to a given precision.
 func Round(val float64, precision int) float64 {
 	ratio := math.Pow(10, float64(precision))
 	return math.Round(val*ratio) / ratio
 }
+
+// Percentile calculates a percentile between 0 and 100 of values, interpolating linearly between the closest ranks.
+// If no values are given, the percentile is 0.
+func Percentile(values []float64, percentile float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// Slope calculates the slope of the least squares line through points, e.g. of a daily series.
+// With less than two distinct x values, the slope is 0.
+func Slope(xs []float64, ys []float64) float64 {
+	if len(xs) < 2 || len(xs) != len(ys) {
+		return 0
+	}
+
+	var meanX, meanY float64
+	for index := range xs {
+		meanX += xs[index]
+		meanY += ys[index]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var covariance, variance float64
+	for index := range xs {
+		covariance += (xs[index] - meanX) * (ys[index] - meanY)
+		variance += (xs[index] - meanX) * (xs[index] - meanX)
+	}
+
+	if variance == 0 {
+		return 0
+	}
+	return covariance / variance
+}
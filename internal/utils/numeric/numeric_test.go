@@ -22,4 +22,35 @@ var _ = Describe("utils.numeric", func() {
 			Expect(rounded).To(Equal(1.23))
 		})
 	})
+
+	var _ = When("Percentile", func() {
+		It("interpolates between the closest ranks.", func() {
+			values := []float64{15, 20, 35, 40, 50}
+			Expect(numeric.Percentile(values, 50)).To(Equal(35.0))
+			Expect(numeric.Percentile(values, 90)).To(Equal(46.0))
+			Expect(numeric.Percentile(values, 100)).To(Equal(50.0))
+		})
+
+		It("does not depend on the order of values.", func() {
+			values := []float64{40, 15, 50, 35, 20}
+			Expect(numeric.Percentile(values, 75)).To(Equal(40.0))
+			Expect(values).To(Equal([]float64{40, 15, 50, 35, 20}))
+		})
+
+		It("returns 0 without values.", func() {
+			Expect(numeric.Percentile([]float64{}, 95)).To(Equal(0.0))
+		})
+	})
+
+	var _ = When("Slope", func() {
+		It("calculates the slope of the least squares line.", func() {
+			xs := []float64{0, 1, 2, 3}
+			ys := []float64{1, 3, 5, 7}
+			Expect(numeric.Slope(xs, ys)).To(Equal(2.0))
+		})
+
+		It("returns 0 with less than two points.", func() {
+			Expect(numeric.Slope([]float64{1}, []float64{5})).To(Equal(0.0))
+		})
+	})
 })
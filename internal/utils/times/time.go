@@ -1,12 +1,49 @@
 package times
 
 import (
+	"fmt"
 	"time"
+
+	// embeds the IANA timezone database, since the runtime image might not provide one
+	_ "time/tzdata"
 )
 
-// Date returns a time with the time set to 00:00:00.
-func Date(t time.Time) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.UTC().Location())
+// Location returns the IANA timezone of a name, or UTC if no name is given.
+func Location(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone: %s", name)
+	}
+	return location, nil
+}
+
+// Date returns the date of a time in a location, as a time in UTC with the time set to 00:00:00.
+// Times of any offset are thereby bucketed into the same days.
+func Date(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Midnight returns the instant a date starts in a location.
+func Midnight(date time.Time, location *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+}
+
+// Week returns the Monday of the ISO week of a time in a location, with the time set to 00:00:00.
+func Week(t time.Time, location *time.Location) time.Time {
+	date := Date(t, location)
+	daysSinceMonday := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -daysSinceMonday)
+}
+
+// Month returns the first day of the month of a time in a location, with the time set to 00:00:00.
+func Month(t time.Time, location *time.Location) time.Time {
+	date := Date(t, location)
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
 }
 
 // Day returns the day of a date in the format YYYY-MM-DD.
@@ -14,7 +51,7 @@ func Day(date time.Time) string {
 	return date.Format("2006-01-02")
 }
 
-// SameDay returns true if the two times are on the same day.
-func SameDay(t1, t2 time.Time) bool {
-	return t1.Year() == t2.Year() && t1.YearDay() == t2.YearDay()
+// SameDay returns true if the two times are on the same day in a location.
+func SameDay(t1, t2 time.Time, location *time.Location) bool {
+	return Date(t1, location).Equal(Date(t2, location))
 }
@@ -25,7 +25,7 @@ var _ = Describe("utils.time", func() {
 			t2, err := time.Parse(time.RFC3339, ts2)
 			Expect(err).To(BeNil())
 
-			same := times.SameDay(t1, t2)
+			same := times.SameDay(t1, t2, time.UTC)
 			Expect(same).To(BeTrue())
 		})
 
@@ -38,18 +38,54 @@ var _ = Describe("utils.time", func() {
 			t2, err := time.Parse(time.RFC3339, ts2)
 			Expect(err).To(BeNil())
 
-			same := times.SameDay(t1, t2)
+			same := times.SameDay(t1, t2, time.UTC)
 			Expect(same).To(BeFalse())
 		})
 	})
 
+	var _ = When("SameDay", func() {
+		It("returns false if the times are on different days in the location.", func() {
+			zurich, err := times.Location("Europe/Zurich")
+			Expect(err).To(BeNil())
+
+			t1 := time.Date(2022, 12, 27, 23, 30, 0, 0, time.UTC)
+			t2 := time.Date(2022, 12, 27, 22, 30, 0, 0, time.UTC)
+
+			Expect(times.SameDay(t1, t2, time.UTC)).To(BeTrue())
+			Expect(times.SameDay(t1, t2, zurich)).To(BeFalse())
+		})
+	})
+
+	var _ = When("Location", func() {
+		It("returns UTC if no timezone is given.", func() {
+			location, err := times.Location("")
+			Expect(err).To(BeNil())
+			Expect(location).To(Equal(time.UTC))
+		})
+
+		It("returns an error for an unknown timezone.", func() {
+			_, err := times.Location("Europe/Atlantis")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
 	var _ = When("Date", func() {
+		It("returns the day of a time in a location, regardless of its offset.", func() {
+			zurich, err := times.Location("Europe/Zurich")
+			Expect(err).To(BeNil())
+
+			t := time.Date(2022, 12, 27, 23, 30, 0, 0, time.FixedZone("", -5*60*60))
+
+			d := times.Date(t, zurich)
+			Expect(d).To(Equal(time.Date(2022, 12, 28, 0, 0, 0, 0, time.UTC)))
+		})
+
 		It("returns a time with the time set to 00:00:00.", func() {
 			ts := "2019-10-09T09:11:20.861Z"
 			t, err := time.Parse(time.RFC3339, ts)
 			Expect(err).To(BeNil())
 
-			d := times.Date(t)
+			d := times.Date(t, time.UTC)
 			Expect(d.Year()).To(Equal(2019))
 			Expect(d.Month()).To(Equal(time.October))
 			Expect(d.Day()).To(Equal(9))
@@ -60,6 +96,41 @@ var _ = Describe("utils.time", func() {
 		})
 	})
 
+	var _ = When("Week", func() {
+		It("returns the Monday of the ISO week.", func() {
+			t := time.Date(2023, 1, 1, 9, 11, 20, 0, time.UTC)
+
+			w := times.Week(t, time.UTC)
+			Expect(w).To(Equal(time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("returns the same day for a Monday.", func() {
+			t := time.Date(2022, 12, 26, 23, 59, 59, 0, time.UTC)
+
+			w := times.Week(t, time.UTC)
+			Expect(w).To(Equal(time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	var _ = When("Midnight", func() {
+		It("returns the instant a date starts in the location.", func() {
+			zurich, err := times.Location("Europe/Zurich")
+			Expect(err).To(BeNil())
+
+			m := times.Midnight(time.Date(2022, 12, 28, 0, 0, 0, 0, time.UTC), zurich)
+			Expect(m.UTC()).To(Equal(time.Date(2022, 12, 27, 23, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	var _ = When("Month", func() {
+		It("returns the first day of the month.", func() {
+			t := time.Date(2022, 12, 27, 9, 11, 20, 0, time.UTC)
+
+			m := times.Month(t, time.UTC)
+			Expect(m).To(Equal(time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)))
+		})
+	})
+
 	var _ = When("Day", func() {
 		It("returns the day of a date in the format YYYY-MM-DD.", func() {
 			ts := "2019-10-09T09:11:20.861Z"
@@ -2,39 +2,76 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/unnmdnwb3/dora/internal/api"
-	"github.com/unnmdnwb3/dora/internal/database/mongodb"
+	"github.com/unnmdnwb3/dora/internal/daos"
+	"github.com/unnmdnwb3/dora/internal/database/migrations"
+	"github.com/unnmdnwb3/dora/internal/services/jobs"
+	"github.com/unnmdnwb3/dora/internal/services/scheduler"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	service := mongodb.NewService()
 	database := os.Getenv("MONGODB_DATABASE")
-	err := service.Connect(ctx, database)
+	store, err := daos.NewStore(ctx, database)
 	if err != nil {
 		log.Fatalln("Could not connect to database: ", err.Error())
 	}
 
-	err = service.Client.Ping(context.TODO(), readpref.Primary())
+	err = store.Service.Client.Ping(ctx, readpref.Primary())
 	if err != nil {
 		log.Fatalln("Could not ping database: ", err.Error())
 	}
 
 	fmt.Println("Successfully connected to database.")
-	defer service.Disconnect(ctx)
+	defer store.Close(context.Background())
 
-	router := api.SetupRouter()
+	err = migrations.Run(ctx, store.Service, migrations.All)
+	if err != nil {
+		log.Fatalln("Could not migrate database: ", err.Error())
+	}
 
-	log.Println("\nThe server is running and listening on localhost! 🚀")
-	err = http.ListenAndServe(":8080", router)
+	jobs.Start(ctx, store, jobs.DefaultWorkers)
+
+	// jobs left over by a previous run would stay pending or running forever otherwise
+	err = jobs.Resume(ctx, store)
 	if err != nil {
+		log.Println("Could not resume jobs: ", err.Error())
+	}
+
+	scheduler.NewScheduler(store).Start(ctx)
+
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: api.SetupRouter(store),
+	}
+
+	// the database connection must outlive the requests still being served
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		log.Println("Shutting down the server.")
+		err := server.Shutdown(context.Background())
+		if err != nil {
+			log.Println("Could not shut down the server: ", err.Error())
+		}
+	}()
+
+	log.Println("\nThe server is running and listening on localhost! 🚀")
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalln("The server encountered a fatal error: ", err.Error())
 	}
+	<-shutdown
 }